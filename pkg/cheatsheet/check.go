@@ -24,7 +24,7 @@ func Check() {
 		log.Fatalf("Error occured while checking if cheatsheets are up to date: %v", err)
 	}
 
-	generateAtDir(tmpDir)
+	generateAtDir(tmpDir, "")
 	defer os.RemoveAll(tmpDir)
 
 	actualContent := obtainContent(dir)
@@ -5,6 +5,10 @@
 //
 // To generate cheatsheet in english run:
 //   go run scripts/generate_cheatsheet.go
+//
+// To generate cheatsheets reflecting the keybindings in your own config file,
+// in the current working directory, run:
+//   go run scripts/cheatsheet/main.go generate path/to/config.yml
 
 package cheatsheet
 
@@ -34,11 +38,17 @@ func GetDir() string {
 	return integration.GetRootDirectory() + "/docs/keybindings"
 }
 
-func generateAtDir(cheatsheetDir string) {
+func generateAtDir(cheatsheetDir string, configPath string) {
 	os.Setenv("LANG", "en")
 
 	translationSetsByLang := i18n.GetTranslationSets()
 	mConfig := config.NewDummyAppConfig()
+	if configPath != "" {
+		mConfig.UserConfigPaths = []string{configPath}
+		if err := mConfig.ReloadUserConfig(); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	for lang := range translationSetsByLang {
 		os.Setenv("LC_ALL", lang)
@@ -58,7 +68,13 @@ func generateAtDir(cheatsheetDir string) {
 }
 
 func Generate() {
-	generateAtDir(GetDir())
+	generateAtDir(GetDir(), "")
+}
+
+// GenerateForConfig generates cheatsheets in the given directory which reflect
+// the keybindings of the given config file
+func GenerateForConfig(dir string, configPath string) {
+	generateAtDir(dir, configPath)
 }
 
 func writeString(file *os.File, str string) {
@@ -84,6 +100,7 @@ func localisedTitle(mApp *app.App, str string) string {
 		"remotes":        tr.RemotesTitle,
 		"reflogCommits":  tr.ReflogCommitsTitle,
 		"tags":           tr.TagsTitle,
+		"pullRequests":   tr.PullRequestsTitle,
 		"commitFiles":    tr.CommitFilesTitle,
 		"commitMessage":  tr.CommitMessageTitle,
 		"commits":        tr.CommitsTitle,
@@ -93,6 +110,7 @@ func localisedTitle(mApp *app.App, str string) string {
 		"main":           tr.MainTitle,
 		"patchBuilding":  tr.PatchBuildingTitle,
 		"merging":        tr.MergingTitle,
+		"mergeResult":    tr.MergeResultTitle,
 		"normal":         tr.NormalTitle,
 		"staging":        tr.StagingTitle,
 		"menu":           tr.MenuTitle,
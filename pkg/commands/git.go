@@ -37,6 +37,8 @@ type GitCommand struct {
 	Tag         *git_commands.TagCommands
 	WorkingTree *git_commands.WorkingTreeCommands
 	Bisect      *git_commands.BisectCommands
+	Rerere      *git_commands.RerereCommands
+	Snapshot    *git_commands.SnapshotCommands
 
 	Loaders Loaders
 }
@@ -50,6 +52,7 @@ type Loaders struct {
 	Remotes       *loaders.RemoteLoader
 	Stash         *loaders.StashLoader
 	Tags          *loaders.TagLoader
+	PullRequests  *loaders.PullRequestRefLoader
 }
 
 func NewGitCommand(
@@ -115,6 +118,8 @@ func NewGitCommandAux(
 	patchManager := patch.NewPatchManager(cmn.Log, workingTreeCommands.ApplyPatch, workingTreeCommands.ShowFileDiff)
 	patchCommands := git_commands.NewPatchCommands(gitCommon, rebaseCommands, commitCommands, statusCommands, stashCommands, patchManager)
 	bisectCommands := git_commands.NewBisectCommands(gitCommon)
+	rerereCommands := git_commands.NewRerereCommands(gitCommon)
+	snapshotCommands := git_commands.NewSnapshotCommands(gitCommon)
 
 	return &GitCommand{
 		Branch:      branchCommands,
@@ -132,6 +137,8 @@ func NewGitCommandAux(
 		Sync:        syncCommands,
 		Tag:         tagCommands,
 		Bisect:      bisectCommands,
+		Rerere:      rerereCommands,
+		Snapshot:    snapshotCommands,
 		WorkingTree: workingTreeCommands,
 		Loaders: Loaders{
 			Branches:      loaders.NewBranchLoader(cmn, branchCommands.GetRawBranches, branchCommands.CurrentBranchName, configCommands),
@@ -142,6 +149,7 @@ func NewGitCommandAux(
 			Remotes:       loaders.NewRemoteLoader(cmn, cmd, repo.Remotes),
 			Stash:         loaders.NewStashLoader(cmn, cmd),
 			Tags:          loaders.NewTagLoader(cmn, cmd),
+			PullRequests:  loaders.NewPullRequestRefLoader(cmn, cmd),
 		},
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"text/template"
 
 	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
 	"github.com/jesseduffield/lazygit/pkg/utils"
@@ -90,15 +91,38 @@ func (self *BranchCommands) Checkout(branch string, options CheckoutOptions) err
 // Currently it limits the result to 100 commits, but when we get async stuff
 // working we can do lazy loading
 func (self *BranchCommands) GetGraph(branchName string) (string, error) {
-	return self.GetGraphCmdObj(branchName).DontLog().RunWithOutput()
+	cmdObj, err := self.GetGraphCmdObj(branchName)
+	if err != nil {
+		return "", err
+	}
+	return cmdObj.DontLog().RunWithOutput()
+}
+
+func (self *BranchCommands) GetGraphCmdObj(branchName string) (oscommands.ICmdObj, error) {
+	cmdStr, err := utils.ResolveShellTemplate(
+		self.UserConfig.Git.BranchLogCmd,
+		map[string]string{"branchName": branchName},
+		branchLogCmdFuncs(branchName),
+		self.cmd.Quote,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return self.cmd.New(cmdStr).DontLog(), nil
 }
 
-func (self *BranchCommands) GetGraphCmdObj(branchName string) oscommands.ICmdObj {
-	branchLogCmdTemplate := self.UserConfig.Git.BranchLogCmd
-	templateValues := map[string]string{
-		"branchName": self.cmd.Quote(branchName),
+// the branch log command can refer to the branch as either {{branchName}} or
+// {{.branchName}}
+func branchLogCmdFuncs(branchName string) template.FuncMap {
+	return template.FuncMap{
+		"branchName": func() string { return branchName },
 	}
-	return self.cmd.New(utils.ResolvePlaceholderString(branchLogCmdTemplate, templateValues)).DontLog()
+}
+
+// ValidateBranchLogCmd checks git.branchLogCmd for syntax errors
+func ValidateBranchLogCmd(templateStr string) error {
+	_, err := utils.ParseShellTemplate(templateStr, branchLogCmdFuncs(""))
+	return err
 }
 
 func (self *BranchCommands) SetCurrentBranchUpstream(remoteName string, remoteBranchName string) error {
@@ -132,6 +156,13 @@ func (self *BranchCommands) GetCommitDifferences(from, to string) (string, strin
 	return strings.TrimSpace(pushableCount), strings.TrimSpace(pullableCount)
 }
 
+// MergeBase returns the best common ancestor of two refs, which is what a
+// pull request's diff starts from
+func (self *BranchCommands) MergeBase(ref1 string, ref2 string) (string, error) {
+	output, err := self.cmd.New(fmt.Sprintf("git merge-base %s %s", self.cmd.Quote(ref1), self.cmd.Quote(ref2))).DontLog().RunWithOutput()
+	return strings.TrimSpace(output), err
+}
+
 func (self *BranchCommands) IsHeadDetached() bool {
 	err := self.cmd.New("git symbolic-ref -q HEAD").DontLog().Run()
 	return err != nil
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-errors/errors"
 	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -153,6 +154,22 @@ func TestBranchGetBranchGraph(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestBranchGetBranchGraphEscapesBranchName(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).ExpectGitArgs([]string{
+		"log", "--oneline", "$(touch oops)", "--",
+	}, "", nil)
+	userConfig := config.GetDefaultConfig()
+	userConfig.Git.BranchLogCmd = "git log --oneline {{.branchName}} --"
+	instance := buildBranchCommands(commonDeps{runner: runner, userConfig: userConfig})
+	_, err := instance.GetGraph("$(touch oops)")
+	assert.NoError(t, err)
+}
+
+func TestValidateBranchLogCmd(t *testing.T) {
+	assert.NoError(t, ValidateBranchLogCmd(config.GetDefaultConfig().Git.BranchLogCmd))
+	assert.Error(t, ValidateBranchLogCmd("git log {{branchName --"))
+}
+
 func TestBranchGetAllBranchGraph(t *testing.T) {
 	runner := oscommands.NewFakeRunner(t).ExpectGitArgs([]string{
 		"log", "--graph", "--all", "--color=always", "--abbrev-commit", "--decorate", "--date=relative", "--pretty=medium",
@@ -67,6 +67,12 @@ func (self *CommitCommands) GetHeadCommitMessage() (string, error) {
 	return strings.TrimSpace(message), err
 }
 
+// GetSha returns the full SHA of the commit that the ref points to
+func (self *CommitCommands) GetSha(ref string) (string, error) {
+	sha, err := self.cmd.New("git rev-parse --verify " + self.cmd.Quote(ref+"^{commit}")).DontLog().RunWithOutput()
+	return strings.TrimSpace(sha), err
+}
+
 func (self *CommitCommands) GetCommitMessage(commitSha string) (string, error) {
 	cmdStr := "git rev-list --format=%B --max-count=1 " + commitSha
 	messageWithHeader, err := self.cmd.New(cmdStr).DontLog().RunWithOutput()
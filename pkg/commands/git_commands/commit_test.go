@@ -27,6 +27,17 @@ func TestCommitResetToCommit(t *testing.T) {
 	runner.CheckForMissingCalls()
 }
 
+func TestCommitGetSha(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"rev-parse", "--verify", "HEAD^{commit}"}, "abc123\n", nil)
+	instance := buildCommitCommands(commonDeps{runner: runner})
+
+	sha, err := instance.GetSha("HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", sha)
+	runner.CheckForMissingCalls()
+}
+
 func TestCommitCommitObj(t *testing.T) {
 	type scenario struct {
 		testName             string
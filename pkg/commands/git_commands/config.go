@@ -72,9 +72,27 @@ func (self *ConfigCommands) GetCoreEditor() string {
 	return self.gitConfig.Get("core.editor")
 }
 
-// GetRemoteURL returns current repo remote url
+// GetRemoteURL returns the URL of the remote we go by for the hosting service
 func (self *ConfigCommands) GetRemoteURL() string {
-	return self.gitConfig.Get("remote.origin.url")
+	return self.gitConfig.Get("remote." + self.GetHostingServiceRemote() + ".url")
+}
+
+// GetHostingServiceRemote returns the remote whose URL tells us the hosting
+// service, and which we fetch pull requests from. That's origin, unless the
+// repo has no origin but does have exactly one other remote.
+func (self *ConfigCommands) GetHostingServiceRemote() string {
+	if self.gitConfig.Get("remote.origin.url") != "" || self.repo == nil {
+		return "origin"
+	}
+
+	conf, err := self.repo.Config()
+	if err != nil || len(conf.Remotes) != 1 {
+		return "origin"
+	}
+	for name := range conf.Remotes {
+		return name
+	}
+	return "origin"
 }
 
 func (self *ConfigCommands) GetShowUntrackedFiles() string {
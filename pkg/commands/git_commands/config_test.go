@@ -0,0 +1,63 @@
+package git_commands
+
+import (
+	"testing"
+
+	gogit "github.com/jesseduffield/go-git/v5"
+	gogitconfig "github.com/jesseduffield/go-git/v5/config"
+	"github.com/jesseduffield/go-git/v5/storage/memory"
+	"github.com/jesseduffield/lazygit/pkg/commands/git_config"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigGetHostingServiceRemote(t *testing.T) {
+	type scenario struct {
+		testName       string
+		gitConfig      map[string]string
+		remoteNames    []string
+		expectedRemote string
+		expectedURL    string
+	}
+
+	scenarios := []scenario{
+		{
+			testName:       "origin",
+			gitConfig:      map[string]string{"remote.origin.url": "git@github.com:me/repo.git"},
+			remoteNames:    []string{"origin", "upstream"},
+			expectedRemote: "origin",
+			expectedURL:    "git@github.com:me/repo.git",
+		},
+		{
+			testName:       "only remote isn't origin",
+			gitConfig:      map[string]string{"remote.upstream.url": "git@github.com:them/repo.git"},
+			remoteNames:    []string{"upstream"},
+			expectedRemote: "upstream",
+			expectedURL:    "git@github.com:them/repo.git",
+		},
+		{
+			testName:       "several remotes, none of them origin",
+			gitConfig:      map[string]string{"remote.upstream.url": "git@github.com:them/repo.git"},
+			remoteNames:    []string{"upstream", "fork"},
+			expectedRemote: "origin",
+			expectedURL:    "",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			repo, err := gogit.Init(memory.NewStorage(), nil)
+			assert.NoError(t, err)
+			for _, name := range s.remoteNames {
+				_, err := repo.CreateRemote(&gogitconfig.RemoteConfig{Name: name, URLs: []string{"git@example.com:" + name + ".git"}})
+				assert.NoError(t, err)
+			}
+
+			instance := NewConfigCommands(utils.NewDummyCommon(), git_config.NewFakeGitConfig(s.gitConfig), repo)
+
+			assert.Equal(t, s.expectedRemote, instance.GetHostingServiceRemote())
+			assert.Equal(t, s.expectedURL, instance.GetRemoteURL())
+		})
+	}
+}
@@ -142,3 +142,21 @@ func buildBranchCommands(deps commonDeps) *BranchCommands {
 
 	return NewBranchCommands(gitCommon)
 }
+
+func buildRerereCommands(deps commonDeps) *RerereCommands {
+	gitCommon := buildGitCommon(deps)
+
+	return NewRerereCommands(gitCommon)
+}
+
+func buildSnapshotCommands(deps commonDeps) *SnapshotCommands {
+	gitCommon := buildGitCommon(deps)
+
+	return NewSnapshotCommands(gitCommon)
+}
+
+func buildRemoteCommands(deps commonDeps) *RemoteCommands {
+	gitCommon := buildGitCommon(deps)
+
+	return NewRemoteCommands(gitCommon)
+}
@@ -2,7 +2,7 @@ package git_commands
 
 import (
 	"io/ioutil"
-	"strconv"
+	"text/template"
 
 	"github.com/go-errors/errors"
 	"github.com/jesseduffield/lazygit/pkg/utils"
@@ -51,12 +51,37 @@ func (self *FileCommands) GetEditCmdStr(filename string, lineNumber int) (string
 		return "", errors.New("No editor defined in config file, $GIT_EDITOR, $VISUAL, $EDITOR, or git config")
 	}
 
-	templateValues := map[string]string{
-		"editor":   editor,
-		"filename": self.cmd.Quote(filename),
-		"line":     strconv.Itoa(lineNumber),
+	templateValues := map[string]interface{}{
+		"editor":   utils.RawShellValue(editor),
+		"filename": filename,
+		"line":     lineNumber,
 	}
 
-	editCmdTemplate := self.UserConfig.OS.EditCommandTemplate
-	return utils.ResolvePlaceholderString(editCmdTemplate, templateValues), nil
+	return utils.ResolveShellTemplate(
+		self.UserConfig.OS.EditCommandTemplate,
+		templateValues,
+		editCommandTemplateFuncs(templateValues),
+		self.cmd.Quote,
+	)
+}
+
+// the edit command template can refer to its values as either e.g.
+// {{filename}} or {{.filename}}
+func editCommandTemplateFuncs(templateValues map[string]interface{}) template.FuncMap {
+	funcs := template.FuncMap{}
+	for name, value := range templateValues {
+		value := value
+		funcs[name] = func() interface{} { return value }
+	}
+	return funcs
+}
+
+// ValidateEditCommandTemplate checks os.editCommandTemplate for syntax errors
+func ValidateEditCommandTemplate(templateStr string) error {
+	_, err := utils.ParseShellTemplate(templateStr, editCommandTemplateFuncs(map[string]interface{}{
+		"editor":   nil,
+		"filename": nil,
+		"line":     nil,
+	}))
+	return err
 }
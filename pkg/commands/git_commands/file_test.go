@@ -143,6 +143,33 @@ func TestEditFileCmdStr(t *testing.T) {
 				assert.Equal(t, `vim +1 "open file/at line"`, cmdStr)
 			},
 		},
+		{
+			filename:                  `it's "$(dangerous)"`,
+			configEditCommand:         "code --wait",
+			configEditCommandTemplate: `{{.editor}} --goto "{{.filename}}":{{.line}}`,
+			runner:                    oscommands.NewFakeRunner(t),
+			getenv: func(env string) string {
+				return ""
+			},
+			gitConfigMockResponses: nil,
+			test: func(cmdStr string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, `code --wait --goto "it's \"\$(dangerous)\"":1`, cmdStr)
+			},
+		},
+		{
+			filename:                  "test",
+			configEditCommand:         "vim",
+			configEditCommandTemplate: "{{editor}} {{filename",
+			runner:                    oscommands.NewFakeRunner(t),
+			getenv: func(env string) string {
+				return ""
+			},
+			gitConfigMockResponses: nil,
+			test: func(cmdStr string, err error) {
+				assert.Error(t, err)
+			},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -161,3 +188,10 @@ func TestEditFileCmdStr(t *testing.T) {
 		s.runner.CheckForMissingCalls()
 	}
 }
+
+func TestValidateEditCommandTemplate(t *testing.T) {
+	assert.NoError(t, ValidateEditCommandTemplate("{{editor}} +{{line}} {{filename}}"))
+	assert.NoError(t, ValidateEditCommandTemplate("{{.editor}} {{.filename}}"))
+	assert.Error(t, ValidateEditCommandTemplate("{{editor}} {{filename"))
+	assert.Error(t, ValidateEditCommandTemplate("{{editor}} {{nope}}"))
+}
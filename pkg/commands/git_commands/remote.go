@@ -2,6 +2,9 @@ package git_commands
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 type RemoteCommands struct {
@@ -56,3 +59,37 @@ func (self *RemoteCommands) CheckRemoteBranchExists(branchName string) bool {
 
 	return err == nil
 }
+
+// Credential asks git's credential helper for the username and password it has
+// for the given host, without prompting the user. This is how we authenticate
+// against a hosting service's API when the user hasn't configured a token.
+func (self *RemoteCommands) Credential(host string) (string, string, error) {
+	// turning off the terminal prompt isn't enough: git would still run an
+	// askpass program, and helpers like Git Credential Manager would pop up a
+	// window of their own, so we make the askpass program fail and tell helpers
+	// not to interact
+	cmdObj := self.cmd.New("git -c credential.interactive=false credential fill").
+		AddEnvVars("GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=false", "SSH_ASKPASS=false", "GCM_INTERACTIVE=never").
+		DontLog()
+	cmdObj.GetCmd().Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+
+	output, err := cmdObj.RunWithOutput()
+	if err != nil {
+		return "", "", err
+	}
+
+	username := ""
+	password := ""
+	for _, line := range utils.SplitLines(output) {
+		if strings.HasPrefix(line, "username=") {
+			username = strings.TrimPrefix(line, "username=")
+		} else if strings.HasPrefix(line, "password=") {
+			password = strings.TrimPrefix(line, "password=")
+		}
+	}
+
+	if password == "" {
+		return "", "", fmt.Errorf("git's credential helper has no password for %s", host)
+	}
+	return username, password, nil
+}
@@ -0,0 +1,58 @@
+package git_commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteCredential(t *testing.T) {
+	type scenario struct {
+		testName         string
+		output           string
+		outputErr        error
+		expectedUsername string
+		expectedPassword string
+		expectedErr      bool
+	}
+
+	scenarios := []scenario{
+		{
+			testName:         "helper has a password",
+			output:           "protocol=https\nhost=github.com\nusername=jesse\npassword=abc=123\n",
+			expectedUsername: "jesse",
+			expectedPassword: "abc=123",
+		},
+		{
+			testName:    "helper has no password",
+			output:      "protocol=https\nhost=github.com\n",
+			expectedErr: true,
+		},
+		{
+			testName:    "helper fails",
+			outputErr:   errors.New("terminal prompts disabled"),
+			expectedErr: true,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			runner := oscommands.NewFakeRunner(t).
+				ExpectGitArgs([]string{"-c", "credential.interactive=false", "credential", "fill"}, s.output, s.outputErr)
+			instance := buildRemoteCommands(commonDeps{runner: runner})
+
+			username, password, err := instance.Credential("github.com")
+			if s.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, s.expectedUsername, username)
+				assert.Equal(t, s.expectedPassword, password)
+			}
+			runner.CheckForMissingCalls()
+		})
+	}
+}
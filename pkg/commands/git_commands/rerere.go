@@ -0,0 +1,188 @@
+package git_commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// rerere ('reuse recorded resolution') records how you resolved each conflict
+// and resolves the same conflict that way next time it comes up.
+
+type RerereCommands struct {
+	*GitCommon
+}
+
+func NewRerereCommands(gitCommon *GitCommon) *RerereCommands {
+	return &RerereCommands{
+		GitCommon: gitCommon,
+	}
+}
+
+func (self *RerereCommands) cacheDir() string {
+	return filepath.Join(self.dotGitDir, "rr-cache")
+}
+
+// Enabled tells us whether rerere is on. We don't go through our cached git
+// config because the user can turn rerere on from within lazygit. If
+// rerere.enabled isn't set, git turns rerere on when the rr-cache directory
+// exists.
+func (self *RerereCommands) Enabled() bool {
+	value, err := self.cmd.New("git config --get rerere.enabled").DontLog().RunWithOutput()
+	if err == nil && strings.TrimSpace(value) != "" {
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "true", "yes", "on", "1":
+			return true
+		default:
+			return false
+		}
+	}
+
+	exists, err := self.os.FileExists(self.cacheDir())
+	return err == nil && exists
+}
+
+// SetEnabled sets rerere.enabled in the repo's config
+func (self *RerereCommands) SetEnabled(enabled bool) error {
+	value := "false"
+	if enabled {
+		value = "true"
+	}
+	return self.cmd.New("git config rerere.enabled " + value).Run()
+}
+
+// MergeRRResolvedPaths returns the paths in MERGE_RR, where git keeps track of
+// the conflicts rerere is handling, which have a recorded resolution. Paths
+// whose conflicts rerere has only just seen don't have one yet.
+func (self *RerereCommands) MergeRRResolvedPaths() ([]string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(self.dotGitDir, "MERGE_RR"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	paths := []string{}
+	// each entry is '<id>\t<path>', where the id may have a '.<variant>' suffix
+	// for when the same conflict has been resolved in more than one way
+	for _, entry := range strings.Split(string(content), "\x00") {
+		split := strings.SplitN(entry, "\t", 2)
+		if len(split) != 2 {
+			continue
+		}
+
+		id, variant := split[0], ""
+		if dot := strings.Index(id, "."); dot != -1 {
+			id, variant = id[:dot], id[dot:]
+		}
+
+		exists, err := self.os.FileExists(filepath.Join(self.cacheDir(), id, "postimage"+variant))
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			paths = append(paths, split[1])
+		}
+	}
+	return paths, nil
+}
+
+var rerereResolvedRegex = regexp.MustCompile(`(?m)^(?:Resolved|Staged) '(.+)' using previous resolution\.?$`)
+
+// ParseRerereResolvedPaths returns the paths which the output of a merge,
+// rebase, cherry-pick etc. says rerere resolved
+func ParseRerereResolvedPaths(output string) []string {
+	paths := []string{}
+	for _, match := range rerereResolvedRegex.FindAllStringSubmatch(output, -1) {
+		paths = append(paths, match[1])
+	}
+	return paths
+}
+
+// Forget throws away the recorded resolution of the conflict in the given path
+// and brings back the conflict markers so that it can be resolved again. This
+// works even if the path has since been staged, because git remembers the
+// conflict when you stage a conflicted file.
+func (self *RerereCommands) Forget(path string) error {
+	quotedPath := self.cmd.Quote(path)
+	if err := self.cmd.New("git rerere forget -- " + quotedPath).Run(); err != nil {
+		return err
+	}
+	return self.cmd.New("git checkout -m -- " + quotedPath).Run()
+}
+
+// RecordedResolutions returns the contents of the rr-cache, newest first
+func (self *RerereCommands) RecordedResolutions() ([]*models.RecordedResolution, error) {
+	entries, err := ioutil.ReadDir(self.cacheDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*models.RecordedResolution{}, nil
+		}
+		return nil, err
+	}
+
+	resolutions := []*models.RecordedResolution{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(self.cacheDir(), entry.Name())
+		resolution := &models.RecordedResolution{
+			ID:         entry.Name(),
+			RecordedAt: entry.ModTime(),
+		}
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if strings.HasPrefix(file.Name(), "postimage") {
+				resolution.Resolved = true
+			}
+			if strings.HasPrefix(file.Name(), "preimage") && resolution.Preview == "" {
+				resolution.Preview = conflictPreview(filepath.Join(dir, file.Name()))
+			}
+		}
+
+		resolutions = append(resolutions, resolution)
+	}
+
+	sort.SliceStable(resolutions, func(i, j int) bool {
+		return resolutions[i].RecordedAt.After(resolutions[j].RecordedAt)
+	})
+
+	return resolutions, nil
+}
+
+// conflictPreview returns the first line within the conflict markers of the
+// given preimage, to help tell one recorded resolution from another
+func conflictPreview(path string) string {
+	preview := ""
+	inConflict := false
+	_ = utils.ForEachLineInFile(path, func(line string, _ int) {
+		line = strings.TrimSpace(line)
+		switch {
+		case preview != "":
+		case strings.HasPrefix(line, "<<<<<<<"):
+			inConflict = true
+		case inConflict && line != "" && !strings.HasPrefix(line, "=======") && !strings.HasPrefix(line, ">>>>>>>"):
+			preview = line
+		}
+	})
+	return preview
+}
+
+// DeleteRecordedResolution removes a recorded resolution from the rr-cache, so
+// that its conflict won't be resolved automatically again
+func (self *RerereCommands) DeleteRecordedResolution(id string) error {
+	return os.RemoveAll(filepath.Join(self.cacheDir(), id))
+}
@@ -0,0 +1,68 @@
+package git_commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRerereMergeRRResolvedPaths(t *testing.T) {
+	dotGitDir, err := ioutil.TempDir("", "lazygit-rerere")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dotGitDir)
+
+	// a.txt has a recorded resolution, b.txt is a conflict rerere hasn't seen
+	// before and 'c d.txt' has a recorded resolution under its second variant
+	for _, file := range []string{"rr-cache/aaa/postimage", "rr-cache/bbb/preimage", "rr-cache/ccc/postimage.1"} {
+		path := filepath.Join(dotGitDir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte{}, 0644))
+	}
+	mergeRR := "aaa\ta.txt\x00bbb\tb.txt\x00ccc.1\tc d.txt\x00"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dotGitDir, "MERGE_RR"), []byte(mergeRR), 0644))
+
+	instance := buildRerereCommands(commonDeps{dotGitDir: dotGitDir})
+
+	resolved, err := instance.MergeRRResolvedPaths()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"a.txt", "c d.txt"}, resolved)
+}
+
+func TestRerereMergeRRResolvedPathsWithoutMergeRR(t *testing.T) {
+	dotGitDir, err := ioutil.TempDir("", "lazygit-rerere")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dotGitDir)
+
+	instance := buildRerereCommands(commonDeps{dotGitDir: dotGitDir})
+
+	resolved, err := instance.MergeRRResolvedPaths()
+	assert.NoError(t, err)
+	assert.Len(t, resolved, 0)
+}
+
+func TestRerereForget(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"rerere", "forget", "--", "a.txt"}, "", nil).
+		ExpectGitArgs([]string{"checkout", "-m", "--", "a.txt"}, "", nil)
+	instance := buildRerereCommands(commonDeps{runner: runner})
+
+	assert.NoError(t, instance.Forget("a.txt"))
+	runner.CheckForMissingCalls()
+}
+
+func TestParseRerereResolvedPaths(t *testing.T) {
+	output := `Auto-merging a.txt
+CONFLICT (content): Merge conflict in a.txt
+Auto-merging dir/b c.txt
+CONFLICT (content): Merge conflict in dir/b c.txt
+Resolved 'a.txt' using previous resolution.
+Staged 'dir/b c.txt' using previous resolution.
+Automatic merge failed; fix conflicts and then commit the result.`
+
+	assert.EqualValues(t, []string{"a.txt", "dir/b c.txt"}, ParseRerereResolvedPaths(output))
+	assert.EqualValues(t, []string{}, ParseRerereResolvedPaths("CONFLICT (content): Merge conflict in a.txt"))
+}
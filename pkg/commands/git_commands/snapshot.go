@@ -0,0 +1,361 @@
+package git_commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// Some of what lazygit does can't be undone through the reflog: deleting a
+// branch, staging, and discarding changes. Before doing any of those we take a
+// snapshot of whatever they'll change, so that we can put it back.
+//
+// A working tree snapshot is a stash-like commit: its tree is the working tree
+// (untracked files included) and its second parent is a commit of the index.
+// Nothing refers to it, so git will garbage collect it in due course.
+
+type SnapshotCommands struct {
+	*GitCommon
+}
+
+func NewSnapshotCommands(gitCommon *GitCommon) *SnapshotCommands {
+	return &SnapshotCommands{
+		GitCommon: gitCommon,
+	}
+}
+
+// IndexTree writes the index to a tree and returns the tree's SHA. This fails if
+// the index has unmerged paths.
+func (self *SnapshotCommands) IndexTree() (string, error) {
+	output, err := self.cmd.New("git write-tree").DontLog().RunWithOutput()
+	return strings.TrimSpace(output), err
+}
+
+// RestoreIndex replaces the index with the given tree
+func (self *SnapshotCommands) RestoreIndex(tree string) error {
+	return self.cmd.New("git read-tree " + tree).Run()
+}
+
+// WorkingTree takes a snapshot of the index and the working tree and returns
+// the SHA of the resulting commit. Only the given paths are read from the
+// working tree; everything else is snapshotted as it is in the index.
+func (self *SnapshotCommands) WorkingTree(paths []string) (string, error) {
+	indexTree, err := self.IndexTree()
+	if err != nil {
+		return "", err
+	}
+
+	base, err := self.snapshotBase()
+	if err != nil {
+		return "", err
+	}
+
+	indexCommit, err := self.cmd.New(
+		fmt.Sprintf("git commit-tree %s -p %s -m %s", indexTree, base, self.cmd.Quote("lazygit snapshot of index")),
+	).DontLog().RunWithOutput()
+	if err != nil {
+		return "", err
+	}
+
+	workingTree, err := self.WorkingTreeTree(paths)
+	if err != nil {
+		return "", err
+	}
+
+	snapshot, err := self.cmd.New(
+		fmt.Sprintf("git commit-tree %s -p %s -p %s -m %s", workingTree, base, strings.TrimSpace(indexCommit), self.cmd.Quote("lazygit snapshot of working tree")),
+	).DontLog().RunWithOutput()
+	return strings.TrimSpace(snapshot), err
+}
+
+// snapshotBase returns the commit that a working tree snapshot's first parent
+// is, which is HEAD unless there are no commits yet. In that case we make an
+// empty commit to stand in for it, so that the snapshot of the index is still
+// its second parent.
+func (self *SnapshotCommands) snapshotBase() (string, error) {
+	if err := self.cmd.New("git rev-parse --verify --quiet HEAD").DontLog().Run(); err == nil {
+		return "HEAD", nil
+	}
+
+	cmdObj := self.cmd.New("git hash-object -w -t tree --stdin").DontLog()
+	cmdObj.GetCmd().Stdin = strings.NewReader("")
+	emptyTree, err := cmdObj.RunWithOutput()
+	if err != nil {
+		return "", err
+	}
+
+	emptyCommit, err := self.cmd.New(
+		fmt.Sprintf("git commit-tree %s -m %s", strings.TrimSpace(emptyTree), self.cmd.Quote("lazygit snapshot base")),
+	).DontLog().RunWithOutput()
+	return strings.TrimSpace(emptyCommit), err
+}
+
+// WorkingTreeTree adds the given paths in the working tree to a copy of the
+// index, so as to leave the real one alone, and writes that to a tree. Unlike a
+// full snapshot, this works when there are merge conflicts.
+func (self *SnapshotCommands) WorkingTreeTree(paths []string) (string, error) {
+	tempIndex, err := ioutil.TempFile("", "lazygit-index")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tempIndex.Name())
+
+	// starting from the real index means git only has to look at the files
+	// which have changed since it was last refreshed
+	content, err := ioutil.ReadFile(filepath.Join(self.dotGitDir, "index"))
+	if err == nil {
+		_, err = tempIndex.Write(content)
+	} else if os.IsNotExist(err) {
+		err = nil
+	}
+	if closeErr := tempIndex.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	indexEnvVar := "GIT_INDEX_FILE=" + tempIndex.Name()
+	paths, err = self.pathsKnownToIndex(paths, indexEnvVar)
+	if err != nil {
+		return "", err
+	}
+
+	if len(paths) > 0 {
+		if err := self.cmd.New("git add -A -- " + self.quotePaths(paths)).AddEnvVars(indexEnvVar).DontLog().Run(); err != nil {
+			return "", err
+		}
+	}
+
+	output, err := self.cmd.New("git write-tree").AddEnvVars(indexEnvVar).DontLog().RunWithOutput()
+	return strings.TrimSpace(output), err
+}
+
+// pathsKnownToIndex drops the paths which neither exist in the working tree nor
+// are in the given index, e.g. an untracked file that's since been discarded,
+// because git add fails on a pathspec that matches nothing
+func (self *SnapshotCommands) pathsKnownToIndex(paths []string, indexEnvVar string) ([]string, error) {
+	result := make([]string, 0, len(paths))
+	for _, path := range paths {
+		exists, err := self.os.FileExists(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			output, err := self.cmd.New("git ls-files -- " + self.cmd.Quote(path)).AddEnvVars(indexEnvVar).DontLog().RunWithOutput()
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(output) == "" {
+				continue
+			}
+		}
+
+		result = append(result, path)
+	}
+	return result, nil
+}
+
+// WorkingTreeChanges returns the files among the given paths whose contents in
+// the working tree differ from a snapshot, which may be a tree or a snapshot
+// commit
+func (self *SnapshotCommands) WorkingTreeChanges(snapshot string, paths []string) ([]string, error) {
+	current, err := self.WorkingTreeTree(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := self.cmd.New(fmt.Sprintf("git diff --name-only %s %s -- %s", snapshot, current, self.quotePaths(paths))).DontLog().RunWithOutput()
+	if err != nil {
+		return nil, err
+	}
+	return utils.SplitLines(output), nil
+}
+
+// RestoreWorkingTree puts the given paths in the index and the working tree
+// back the way they were in a snapshot. Paths may be directories.
+func (self *SnapshotCommands) RestoreWorkingTree(snapshot string, paths []string) error {
+	pathArgs := self.quotePaths(paths)
+
+	snapshotOutput, err := self.cmd.New(fmt.Sprintf("git ls-tree -r --name-only %s -- %s", snapshot, pathArgs)).DontLog().RunWithOutput()
+	if err != nil {
+		return err
+	}
+	snapshotFiles := utils.SplitLines(snapshotOutput)
+
+	currentOutput, err := self.cmd.New("git ls-files -co --exclude-standard -- " + pathArgs).DontLog().RunWithOutput()
+	if err != nil {
+		return err
+	}
+
+	// files that didn't exist when we took the snapshot mustn't exist afterwards
+	for _, file := range utils.SplitLines(currentOutput) {
+		if utils.IncludesString(snapshotFiles, file) {
+			continue
+		}
+		if err := self.os.Remove(file); err != nil {
+			return err
+		}
+	}
+
+	if len(snapshotFiles) > 0 {
+		if err := self.cmd.New(fmt.Sprintf("git checkout %s -- %s", snapshot, self.quotePaths(snapshotFiles))).Run(); err != nil {
+			return err
+		}
+	}
+
+	// checking out the files staged them, so now we put back the index
+	return self.cmd.New(fmt.Sprintf("git reset -q %s^2 -- %s", snapshot, pathArgs)).Run()
+}
+
+// BranchTip returns the SHA that a branch points to, along with its upstream
+// if it has one
+func (self *SnapshotCommands) BranchTip(branchName string) (string, string, error) {
+	sha, err := self.cmd.New("git rev-parse --verify " + self.cmd.Quote("refs/heads/"+branchName)).DontLog().RunWithOutput()
+	if err != nil {
+		return "", "", err
+	}
+
+	upstream, err := self.cmd.New(fmt.Sprintf("git rev-parse --abbrev-ref %s", self.cmd.Quote(branchName+"@{u}"))).DontLog().RunWithOutput()
+	if err != nil {
+		upstream = ""
+	}
+
+	return strings.TrimSpace(sha), strings.TrimSpace(upstream), nil
+}
+
+// RestoreBranch recreates a deleted branch
+func (self *SnapshotCommands) RestoreBranch(branchName string, sha string, upstream string) error {
+	if err := self.cmd.New(fmt.Sprintf("git branch %s %s", self.cmd.Quote(branchName), sha)).Run(); err != nil {
+		return err
+	}
+
+	if upstream == "" {
+		return nil
+	}
+
+	return self.cmd.New(fmt.Sprintf("git branch --set-upstream-to=%s %s", self.cmd.Quote(upstream), self.cmd.Quote(branchName))).Run()
+}
+
+func (self *SnapshotCommands) quotePaths(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = self.cmd.Quote(path)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// RebaseSnapshot is what we need to put an interactive rebase back the way it
+// was
+type RebaseSnapshot struct {
+	Head string
+	// the contents of each file in the rebase-merge directory, e.g. the todo
+	// file, keyed by name
+	Files map[string]string
+	// a working tree snapshot, if we took one
+	WorkingTree string
+}
+
+// SameRebaseState tells us whether two snapshots have the same HEAD and
+// rebase-merge directory. We don't compare working tree snapshots, because
+// two snapshots of the same working tree needn't be the same commit.
+func (self *RebaseSnapshot) SameRebaseState(other *RebaseSnapshot) bool {
+	if self.Head != other.Head || len(self.Files) != len(other.Files) {
+		return false
+	}
+
+	for name, content := range self.Files {
+		otherContent, ok := other.Files[name]
+		if !ok || otherContent != content {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (self *SnapshotCommands) rebaseMergeDir() string {
+	return filepath.Join(self.dotGitDir, "rebase-merge")
+}
+
+// Rebase takes a snapshot of the interactive rebase in progress, along with the
+// working tree if the next step might change it
+func (self *SnapshotCommands) Rebase(includeWorkingTree bool) (*RebaseSnapshot, error) {
+	head, err := self.cmd.New("git rev-parse HEAD").DontLog().RunWithOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(self.rebaseMergeDir())
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(filepath.Join(self.rebaseMergeDir(), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = string(content)
+	}
+
+	snapshot := &RebaseSnapshot{Head: strings.TrimSpace(head), Files: files}
+	if includeWorkingTree {
+		if snapshot.WorkingTree, err = self.WorkingTree([]string{"."}); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// RestoreRebase puts the rebase in progress back the way it was in a snapshot
+func (self *SnapshotCommands) RestoreRebase(snapshot *RebaseSnapshot) error {
+	if snapshot.WorkingTree != "" {
+		// we don't want this reset to look like an action the user took when we
+		// go through the reflog
+		if err := self.cmd.New("git reset --hard " + snapshot.Head).AddEnvVars("GIT_REFLOG_ACTION=[lazygit rebase undo]").Run(); err != nil {
+			return err
+		}
+
+		if err := self.RestoreWorkingTree(snapshot.WorkingTree, []string{"."}); err != nil {
+			return err
+		}
+	}
+
+	dir := self.rebaseMergeDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, ok := snapshot.Files[entry.Name()]; ok || entry.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	for name, content := range snapshot.Files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
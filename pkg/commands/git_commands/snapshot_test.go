@@ -0,0 +1,283 @@
+package git_commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotWorkingTree(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"write-tree"}, "indextree\n", nil).
+		ExpectGitArgs([]string{"rev-parse", "--verify", "--quiet", "HEAD"}, "headsha\n", nil).
+		ExpectGitArgs([]string{"commit-tree", "indextree", "-p", "HEAD", "-m", "lazygit snapshot of index"}, "indexcommit\n", nil).
+		ExpectGitArgs([]string{"ls-files", "--", "dir"}, "dir/a.txt\n", nil).
+		ExpectGitArgs([]string{"add", "-A", "--", "dir"}, "", nil).
+		ExpectGitArgs([]string{"write-tree"}, "worktree\n", nil).
+		ExpectGitArgs([]string{"commit-tree", "worktree", "-p", "HEAD", "-p", "indexcommit", "-m", "lazygit snapshot of working tree"}, "snapshot\n", nil)
+	instance := buildSnapshotCommands(commonDeps{runner: runner})
+
+	snapshot, err := instance.WorkingTree([]string{"dir"})
+	assert.NoError(t, err)
+	assert.Equal(t, "snapshot", snapshot)
+	runner.CheckForMissingCalls()
+}
+
+func TestSnapshotRestoreWorkingTree(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"ls-tree", "-r", "--name-only", "snapshot", "--", "dir"}, "dir/a.txt\ndir/b.txt\n", nil).
+		ExpectGitArgs([]string{"ls-files", "-co", "--exclude-standard", "--", "dir"}, "dir/a.txt\n", nil).
+		ExpectGitArgs([]string{"checkout", "snapshot", "--", "dir/a.txt", "dir/b.txt"}, "", nil).
+		ExpectGitArgs([]string{"reset", "-q", "snapshot^2", "--", "dir"}, "", nil)
+	instance := buildSnapshotCommands(commonDeps{runner: runner})
+
+	assert.NoError(t, instance.RestoreWorkingTree("snapshot", []string{"dir"}))
+	runner.CheckForMissingCalls()
+}
+
+func TestSnapshotWorkingTreeChanges(t *testing.T) {
+	type scenario struct {
+		testName        string
+		diffOutput      string
+		expectedChanges []string
+	}
+
+	scenarios := []scenario{
+		{
+			testName:        "unchanged",
+			diffOutput:      "",
+			expectedChanges: []string{},
+		},
+		{
+			testName:        "changed",
+			diffOutput:      "dir/a.txt\ndir/b.txt\n",
+			expectedChanges: []string{"dir/a.txt", "dir/b.txt"},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			runner := oscommands.NewFakeRunner(t).
+				ExpectGitArgs([]string{"ls-files", "--", "dir"}, "dir/a.txt\n", nil).
+				ExpectGitArgs([]string{"add", "-A", "--", "dir"}, "", nil).
+				ExpectGitArgs([]string{"write-tree"}, "worktree\n", nil).
+				ExpectGitArgs([]string{"diff", "--name-only", "snapshot", "worktree", "--", "dir"}, s.diffOutput, nil)
+			instance := buildSnapshotCommands(commonDeps{runner: runner})
+
+			changes, err := instance.WorkingTreeChanges("snapshot", []string{"dir"})
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedChanges, changes)
+			runner.CheckForMissingCalls()
+		})
+	}
+}
+
+func TestSnapshotWorkingTreeTreeSkipsUnknownPaths(t *testing.T) {
+	// neither in the working tree nor in the index, e.g. a discarded untracked
+	// file, so git add would fail on it
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"ls-files", "--", "gone.txt"}, "", nil).
+		ExpectGitArgs([]string{"write-tree"}, "worktree\n", nil)
+	instance := buildSnapshotCommands(commonDeps{runner: runner})
+
+	tree, err := instance.WorkingTreeTree([]string{"gone.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, "worktree", tree)
+	runner.CheckForMissingCalls()
+}
+
+func TestSnapshotBranchTip(t *testing.T) {
+	type scenario struct {
+		testName         string
+		runner           *oscommands.FakeCmdObjRunner
+		expectedSha      string
+		expectedUpstream string
+	}
+
+	scenarios := []scenario{
+		{
+			testName: "with upstream",
+			runner: oscommands.NewFakeRunner(t).
+				ExpectGitArgs([]string{"rev-parse", "--verify", "refs/heads/feature"}, "abc\n", nil).
+				ExpectGitArgs([]string{"rev-parse", "--abbrev-ref", "feature@{u}"}, "origin/feature\n", nil),
+			expectedSha:      "abc",
+			expectedUpstream: "origin/feature",
+		},
+		{
+			testName: "without upstream",
+			runner: oscommands.NewFakeRunner(t).
+				ExpectGitArgs([]string{"rev-parse", "--verify", "refs/heads/feature"}, "abc\n", nil).
+				ExpectGitArgs([]string{"rev-parse", "--abbrev-ref", "feature@{u}"}, "", errors.New("no upstream")),
+			expectedSha:      "abc",
+			expectedUpstream: "",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			instance := buildSnapshotCommands(commonDeps{runner: s.runner})
+
+			sha, upstream, err := instance.BranchTip("feature")
+			assert.NoError(t, err)
+			assert.Equal(t, s.expectedSha, sha)
+			assert.Equal(t, s.expectedUpstream, upstream)
+			s.runner.CheckForMissingCalls()
+		})
+	}
+}
+
+func TestSnapshotRestoreBranch(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"branch", "feature", "abc"}, "", nil).
+		ExpectGitArgs([]string{"branch", "--set-upstream-to=origin/feature", "feature"}, "", nil)
+	instance := buildSnapshotCommands(commonDeps{runner: runner})
+
+	assert.NoError(t, instance.RestoreBranch("feature", "abc", "origin/feature"))
+	runner.CheckForMissingCalls()
+}
+
+func TestSnapshotRebase(t *testing.T) {
+	dotGitDir, err := ioutil.TempDir("", "lazygit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dotGitDir)
+
+	rebaseMergeDir := filepath.Join(dotGitDir, "rebase-merge")
+	assert.NoError(t, os.MkdirAll(rebaseMergeDir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(rebaseMergeDir, "git-rebase-todo"), []byte("pick abc one\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(rebaseMergeDir, "msgnum"), []byte("1\n"), 0644))
+
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"rev-parse", "HEAD"}, "head\n", nil)
+	instance := buildSnapshotCommands(commonDeps{runner: runner, dotGitDir: dotGitDir})
+
+	snapshot, err := instance.Rebase(false)
+	assert.NoError(t, err)
+	assert.Equal(t, &RebaseSnapshot{
+		Head:  "head",
+		Files: map[string]string{"git-rebase-todo": "pick abc one\n", "msgnum": "1\n"},
+	}, snapshot)
+	runner.CheckForMissingCalls()
+
+	// the rebase moves on a step
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(rebaseMergeDir, "git-rebase-todo"), []byte(""), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(rebaseMergeDir, "done"), []byte("pick abc one\n"), 0644))
+
+	assert.NoError(t, instance.RestoreRebase(snapshot))
+
+	entries, err := ioutil.ReadDir(rebaseMergeDir)
+	assert.NoError(t, err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"git-rebase-todo", "msgnum"}, names)
+
+	content, err := ioutil.ReadFile(filepath.Join(rebaseMergeDir, "git-rebase-todo"))
+	assert.NoError(t, err)
+	assert.Equal(t, "pick abc one\n", string(content))
+}
+
+func TestSameRebaseState(t *testing.T) {
+	snapshot := &RebaseSnapshot{Head: "head", Files: map[string]string{"git-rebase-todo": "pick abc one\n"}}
+
+	assert.True(t, snapshot.SameRebaseState(&RebaseSnapshot{
+		Head:        "head",
+		Files:       map[string]string{"git-rebase-todo": "pick abc one\n"},
+		WorkingTree: "snapshot",
+	}))
+	assert.False(t, snapshot.SameRebaseState(&RebaseSnapshot{
+		Head:  "other",
+		Files: map[string]string{"git-rebase-todo": "pick abc one\n"},
+	}))
+	assert.False(t, snapshot.SameRebaseState(&RebaseSnapshot{
+		Head:  "head",
+		Files: map[string]string{"git-rebase-todo": "drop abc one\n"},
+	}))
+	assert.False(t, snapshot.SameRebaseState(&RebaseSnapshot{
+		Head:  "head",
+		Files: map[string]string{"git-rebase-todo": "pick abc one\n", "stopped-sha": "abc\n"},
+	}))
+}
+
+// this one runs real git commands, because what we're testing is how git treats
+// paths that no longer exist, and repos without any commits
+func TestSnapshotUndoDiscardingUntrackedFile(t *testing.T) {
+	for _, withCommit := range []bool{true, false} {
+		withCommit := withCommit
+		t.Run(fmt.Sprintf("with commit: %t", withCommit), func(t *testing.T) {
+			testSnapshotUndoDiscardingUntrackedFile(t, withCommit)
+		})
+	}
+}
+
+func testSnapshotUndoDiscardingUntrackedFile(t *testing.T, withCommit bool) {
+	repoDir, err := ioutil.TempDir("", "lazygit-snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repoDir)
+
+	cwd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(repoDir))
+	defer func() { _ = os.Chdir(cwd) }()
+
+	osCommand := oscommands.NewOSCommand(utils.NewDummyCommon(), oscommands.GetPlatform(), oscommands.NewNullGuiIO(utils.NewDummyLog()))
+	setupCmds := []string{
+		"git init -q",
+		"git config user.name lazygit",
+		"git config user.email lazygit@example.com",
+	}
+	if withCommit {
+		setupCmds = append(setupCmds, "git commit -q --allow-empty -m initial")
+	}
+	for _, cmdStr := range setupCmds {
+		assert.NoError(t, osCommand.Cmd.New(cmdStr).Run())
+	}
+
+	assert.NoError(t, os.MkdirAll("dir", 0755))
+	assert.NoError(t, ioutil.WriteFile("u.txt", []byte("untracked\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join("dir", "d.txt"), []byte("untracked too\n"), 0644))
+
+	instance := buildSnapshotCommands(commonDeps{cmd: osCommand.Cmd})
+
+	for _, path := range []string{"u.txt", "dir"} {
+		before, err := instance.WorkingTree([]string{path})
+		assert.NoError(t, err)
+
+		assert.NoError(t, os.RemoveAll(path))
+
+		after, err := instance.WorkingTree([]string{path})
+		assert.NoError(t, err)
+
+		changes, err := instance.WorkingTreeChanges(after, []string{path})
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+
+		assert.NoError(t, instance.RestoreWorkingTree(before, []string{path}))
+
+		changes, err = instance.WorkingTreeChanges(before, []string{path})
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	}
+
+	content, err := ioutil.ReadFile("u.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "untracked\n", string(content))
+
+	content, err = ioutil.ReadFile(filepath.Join("dir", "d.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "untracked too\n", string(content))
+
+	// the files should be untracked again, rather than staged
+	status, err := osCommand.Cmd.New("git status --porcelain").RunWithOutput()
+	assert.NoError(t, err)
+	assert.Equal(t, "?? dir/\n?? u.txt\n", status)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/jesseduffield/lazygit/pkg/commands/loaders"
 	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 type StashCommands struct {
@@ -37,6 +38,20 @@ func (self *StashCommands) Apply(index int) error {
 	return self.cmd.New(fmt.Sprintf("git stash apply stash@{%d}", index)).Run()
 }
 
+// Shas returns the commit SHAs of the stash entries, most recent first
+func (self *StashCommands) Shas() ([]string, error) {
+	output, err := self.cmd.New("git stash list --format=%H").DontLog().RunWithOutput()
+	if err != nil {
+		return nil, err
+	}
+	return utils.SplitLines(output), nil
+}
+
+// Store adds a stash commit, e.g. one that was dropped, to the stash list
+func (self *StashCommands) Store(sha string, message string) error {
+	return self.cmd.New(fmt.Sprintf("git stash store -m %s %s", self.cmd.Quote(message), sha)).Run()
+}
+
 // Save save stash
 // TODO: before calling this, check if there is anything to save
 func (self *StashCommands) Save(message string) error {
@@ -79,3 +79,23 @@ func TestStashStashEntryCmdObj(t *testing.T) {
 		})
 	}
 }
+
+func TestStashShas(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"stash", "list", "--format=%H"}, "abc\ndef\n", nil)
+	instance := buildStashCommands(commonDeps{runner: runner})
+
+	shas, err := instance.Shas()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"abc", "def"}, shas)
+	runner.CheckForMissingCalls()
+}
+
+func TestStashStore(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		ExpectGitArgs([]string{"stash", "store", "-m", "On master: foo", "abc"}, "", nil)
+	instance := buildStashCommands(commonDeps{runner: runner})
+
+	assert.NoError(t, instance.Store("abc", "On master: foo"))
+	runner.CheckForMissingCalls()
+}
@@ -1,6 +1,7 @@
 package git_commands
 
 import (
+	"os"
 	"path/filepath"
 
 	gogit "github.com/jesseduffield/go-git/v5"
@@ -59,3 +60,19 @@ func (self *StatusCommands) IsBareRepo() bool {
 	_, err := self.repo.Worktree()
 	return err == gogit.ErrIsBareRepository
 }
+
+// RepoPaths returns the absolute paths of the repo's root directory and its git
+// directory. We navigate to the root directory on startup, so it's where we are.
+func (self *StatusCommands) RepoPaths() (string, string, error) {
+	repoRoot, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+
+	gitDir := self.dotGitDir
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoRoot, gitDir)
+	}
+
+	return repoRoot, gitDir, nil
+}
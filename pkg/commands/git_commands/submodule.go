@@ -154,6 +154,12 @@ func (self *SubmoduleCommands) Update(path string) error {
 	return self.cmd.New("git submodule update --init -- " + self.cmd.Quote(path)).Run()
 }
 
+// ResolveConflictWithCommit resolves a conflicted submodule by pointing it at
+// the given commit in the index. The submodule's own checkout is left alone.
+func (self *SubmoduleCommands) ResolveConflictWithCommit(path string, sha string) error {
+	return self.cmd.New("git update-index --cacheinfo " + self.cmd.Quote(fmt.Sprintf("160000,%s,%s", sha, path))).Run()
+}
+
 func (self *SubmoduleCommands) BulkInitCmdObj() oscommands.ICmdObj {
 	return self.cmd.New("git submodule init")
 }
@@ -86,6 +86,48 @@ func (self *SyncCommands) Fetch(opts FetchOptions) error {
 	return cmdObj.Run()
 }
 
+// PullRequestRef returns the ref in which a hosting service keeps the head of
+// a pull request, given the namespace of such refs, e.g. refs/pull/5/head. We
+// fetch the head into the same ref locally.
+func PullRequestRef(namespace string, number int) string {
+	return fmt.Sprintf("refs/%s/%d/head", namespace, number)
+}
+
+type FetchPullRequestsOptions struct {
+	Background bool
+	RemoteName string
+	// e.g. 'pull' for GitHub; see PullRequestRef
+	Namespace string
+	// the pull requests to fetch the heads of, or all of them if this is empty
+	Numbers []int
+}
+
+// FetchPullRequests fetches the heads of pull requests into the refs that the
+// remote keeps them in, overwriting whatever we fetched before
+func (self *SyncCommands) FetchPullRequests(opts FetchPullRequestsOptions) error {
+	refspecs := []string{}
+	if len(opts.Numbers) == 0 {
+		refspecs = append(refspecs, fmt.Sprintf("+refs/%[1]s/*/head:refs/%[1]s/*/head", opts.Namespace))
+	}
+	for _, number := range opts.Numbers {
+		ref := PullRequestRef(opts.Namespace, number)
+		refspecs = append(refspecs, fmt.Sprintf("+%s:%s", ref, ref))
+	}
+
+	cmdStr := "git fetch " + self.cmd.Quote(opts.RemoteName)
+	for _, refspec := range refspecs {
+		cmdStr += " " + self.cmd.Quote(refspec)
+	}
+
+	cmdObj := self.cmd.New(cmdStr)
+	if opts.Background {
+		cmdObj.DontLog().FailOnCredentialRequest()
+	} else {
+		cmdObj.PromptOnCredentialRequest()
+	}
+	return cmdObj.Run()
+}
+
 type PullOptions struct {
 	RemoteName      string
 	BranchName      string
@@ -92,3 +92,39 @@ func TestSyncPush(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncFetchPullRequests(t *testing.T) {
+	type scenario struct {
+		testName     string
+		opts         FetchPullRequestsOptions
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			testName:     "All pull requests",
+			opts:         FetchPullRequestsOptions{RemoteName: "origin", Namespace: "pull"},
+			expectedArgs: []string{"fetch", "origin", "+refs/pull/*/head:refs/pull/*/head"},
+		},
+		{
+			testName: "Some merge requests",
+			opts:     FetchPullRequestsOptions{RemoteName: "origin", Namespace: "merge-requests", Numbers: []int{3, 12}, Background: true},
+			expectedArgs: []string{
+				"fetch", "origin",
+				"+refs/merge-requests/3/head:refs/merge-requests/3/head",
+				"+refs/merge-requests/12/head:refs/merge-requests/12/head",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			runner := oscommands.NewFakeRunner(t).ExpectGitArgs(s.expectedArgs, "", nil)
+			instance := buildSyncCommands(commonDeps{runner: runner})
+
+			assert.NoError(t, instance.FetchPullRequests(s.opts))
+			runner.CheckForMissingCalls()
+		})
+	}
+}
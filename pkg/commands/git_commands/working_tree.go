@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -292,6 +293,21 @@ func (self *WorkingTreeCommands) ShowFileDiffCmdObj(from string, to string, reve
 		DontLog()
 }
 
+// DiffAgainstHead returns the diff from HEAD to the file in the index if cached
+// is set, or else to the file in the working tree, with no context lines. We use
+// it to tell which lines at HEAD the lines of the file correspond to.
+func (self *WorkingTreeCommands) DiffAgainstHead(fileName string, cached bool) (string, error) {
+	cachedArg := ""
+	if cached {
+		cachedArg = " --cached"
+	}
+
+	return self.cmd.
+		New(fmt.Sprintf("git diff --no-ext-diff --unified=0 --no-renames --color=never%s HEAD -- %s", cachedArg, self.cmd.Quote(fileName))).
+		DontLog().
+		RunWithOutput()
+}
+
 // CheckoutFile checks out the file for the given commit
 func (self *WorkingTreeCommands) CheckoutFile(commitSha, fileName string) error {
 	return self.cmd.New(fmt.Sprintf("git checkout %s -- %s", commitSha, self.cmd.Quote(fileName))).Run()
@@ -346,6 +362,52 @@ func (self *WorkingTreeCommands) ResetMixed(ref string) error {
 	return self.cmd.New("git reset --mixed " + self.cmd.Quote(ref)).Run()
 }
 
+// ShowFileAtStage returns the content of a conflicted file as it is at the
+// given stage of the index: 1 is the common ancestor, 2 is ours, 3 is theirs
+func (self *WorkingTreeCommands) ShowFileAtStage(stage int, path string) (string, error) {
+	return self.cmd.New(fmt.Sprintf("git show %s", self.cmd.Quote(fmt.Sprintf(":%d:%s", stage, path)))).DontLog().RunWithOutput()
+}
+
+// ConflictStages returns the entries in the index for each side of a
+// conflicted file
+func (self *WorkingTreeCommands) ConflictStages(path string) ([]*models.ConflictStage, error) {
+	output, err := self.cmd.New("git ls-files -u -- " + self.cmd.Quote(path)).DontLog().RunWithOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	stages := []*models.ConflictStage{}
+	for _, line := range utils.SplitLines(output) {
+		// each line looks like '<mode> <sha> <stage>\t<path>'
+		fields := strings.Fields(strings.SplitN(line, "\t", 2)[0])
+		if len(fields) != 3 {
+			continue
+		}
+		stage, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		stages = append(stages, &models.ConflictStage{Mode: fields[0], Sha: fields[1], Stage: stage})
+	}
+
+	return stages, nil
+}
+
+// CheckoutOurs replaces a conflicted file with our version of it
+func (self *WorkingTreeCommands) CheckoutOurs(path string) error {
+	return self.cmd.New("git checkout --ours -- " + self.cmd.Quote(path)).Run()
+}
+
+// CheckoutTheirs replaces a conflicted file with their version of it
+func (self *WorkingTreeCommands) CheckoutTheirs(path string) error {
+	return self.cmd.New("git checkout --theirs -- " + self.cmd.Quote(path)).Run()
+}
+
+// RemoveConflictedFile resolves a conflict by deleting the file
+func (self *WorkingTreeCommands) RemoveConflictedFile(path string) error {
+	return self.cmd.New("git rm -- " + self.cmd.Quote(path)).Run()
+}
+
 // so that we don't have unnecessary space in our commands we use this helper function to prepend spaces to args so that in the format string we can go '%s%s%s' and if any args are missing we won't have gaps.
 func pad(str string) string {
 	if str == "" {
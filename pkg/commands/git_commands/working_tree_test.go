@@ -584,3 +584,78 @@ func TestWorkingTreeResetHard(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkingTreeShowFileAtStage(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		Expect(`git show ":2:dir/test.txt"`, "ours\n", nil)
+
+	instance := buildWorkingTreeCommands(commonDeps{runner: runner})
+
+	content, err := instance.ShowFileAtStage(2, "dir/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "ours\n", content)
+	runner.CheckForMissingCalls()
+}
+
+func TestWorkingTreeConflictStages(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		Expect(`git ls-files -u -- "sub"`, "160000 aaaaaaa 1\tsub\n160000 bbbbbbb 2\tsub\n160000 ccccccc 3\tsub\n", nil)
+
+	instance := buildWorkingTreeCommands(commonDeps{runner: runner})
+
+	stages, err := instance.ConflictStages("sub")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*models.ConflictStage{
+		{Mode: "160000", Sha: "aaaaaaa", Stage: 1},
+		{Mode: "160000", Sha: "bbbbbbb", Stage: 2},
+		{Mode: "160000", Sha: "ccccccc", Stage: 3},
+	}, stages)
+	runner.CheckForMissingCalls()
+}
+
+func TestWorkingTreeCheckoutConflictSide(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		Expect(`git checkout --ours -- "image.png"`, "", nil).
+		Expect(`git checkout --theirs -- "image.png"`, "", nil)
+
+	instance := buildWorkingTreeCommands(commonDeps{runner: runner})
+
+	assert.NoError(t, instance.CheckoutOurs("image.png"))
+	assert.NoError(t, instance.CheckoutTheirs("image.png"))
+	runner.CheckForMissingCalls()
+}
+
+func TestWorkingTreeDiffAgainstHead(t *testing.T) {
+	type scenario struct {
+		testName string
+		cached   bool
+		runner   *oscommands.FakeCmdObjRunner
+	}
+
+	scenarios := []scenario{
+		{
+			testName: "working tree",
+			cached:   false,
+			runner: oscommands.NewFakeRunner(t).
+				Expect(`git diff --no-ext-diff --unified=0 --no-renames --color=never HEAD -- "test.txt"`, "diff", nil),
+		},
+		{
+			testName: "index",
+			cached:   true,
+			runner: oscommands.NewFakeRunner(t).
+				Expect(`git diff --no-ext-diff --unified=0 --no-renames --color=never --cached HEAD -- "test.txt"`, "diff", nil),
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			instance := buildWorkingTreeCommands(commonDeps{runner: s.runner})
+
+			result, err := instance.DiffAgainstHead("test.txt", s.cached)
+			assert.NoError(t, err)
+			assert.Equal(t, "diff", result)
+			s.runner.CheckForMissingCalls()
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package hosting_service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+)
+
+// Besides building URLs for the browser, we can ask a hosting service's API
+// about the repo's pull requests, given a token. Each service has its own API,
+// so each has its own client.
+
+// PullRequestClient talks to a hosting service's API about pull requests
+type PullRequestClient interface {
+	// GetPullRequests returns the repo's open pull requests. Finding out the
+	// review state and CI status of a pull request takes more requests, so we
+	// only do that for those whose head branch is one of the given branches.
+	GetPullRequests(detailedBranches []string) ([]*models.PullRequest, error)
+}
+
+// Auth is what we authenticate with against a hosting service's API. A token
+// from the user's config has no username, whereas a password from git's
+// credential helper comes with the username it belongs to.
+type Auth struct {
+	Username string
+	Token    string
+}
+
+// apiClient makes authenticated requests to a hosting service's JSON API
+type apiClient struct {
+	httpClient *http.Client
+	baseURL    string
+	// the header which carries the token, and its value
+	authHeader string
+	authValue  string
+}
+
+func newAPIClient(baseURL string, authHeader string, authValue string) *apiClient {
+	return &apiClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		authHeader: authHeader,
+		authValue:  authValue,
+	}
+}
+
+// we stop following 'next' links after this many pages, so that a repo with
+// thousands of open pull requests doesn't cost us thousands of requests
+const maxPages = 10
+
+// cachedResponse is a response we can reuse if the service tells us that
+// nothing has changed since
+type cachedResponse struct {
+	etag     string
+	body     []byte
+	nextPage string
+}
+
+// We refresh pull requests every so often, and a new client each time, so the
+// cache outlives the clients. A response which the service says hasn't changed
+// doesn't count against GitHub's rate limit.
+var (
+	responseCache      = map[string]*cachedResponse{}
+	responseCacheMutex sync.Mutex
+)
+
+// we key the cache on the token too, because what you can see depends on it
+func (self *apiClient) cacheKey(requestURL string) string {
+	return self.authValue + " " + requestURL
+}
+
+func (self *apiClient) cachedResponse(requestURL string) *cachedResponse {
+	responseCacheMutex.Lock()
+	defer responseCacheMutex.Unlock()
+
+	return responseCache[self.cacheKey(requestURL)]
+}
+
+func (self *apiClient) cacheResponse(requestURL string, response *cachedResponse) {
+	responseCacheMutex.Lock()
+	defer responseCacheMutex.Unlock()
+
+	// the statuses of old commits pile up, and we'd rather start over than
+	// keep them around forever
+	if len(responseCache) >= 1000 {
+		responseCache = map[string]*cachedResponse{}
+	}
+	responseCache[self.cacheKey(requestURL)] = response
+}
+
+// get requests the given path, which is relative to the API's base URL, and
+// decodes the JSON response into result
+func (self *apiClient) get(path string, result interface{}) error {
+	_, err := self.getPage(self.baseURL+path, result)
+	return err
+}
+
+// getAll requests the given path and, as long as the responses link to a next
+// page, the pages after it, passing each page's body to decodePage. The
+// service either links to the next page in the Link header, or decodePage
+// finds the link in the body and returns it.
+func (self *apiClient) getAll(path string, decodePage func(body []byte) (string, error)) error {
+	requestURL := self.baseURL + path
+	for page := 0; page < maxPages && requestURL != ""; page++ {
+		var body json.RawMessage
+		nextPage, err := self.getPage(requestURL, &body)
+		if err != nil {
+			return err
+		}
+
+		nextPageInBody, err := decodePage(body)
+		if err != nil {
+			return err
+		}
+		if nextPage == "" {
+			nextPage = nextPageInBody
+		}
+		requestURL = nextPage
+	}
+
+	return nil
+}
+
+// getPage requests the given URL and decodes the JSON response into result,
+// returning the URL of the next page if the Link header has one
+func (self *apiClient) getPage(requestURL string, result interface{}) (string, error) {
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if self.authValue != "" {
+		req.Header.Set(self.authHeader, self.authValue)
+	}
+
+	cached := self.cachedResponse(requestURL)
+	if cached != nil {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := self.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.nextPage, json.Unmarshal(cached.body, result)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		path := strings.TrimPrefix(requestURL, self.baseURL)
+		return "", fmt.Errorf("GET %s: %s %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	nextPage := nextPageFromLinkHeader(resp.Header.Get("Link"))
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		self.cacheResponse(requestURL, &cachedResponse{etag: etag, body: body, nextPage: nextPage})
+	}
+
+	return nextPage, json.Unmarshal(body, result)
+}
+
+// nextPageFromLinkHeader returns the URL of the next page from a Link header
+// like '<https://api.github.com/...&page=2>; rel="next", <...>; rel="last"'
+func nextPageFromLinkHeader(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		split := strings.SplitN(link, ";", 2)
+		if len(split) != 2 || !strings.Contains(split[1], `rel="next"`) {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(split[0]), "<>")
+	}
+	return ""
+}
+
+// withPrefix returns the token with e.g. "token " in front of it, unless we
+// don't have a token
+func withPrefix(prefix string, token string) string {
+	if token == "" {
+		return ""
+	}
+	return prefix + token
+}
+
+// basicAuth returns the value of an Authorization header for HTTP basic auth,
+// unless we don't have a token
+func basicAuth(auth Auth) string {
+	if auth.Token == "" {
+		return ""
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth.Username+":"+auth.Token))
+}
+
+func repoPath(repo *RepoInformation) string {
+	return url.PathEscape(repo.Owner) + "/" + url.PathEscape(repo.Repository)
+}
+
+// sameRepo tells us whether two 'owner/repo' names are the same repo. Services
+// don't care about case in these.
+func sameRepo(a string, b string) bool {
+	return a != "" && strings.EqualFold(a, b)
+}
@@ -0,0 +1,321 @@
+package hosting_service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/jesseduffield/lazygit/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMockServer serves the given JSON responses, keyed by request URI, and
+// fails the test if a request doesn't carry the expected auth header
+func newMockServer(t *testing.T, authHeader string, authValue string, responses map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, authValue, r.Header.Get(authHeader))
+
+		response, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+var mockRepo = &RepoInformation{Owner: "peter", Repository: "lazygit"}
+
+func TestGithubClientGetPullRequests(t *testing.T) {
+	server := newMockServer(t, "Authorization", "token abc", map[string]string{
+		"/repos/peter/lazygit/pulls?state=open&per_page=100": `[
+			{"number": 1, "title": "Add a feature", "html_url": "https://github.com/peter/lazygit/pull/1", "user": {"login": "jesse"}, "head": {"ref": "feature", "sha": "aaa", "repo": {"full_name": "peter/lazygit"}}, "base": {"ref": "master", "repo": {"full_name": "peter/lazygit"}}},
+			{"number": 2, "title": "Fix a bug", "draft": true, "user": {"login": "mark"}, "head": {"ref": "feature", "sha": "bbb", "repo": {"full_name": "mark/lazygit"}}, "base": {"ref": "master", "repo": {"full_name": "peter/lazygit"}}}
+		]`,
+		"/repos/peter/lazygit/pulls/1/reviews": `[
+			{"state": "CHANGES_REQUESTED", "user": {"login": "mark"}},
+			{"state": "COMMENTED", "user": {"login": "mark"}},
+			{"state": "APPROVED", "user": {"login": "mark"}},
+			{"state": "APPROVED", "user": {"login": "jesse"}}
+		]`,
+		"/repos/peter/lazygit/commits/aaa/status":     `{"state": "success", "statuses": [{"state": "success"}]}`,
+		"/repos/peter/lazygit/commits/aaa/check-runs": `{"check_runs": [{"status": "completed", "conclusion": "success"}, {"status": "in_progress"}]}`,
+	})
+
+	client := newGithubClient(server.URL, Auth{Token: "abc"}, mockRepo)
+	pullRequests, err := client.GetPullRequests([]string{"feature"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*models.PullRequest{
+		{
+			Number:      1,
+			Title:       "Add a feature",
+			Author:      "jesse",
+			HeadBranch:  "feature",
+			BaseBranch:  "master",
+			HeadSha:     "aaa",
+			URL:         "https://github.com/peter/lazygit/pull/1",
+			ReviewState: models.PR_REVIEW_APPROVED,
+			CIStatus:    models.PR_CI_PENDING,
+		},
+		{
+			Number:     2,
+			Title:      "Fix a bug",
+			Author:     "mark",
+			HeadBranch: "feature",
+			BaseBranch: "master",
+			HeadSha:    "bbb",
+			IsDraft:    true,
+			IsFromFork: true,
+		},
+	}, pullRequests)
+}
+
+func TestGiteaClientGetPullRequests(t *testing.T) {
+	server := newMockServer(t, "Authorization", "", map[string]string{
+		"/repos/peter/lazygit/pulls?state=open&limit=50": `[
+			{"number": 3, "title": "Add a feature", "user": {"login": "jesse"}, "head": {"ref": "feature", "sha": "aaa", "repo": {"full_name": "peter/lazygit"}}, "base": {"ref": "master", "repo": {"full_name": "Peter/LazyGit"}}}
+		]`,
+		"/repos/peter/lazygit/pulls/3/reviews": `[
+			{"state": "APPROVED", "user": {"login": "mark"}},
+			{"state": "REQUEST_CHANGES", "user": {"login": "jesse"}}
+		]`,
+		"/repos/peter/lazygit/commits/aaa/status": `{"state": "failure", "statuses": [{"state": "failure"}]}`,
+	})
+
+	client := newGiteaClient(server.URL, Auth{}, mockRepo)
+	pullRequests, err := client.GetPullRequests([]string{"feature"})
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 1)
+	assert.Equal(t, models.PR_REVIEW_CHANGES_REQUESTED, pullRequests[0].ReviewState)
+	assert.Equal(t, models.PR_CI_FAILURE, pullRequests[0].CIStatus)
+}
+
+func TestGitlabClientGetPullRequests(t *testing.T) {
+	server := newMockServer(t, "PRIVATE-TOKEN", "abc", map[string]string{
+		"/projects/peter%2Flazygit/merge_requests?state=opened&per_page=100": `[
+			{"iid": 4, "title": "Draft: Add a feature", "work_in_progress": true, "web_url": "https://gitlab.com/peter/lazygit/-/merge_requests/4", "source_branch": "feature", "target_branch": "master", "source_project_id": 7, "target_project_id": 7, "sha": "aaa", "author": {"username": "jesse"}}
+		]`,
+		"/projects/peter%2Flazygit/merge_requests/4":           `{"iid": 4, "head_pipeline": {"status": "running"}}`,
+		"/projects/peter%2Flazygit/merge_requests/4/approvals": `{"approved": true, "approved_by": [{"user": {"username": "mark"}}]}`,
+	})
+
+	client := newGitlabClient(server.URL, Auth{Token: "abc"}, mockRepo)
+	pullRequests, err := client.GetPullRequests([]string{"feature"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*models.PullRequest{
+		{
+			Number:      4,
+			Title:       "Draft: Add a feature",
+			Author:      "jesse",
+			HeadBranch:  "feature",
+			BaseBranch:  "master",
+			HeadSha:     "aaa",
+			URL:         "https://gitlab.com/peter/lazygit/-/merge_requests/4",
+			IsDraft:     true,
+			ReviewState: models.PR_REVIEW_APPROVED,
+			CIStatus:    models.PR_CI_PENDING,
+		},
+	}, pullRequests)
+}
+
+func TestBitbucketClientGetPullRequests(t *testing.T) {
+	server := newMockServer(t, "Authorization", "Bearer abc", map[string]string{
+		"/repositories/peter/lazygit/pullrequests?state=OPEN&pagelen=50": `{"values": [
+			{"id": 5, "title": "Add a feature", "author": {"display_name": "Jesse"}, "source": {"branch": {"name": "feature"}, "commit": {"hash": "aaa"}, "repository": {"full_name": "peter/lazygit"}}, "destination": {"branch": {"name": "master"}, "repository": {"full_name": "peter/lazygit"}}, "links": {"html": {"href": "https://bitbucket.org/peter/lazygit/pull-requests/5"}}}
+		]}`,
+		"/repositories/peter/lazygit/pullrequests/5":          `{"id": 5, "participants": [{"state": "approved", "user": {"display_name": "Mark"}}, {"state": null, "user": {"display_name": "Peter"}}]}`,
+		"/repositories/peter/lazygit/pullrequests/5/statuses": `{"values": [{"state": "SUCCESSFUL"}, {"state": "FAILED"}]}`,
+	})
+
+	client := newBitbucketClient(server.URL, Auth{Token: "abc"}, mockRepo)
+	pullRequests, err := client.GetPullRequests([]string{"feature"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*models.PullRequest{
+		{
+			Number:      5,
+			Title:       "Add a feature",
+			Author:      "Jesse",
+			HeadBranch:  "feature",
+			BaseBranch:  "master",
+			HeadSha:     "aaa",
+			URL:         "https://bitbucket.org/peter/lazygit/pull-requests/5",
+			ReviewState: models.PR_REVIEW_APPROVED,
+			CIStatus:    models.PR_CI_FAILURE,
+		},
+	}, pullRequests)
+}
+
+func TestBitbucketClientUsesBasicAuthWithUsername(t *testing.T) {
+	server := newMockServer(t, "Authorization", "Basic amVzc2U6YWJj", map[string]string{
+		"/repositories/peter/lazygit/pullrequests?state=OPEN&pagelen=50": `{"values": []}`,
+	})
+
+	client := newBitbucketClient(server.URL, Auth{Username: "jesse", Token: "abc"}, mockRepo)
+	pullRequests, err := client.GetPullRequests(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, pullRequests)
+}
+
+func TestGithubClientGetPullRequestsFollowsNextPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RequestURI() {
+		case "/repos/peter/lazygit/pulls?state=open&per_page=100":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/repos/peter/lazygit/pulls?state=open&per_page=100&page=2>; rel="next", <http://%s/repos/peter/lazygit/pulls?state=open&per_page=100&page=2>; rel="last"`, r.Host, r.Host))
+			_, _ = w.Write([]byte(`[{"number": 1, "head": {"ref": "feature"}}]`))
+		case "/repos/peter/lazygit/pulls?state=open&per_page=100&page=2":
+			_, _ = w.Write([]byte(`[{"number": 2, "head": {"ref": "bugfix"}}]`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := newGithubClient(server.URL, Auth{}, mockRepo)
+	pullRequests, err := client.GetPullRequests(nil)
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 2)
+	assert.Equal(t, 2, pullRequests[1].Number)
+}
+
+func TestBitbucketClientGetPullRequestsFollowsNextPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RequestURI() {
+		case "/repositories/peter/lazygit/pullrequests?state=OPEN&pagelen=50":
+			fmt.Fprintf(w, `{"values": [{"id": 1}], "next": "http://%s/repositories/peter/lazygit/pullrequests?state=OPEN&pagelen=50&page=2"}`, r.Host)
+		case "/repositories/peter/lazygit/pullrequests?state=OPEN&pagelen=50&page=2":
+			_, _ = w.Write([]byte(`{"values": [{"id": 2}]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := newBitbucketClient(server.URL, Auth{}, mockRepo)
+	pullRequests, err := client.GetPullRequests(nil)
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 2)
+	assert.Equal(t, 2, pullRequests[1].Number)
+}
+
+func TestAPIClientReusesUnchangedResponses(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`[{"number": 1, "head": {"ref": "feature"}}]`))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		// we make a new client each time we refresh
+		client := newGithubClient(server.URL, Auth{Token: "abc"}, mockRepo)
+		pullRequests, err := client.GetPullRequests(nil)
+		assert.NoError(t, err)
+		assert.Len(t, pullRequests, 1)
+		assert.Equal(t, 1, pullRequests[0].Number)
+	}
+	assert.Equal(t, 2, requests)
+}
+
+func TestGithubClientToleratesFailedDetailRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RequestURI() {
+		case "/repos/peter/lazygit/pulls?state=open&per_page=100":
+			_, _ = w.Write([]byte(`[{"number": 1, "head": {"ref": "feature", "sha": "aaa", "repo": {"full_name": "peter/lazygit"}}, "base": {"repo": {"full_name": "peter/lazygit"}}}]`))
+		case "/repos/peter/lazygit/pulls/1/reviews":
+			http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+		case "/repos/peter/lazygit/commits/aaa/status":
+			_, _ = w.Write([]byte(`{"state": "success", "statuses": [{"state": "success"}]}`))
+		case "/repos/peter/lazygit/commits/aaa/check-runs":
+			_, _ = w.Write([]byte(`{"check_runs": []}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := newGithubClient(server.URL, Auth{}, mockRepo)
+	pullRequests, err := client.GetPullRequests([]string{"feature"})
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 1)
+	assert.Equal(t, models.PR_REVIEW_NONE, pullRequests[0].ReviewState)
+	assert.Equal(t, models.PR_CI_SUCCESS, pullRequests[0].CIStatus)
+}
+
+func TestAPIClientReportsErrorResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := newGithubClient(server.URL, Auth{Token: "wrong"}, mockRepo)
+	_, err := client.GetPullRequests(nil)
+	assert.EqualError(t, err, `GET /repos/peter/lazygit/pulls?state=open&per_page=100: 401 Unauthorized {"message": "Bad credentials"}`)
+}
+
+func TestGetPullRequestClient(t *testing.T) {
+	server := newMockServer(t, "PRIVATE-TOKEN", "abc", map[string]string{
+		"/projects/peter%2Flazygit/merge_requests?state=opened&per_page=100": `[]`,
+	})
+
+	tr := i18n.EnglishTranslationSet()
+	hostingServiceMgr := NewHostingServiceMgr(&test.FakeFieldLogger{}, &tr, "git@git.work.com:peter/lazygit.git", map[string]string{"git.work.com": "gitlab:code.work.com"}, nil)
+
+	webDomain, err := hostingServiceMgr.GetWebDomain()
+	assert.NoError(t, err)
+	assert.Equal(t, "code.work.com", webDomain)
+
+	client, err := hostingServiceMgr.GetPullRequestClient(Auth{Token: "abc"}, server.URL)
+	assert.NoError(t, err)
+	pullRequests, err := client.GetPullRequests(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, pullRequests)
+}
+
+func TestAPIBaseURLs(t *testing.T) {
+	assert.Equal(t, "https://api.github.com", githubAPIBaseURL("github.com"))
+	assert.Equal(t, "https://github.work.com/api/v3", githubAPIBaseURL("github.work.com"))
+	assert.Equal(t, "https://gitlab.com/api/v4", gitlabAPIBaseURL("gitlab.com"))
+	assert.Equal(t, "https://gitea.com/api/v1", giteaAPIBaseURL("gitea.com"))
+	assert.Equal(t, "https://api.bitbucket.org/2.0", bitbucketAPIBaseURL("bitbucket.org"))
+}
+
+func TestGetPullRequestRefNamespace(t *testing.T) {
+	scenarios := []struct {
+		remoteURL   string
+		expected    string
+		expectedErr string
+	}{
+		{"git@github.com:peter/lazygit.git", "pull", ""},
+		{"https://gitlab.com/peter/lazygit.git", "merge-requests", ""},
+		{"git@gitea.com:peter/lazygit.git", "pull", ""},
+		{"git@bitbucket.org:peter/lazygit.git", "", "This git service doesn't keep pull requests in refs that we can fetch"},
+	}
+
+	for _, s := range scenarios {
+		tr := i18n.EnglishTranslationSet()
+		hostingServiceMgr := NewHostingServiceMgr(&test.FakeFieldLogger{}, &tr, s.remoteURL, nil, nil)
+
+		namespace, err := hostingServiceMgr.GetPullRequestRefNamespace()
+		if s.expectedErr != "" {
+			assert.EqualError(t, err, s.expectedErr)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, s.expected, namespace)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package hosting_service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+type bitbucketClient struct {
+	api  *apiClient
+	repo *RepoInformation
+}
+
+func newBitbucketClient(baseURL string, auth Auth, repo *RepoInformation) PullRequestClient {
+	// an app password from git's credential helper only works with the username
+	// it belongs to, whereas an access token stands on its own
+	authValue := withPrefix("Bearer ", auth.Token)
+	if auth.Username != "" {
+		authValue = basicAuth(auth)
+	}
+
+	return &bitbucketClient{
+		api:  newAPIClient(baseURL, "Authorization", authValue),
+		repo: repo,
+	}
+}
+
+func bitbucketAPIBaseURL(webDomain string) string {
+	return fmt.Sprintf("https://api.%s/2.0", webDomain)
+}
+
+type bitbucketPullRequest struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Draft  bool   `json:"draft"`
+	Author struct {
+		DisplayName string `json:"display_name"`
+	} `json:"author"`
+	Source struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+		Commit struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
+		Repository bitbucketRepo `json:"repository"`
+	} `json:"source"`
+	Destination struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+		Repository bitbucketRepo `json:"repository"`
+	} `json:"destination"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+	// only in the response for a single pull request
+	Participants []struct {
+		State string `json:"state"`
+		User  struct {
+			DisplayName string `json:"display_name"`
+		} `json:"user"`
+	} `json:"participants"`
+}
+
+type bitbucketRepo struct {
+	FullName string `json:"full_name"`
+}
+
+type bitbucketStatuses struct {
+	Values []struct {
+		State string `json:"state"`
+	} `json:"values"`
+}
+
+func (self *bitbucketClient) path(format string, args ...interface{}) string {
+	return "/repositories/" + repoPath(self.repo) + fmt.Sprintf(format, args...)
+}
+
+func (self *bitbucketClient) GetPullRequests(detailedBranches []string) ([]*models.PullRequest, error) {
+	response := []bitbucketPullRequest{}
+	err := self.api.getAll(self.path("/pullrequests?state=OPEN&pagelen=50"), func(body []byte) (string, error) {
+		var page struct {
+			Values []bitbucketPullRequest `json:"values"`
+			// the URL of the next page, if there is one
+			Next string `json:"next"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		response = append(response, page.Values...)
+		return page.Next, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests := make([]*models.PullRequest, len(response))
+	for i, pr := range response {
+		pullRequest := &models.PullRequest{
+			Number:     pr.ID,
+			Title:      pr.Title,
+			Author:     pr.Author.DisplayName,
+			HeadBranch: pr.Source.Branch.Name,
+			BaseBranch: pr.Destination.Branch.Name,
+			HeadSha:    pr.Source.Commit.Hash,
+			URL:        pr.Links.HTML.Href,
+			IsDraft:    pr.Draft,
+			IsFromFork: !sameRepo(pr.Source.Repository.FullName, pr.Destination.Repository.FullName),
+		}
+		pullRequests[i] = pullRequest
+
+		if pullRequest.IsFromFork || !utils.IncludesString(detailedBranches, pr.Source.Branch.Name) {
+			continue
+		}
+
+		// we'd rather show a pull request without its review state or CI status
+		// than not show any pull requests at all
+		var detail bitbucketPullRequest
+		if err := self.api.get(self.path("/pullrequests/%d", pr.ID), &detail); err == nil {
+			verdicts := map[string]models.PullRequestReviewState{}
+			for _, participant := range detail.Participants {
+				switch participant.State {
+				case "approved":
+					verdicts[participant.User.DisplayName] = models.PR_REVIEW_APPROVED
+				case "changes_requested":
+					verdicts[participant.User.DisplayName] = models.PR_REVIEW_CHANGES_REQUESTED
+				}
+			}
+			pullRequest.ReviewState = combineReviewVerdicts(verdicts)
+		}
+
+		var statuses bitbucketStatuses
+		if err := self.api.get(self.path("/pullrequests/%d/statuses", pr.ID), &statuses); err == nil {
+			ciStatuses := make([]models.PullRequestCIStatus, len(statuses.Values))
+			for i, status := range statuses.Values {
+				ciStatuses[i] = bitbucketCIStatus(status.State)
+			}
+			pullRequest.CIStatus = models.CombineCIStatuses(ciStatuses)
+		}
+	}
+
+	return pullRequests, nil
+}
+
+func bitbucketCIStatus(state string) models.PullRequestCIStatus {
+	switch state {
+	case "SUCCESSFUL":
+		return models.PR_CI_SUCCESS
+	case "FAILED":
+		return models.PR_CI_FAILURE
+	case "INPROGRESS":
+		return models.PR_CI_PENDING
+	default:
+		// e.g. STOPPED
+		return models.PR_CI_NONE
+	}
+}
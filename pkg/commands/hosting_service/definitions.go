@@ -1,5 +1,7 @@
 package hosting_service
 
+import "github.com/jesseduffield/lazygit/pkg/config"
+
 // if you want to make a custom regex for a given service feel free to test it out
 // at regoio.herokuapp.com
 var defaultUrlRegexStrings = []string{
@@ -15,6 +17,17 @@ var githubServiceDef = ServiceDefinition{
 	pullRequestURLIntoTargetBranch:  "/compare/{{.To}}...{{.From}}?expand=1",
 	commitURL:                       "/commit/{{.CommitSha}}",
 	regexStrings:                    defaultUrlRegexStrings,
+	pullRequestRefNamespace:         "pull",
+	apiBaseURL:                      githubAPIBaseURL,
+	newPullRequestClient:            newGithubClient,
+	urlTemplates: config.URLTemplatesConfig{
+		File:      "/blob/{{.Ref}}/{{.Path}}",
+		Line:      "/blob/{{.Ref}}/{{.Path}}#L{{.Line}}",
+		LineRange: "/blob/{{.Ref}}/{{.Path}}#L{{.StartLine}}-L{{.EndLine}}",
+		Branch:    "/tree/{{.Branch}}",
+		Tag:       "/releases/tag/{{.Tag}}",
+		Compare:   "/compare/{{.From}}...{{.To}}",
+	},
 }
 
 var bitbucketServiceDef = ServiceDefinition{
@@ -23,6 +36,17 @@ var bitbucketServiceDef = ServiceDefinition{
 	pullRequestURLIntoTargetBranch:  "/pull-requests/new?source={{.From}}&dest={{.To}}&t=1",
 	commitURL:                       "/commits/{{.CommitSha}}",
 	regexStrings:                    defaultUrlRegexStrings,
+	apiBaseURL:                      bitbucketAPIBaseURL,
+	newPullRequestClient:            newBitbucketClient,
+	urlTemplates: config.URLTemplatesConfig{
+		File:      "/src/{{.Ref}}/{{.Path}}",
+		Line:      "/src/{{.Ref}}/{{.Path}}#lines-{{.Line}}",
+		LineRange: "/src/{{.Ref}}/{{.Path}}#lines-{{.StartLine}}:{{.EndLine}}",
+		Branch:    "/branch/{{.Branch}}",
+		Tag:       "/src/{{.Tag}}",
+		// bitbucket puts the ref with the changes first
+		Compare: "/branches/compare/{{.To}}%0D{{.From}}#diff",
+	},
 }
 
 var gitLabServiceDef = ServiceDefinition{
@@ -31,9 +55,39 @@ var gitLabServiceDef = ServiceDefinition{
 	pullRequestURLIntoTargetBranch:  "/merge_requests/new?merge_request[source_branch]={{.From}}&merge_request[target_branch]={{.To}}",
 	commitURL:                       "/commit/{{.CommitSha}}",
 	regexStrings:                    defaultUrlRegexStrings,
+	pullRequestRefNamespace:         "merge-requests",
+	apiBaseURL:                      gitlabAPIBaseURL,
+	newPullRequestClient:            newGitlabClient,
+	urlTemplates: config.URLTemplatesConfig{
+		File:      "/-/blob/{{.Ref}}/{{.Path}}",
+		Line:      "/-/blob/{{.Ref}}/{{.Path}}#L{{.Line}}",
+		LineRange: "/-/blob/{{.Ref}}/{{.Path}}#L{{.StartLine}}-{{.EndLine}}",
+		Branch:    "/-/tree/{{.Branch}}",
+		Tag:       "/-/tags/{{.Tag}}",
+		Compare:   "/-/compare/{{.From}}...{{.To}}",
+	},
 }
 
-var serviceDefinitions = []ServiceDefinition{githubServiceDef, bitbucketServiceDef, gitLabServiceDef}
+var giteaServiceDef = ServiceDefinition{
+	provider:                        "gitea",
+	pullRequestURLIntoDefaultBranch: "/compare/{{.From}}",
+	pullRequestURLIntoTargetBranch:  "/compare/{{.To}}...{{.From}}",
+	commitURL:                       "/commit/{{.CommitSha}}",
+	regexStrings:                    defaultUrlRegexStrings,
+	pullRequestRefNamespace:         "pull",
+	apiBaseURL:                      giteaAPIBaseURL,
+	newPullRequestClient:            newGiteaClient,
+	urlTemplates: config.URLTemplatesConfig{
+		File:      "/src/commit/{{.Ref}}/{{.Path}}",
+		Line:      "/src/commit/{{.Ref}}/{{.Path}}#L{{.Line}}",
+		LineRange: "/src/commit/{{.Ref}}/{{.Path}}#L{{.StartLine}}-L{{.EndLine}}",
+		Branch:    "/src/branch/{{.Branch}}",
+		Tag:       "/releases/tag/{{.Tag}}",
+		Compare:   "/compare/{{.From}}...{{.To}}",
+	},
+}
+
+var serviceDefinitions = []ServiceDefinition{githubServiceDef, bitbucketServiceDef, gitLabServiceDef, giteaServiceDef}
 
 var defaultServiceDomains = []ServiceDomain{
 	{
@@ -51,4 +105,9 @@ var defaultServiceDomains = []ServiceDomain{
 		gitDomain:         "gitlab.com",
 		webDomain:         "gitlab.com",
 	},
+	{
+		serviceDefinition: giteaServiceDef,
+		gitDomain:         "gitea.com",
+		webDomain:         "gitea.com",
+	},
 }
@@ -0,0 +1,225 @@
+package hosting_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// Gitea's API is modelled on GitHub's, so this client serves both
+
+type githubClient struct {
+	api  *apiClient
+	repo *RepoInformation
+	// the query string for listing open pull requests
+	listQuery string
+	// only GitHub has check runs (e.g. from GitHub Actions) on top of statuses
+	hasCheckRuns bool
+}
+
+func newGithubClient(baseURL string, auth Auth, repo *RepoInformation) PullRequestClient {
+	return &githubClient{
+		api:          newAPIClient(baseURL, "Authorization", withPrefix("token ", auth.Token)),
+		repo:         repo,
+		listQuery:    "state=open&per_page=100",
+		hasCheckRuns: true,
+	}
+}
+
+func newGiteaClient(baseURL string, auth Auth, repo *RepoInformation) PullRequestClient {
+	return &githubClient{
+		api:       newAPIClient(baseURL, "Authorization", withPrefix("token ", auth.Token)),
+		repo:      repo,
+		listQuery: "state=open&limit=50",
+	}
+}
+
+func githubAPIBaseURL(webDomain string) string {
+	if webDomain == "github.com" {
+		return "https://api.github.com"
+	}
+	// GitHub Enterprise
+	return fmt.Sprintf("https://%s/api/v3", webDomain)
+}
+
+func giteaAPIBaseURL(webDomain string) string {
+	return fmt.Sprintf("https://%s/api/v1", webDomain)
+}
+
+type githubPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	Draft   bool   `json:"draft"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head struct {
+		Ref string `json:"ref"`
+		Sha string `json:"sha"`
+		// this is null if the fork has since been deleted
+		Repo *githubRepo `json:"repo"`
+	} `json:"head"`
+	Base struct {
+		Ref  string     `json:"ref"`
+		Repo githubRepo `json:"repo"`
+	} `json:"base"`
+}
+
+type githubRepo struct {
+	FullName string `json:"full_name"`
+}
+
+type githubReview struct {
+	State string `json:"state"`
+	User  struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+type githubCombinedStatus struct {
+	State    string `json:"state"`
+	Statuses []struct {
+		State string `json:"state"`
+	} `json:"statuses"`
+}
+
+type githubCheckRuns struct {
+	CheckRuns []struct {
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	} `json:"check_runs"`
+}
+
+func (self *githubClient) path(format string, args ...interface{}) string {
+	return "/repos/" + repoPath(self.repo) + fmt.Sprintf(format, args...)
+}
+
+func (self *githubClient) GetPullRequests(detailedBranches []string) ([]*models.PullRequest, error) {
+	response := []githubPullRequest{}
+	err := self.api.getAll(self.path("/pulls?%s", self.listQuery), func(body []byte) (string, error) {
+		var page []githubPullRequest
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		response = append(response, page...)
+		// the Link header takes us to the next page
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests := make([]*models.PullRequest, len(response))
+	for i, pr := range response {
+		pullRequest := &models.PullRequest{
+			Number:     pr.Number,
+			Title:      pr.Title,
+			Author:     pr.User.Login,
+			HeadBranch: pr.Head.Ref,
+			BaseBranch: pr.Base.Ref,
+			HeadSha:    pr.Head.Sha,
+			URL:        pr.HTMLURL,
+			IsDraft:    pr.Draft,
+			IsFromFork: pr.Head.Repo == nil || !sameRepo(pr.Head.Repo.FullName, pr.Base.Repo.FullName),
+		}
+		pullRequests[i] = pullRequest
+
+		if pullRequest.IsFromFork || !utils.IncludesString(detailedBranches, pr.Head.Ref) {
+			continue
+		}
+
+		// we'd rather show a pull request without its review state or CI status
+		// than not show any pull requests at all
+		if reviewState, err := self.getReviewState(pr.Number); err == nil {
+			pullRequest.ReviewState = reviewState
+		}
+		if ciStatus, err := self.getCIStatus(pr.Head.Sha); err == nil {
+			pullRequest.CIStatus = ciStatus
+		}
+	}
+
+	return pullRequests, nil
+}
+
+func (self *githubClient) getReviewState(number int) (models.PullRequestReviewState, error) {
+	var reviews []githubReview
+	if err := self.api.get(self.path("/pulls/%d/reviews", number), &reviews); err != nil {
+		return models.PR_REVIEW_NONE, err
+	}
+
+	// reviews come oldest first, and it's each reviewer's latest verdict that
+	// counts
+	verdicts := map[string]models.PullRequestReviewState{}
+	for _, review := range reviews {
+		switch strings.ToUpper(review.State) {
+		case "APPROVED":
+			verdicts[review.User.Login] = models.PR_REVIEW_APPROVED
+		case "CHANGES_REQUESTED", "REQUEST_CHANGES":
+			verdicts[review.User.Login] = models.PR_REVIEW_CHANGES_REQUESTED
+		case "DISMISSED":
+			delete(verdicts, review.User.Login)
+		}
+	}
+
+	return combineReviewVerdicts(verdicts), nil
+}
+
+func (self *githubClient) getCIStatus(sha string) (models.PullRequestCIStatus, error) {
+	var combinedStatus githubCombinedStatus
+	if err := self.api.get(self.path("/commits/%s/status", sha), &combinedStatus); err != nil {
+		return models.PR_CI_NONE, err
+	}
+
+	statuses := []models.PullRequestCIStatus{}
+	// with no statuses, the combined state is 'pending', which is misleading
+	if len(combinedStatus.Statuses) > 0 {
+		statuses = append(statuses, githubCIStatus(combinedStatus.State))
+	}
+
+	if self.hasCheckRuns {
+		var checkRuns githubCheckRuns
+		if err := self.api.get(self.path("/commits/%s/check-runs", sha), &checkRuns); err != nil {
+			return models.PR_CI_NONE, err
+		}
+		for _, checkRun := range checkRuns.CheckRuns {
+			if checkRun.Status != "completed" {
+				statuses = append(statuses, models.PR_CI_PENDING)
+				continue
+			}
+			statuses = append(statuses, githubCIStatus(checkRun.Conclusion))
+		}
+	}
+
+	return models.CombineCIStatuses(statuses), nil
+}
+
+func githubCIStatus(state string) models.PullRequestCIStatus {
+	switch state {
+	case "success":
+		return models.PR_CI_SUCCESS
+	case "pending":
+		return models.PR_CI_PENDING
+	case "failure", "error", "timed_out", "action_required", "cancelled":
+		return models.PR_CI_FAILURE
+	default:
+		// e.g. a check run that was skipped or neutral
+		return models.PR_CI_NONE
+	}
+}
+
+// combineReviewVerdicts sums up the latest verdict of each reviewer: one
+// request for changes outweighs any number of approvals
+func combineReviewVerdicts(verdicts map[string]models.PullRequestReviewState) models.PullRequestReviewState {
+	result := models.PR_REVIEW_NONE
+	for _, verdict := range verdicts {
+		if verdict == models.PR_REVIEW_CHANGES_REQUESTED {
+			return verdict
+		}
+		result = verdict
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package hosting_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+type gitlabClient struct {
+	api  *apiClient
+	repo *RepoInformation
+}
+
+func newGitlabClient(baseURL string, auth Auth, repo *RepoInformation) PullRequestClient {
+	return &gitlabClient{
+		api:  newAPIClient(baseURL, "PRIVATE-TOKEN", auth.Token),
+		repo: repo,
+	}
+}
+
+func gitlabAPIBaseURL(webDomain string) string {
+	return fmt.Sprintf("https://%s/api/v4", webDomain)
+}
+
+type gitlabMergeRequest struct {
+	IID            int    `json:"iid"`
+	Title          string `json:"title"`
+	WebURL         string `json:"web_url"`
+	Draft          bool   `json:"draft"`
+	WorkInProgress bool   `json:"work_in_progress"`
+	SourceBranch   string `json:"source_branch"`
+	TargetBranch   string `json:"target_branch"`
+	Sha            string `json:"sha"`
+	Author         struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	// these differ when the source branch is in a fork
+	SourceProjectID int `json:"source_project_id"`
+	TargetProjectID int `json:"target_project_id"`
+	// only in the response for a single merge request
+	HeadPipeline *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+type gitlabApprovals struct {
+	// this is true when no approvals are required, even if there are none
+	Approved   bool `json:"approved"`
+	ApprovedBy []struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	} `json:"approved_by"`
+}
+
+// the project ID can be its path, with the slashes escaped
+func (self *gitlabClient) path(format string, args ...interface{}) string {
+	return "/projects/" + url.PathEscape(self.repo.Owner+"/"+self.repo.Repository) + fmt.Sprintf(format, args...)
+}
+
+func (self *gitlabClient) GetPullRequests(detailedBranches []string) ([]*models.PullRequest, error) {
+	response := []gitlabMergeRequest{}
+	err := self.api.getAll(self.path("/merge_requests?state=opened&per_page=100"), func(body []byte) (string, error) {
+		var page []gitlabMergeRequest
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		response = append(response, page...)
+		// the Link header takes us to the next page
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests := make([]*models.PullRequest, len(response))
+	for i, mr := range response {
+		pullRequest := &models.PullRequest{
+			Number:     mr.IID,
+			Title:      mr.Title,
+			Author:     mr.Author.Username,
+			HeadBranch: mr.SourceBranch,
+			BaseBranch: mr.TargetBranch,
+			HeadSha:    mr.Sha,
+			URL:        mr.WebURL,
+			IsDraft:    mr.Draft || mr.WorkInProgress,
+			IsFromFork: mr.SourceProjectID != mr.TargetProjectID,
+		}
+		pullRequests[i] = pullRequest
+
+		if pullRequest.IsFromFork || !utils.IncludesString(detailedBranches, mr.SourceBranch) {
+			continue
+		}
+
+		// we'd rather show a merge request without its review state or CI
+		// status than not show any merge requests at all
+		var detail gitlabMergeRequest
+		if err := self.api.get(self.path("/merge_requests/%d", mr.IID), &detail); err == nil && detail.HeadPipeline != nil {
+			pullRequest.CIStatus = gitlabCIStatus(detail.HeadPipeline.Status)
+		}
+
+		var approvals gitlabApprovals
+		if err := self.api.get(self.path("/merge_requests/%d/approvals", mr.IID), &approvals); err == nil && approvals.Approved && len(approvals.ApprovedBy) > 0 {
+			pullRequest.ReviewState = models.PR_REVIEW_APPROVED
+		}
+	}
+
+	return pullRequests, nil
+}
+
+func gitlabCIStatus(status string) models.PullRequestCIStatus {
+	switch status {
+	case "success":
+		return models.PR_CI_SUCCESS
+	case "failed":
+		return models.PR_CI_FAILURE
+	case "created", "waiting_for_resource", "preparing", "pending", "running", "scheduled":
+		return models.PR_CI_PENDING
+	default:
+		// e.g. canceled, skipped or manual
+		return models.PR_CI_NONE
+	}
+}
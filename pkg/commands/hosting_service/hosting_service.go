@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/go-errors/errors"
+	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/i18n"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -24,15 +26,18 @@ type HostingServiceMgr struct {
 
 	// see https://github.com/jesseduffield/lazygit/blob/master/docs/Config.md#custom-pull-request-urls
 	configServiceDomains map[string]string
+	// keyed by web domain or provider
+	configURLTemplates map[string]config.URLTemplatesConfig
 }
 
 // NewHostingServiceMgr creates new instance of PullRequest
-func NewHostingServiceMgr(log logrus.FieldLogger, tr *i18n.TranslationSet, remoteURL string, configServiceDomains map[string]string) *HostingServiceMgr {
+func NewHostingServiceMgr(log logrus.FieldLogger, tr *i18n.TranslationSet, remoteURL string, configServiceDomains map[string]string, configURLTemplates map[string]config.URLTemplatesConfig) *HostingServiceMgr {
 	return &HostingServiceMgr{
 		log:                  log,
 		tr:                   tr,
 		remoteURL:            remoteURL,
 		configServiceDomains: configServiceDomains,
+		configURLTemplates:   configURLTemplates,
 	}
 }
 
@@ -60,6 +65,115 @@ func (self *HostingServiceMgr) GetCommitURL(commitSha string) (string, error) {
 	return pullRequestURL, nil
 }
 
+// GetRepoURL returns the URL of the repo's page on the hosting service's
+// website, e.g. https://github.com/jesseduffield/lazygit
+func (self *HostingServiceMgr) GetRepoURL() (string, error) {
+	gitService, err := self.getService()
+	if err != nil {
+		return "", err
+	}
+
+	return gitService.root, nil
+}
+
+// GetFileURL returns the URL of the file at the given path as of the given
+// ref, which had best be a commit that the remote knows about
+func (self *HostingServiceMgr) GetFileURL(ref string, path string) (string, error) {
+	gitService, err := self.getService()
+	if err != nil {
+		return "", err
+	}
+
+	return gitService.getFileURL(escapePath(ref), escapePath(path)), nil
+}
+
+// GetLinesURL returns the URL of the given lines of the file, counting from 1
+func (self *HostingServiceMgr) GetLinesURL(ref string, path string, startLine int, endLine int) (string, error) {
+	gitService, err := self.getService()
+	if err != nil {
+		return "", err
+	}
+
+	return gitService.getLinesURL(escapePath(ref), escapePath(path), startLine, endLine), nil
+}
+
+func (self *HostingServiceMgr) GetBranchURL(branchName string) (string, error) {
+	gitService, err := self.getService()
+	if err != nil {
+		return "", err
+	}
+
+	return gitService.getBranchURL(escapePath(branchName)), nil
+}
+
+func (self *HostingServiceMgr) GetTagURL(tagName string) (string, error) {
+	gitService, err := self.getService()
+	if err != nil {
+		return "", err
+	}
+
+	return gitService.getTagURL(escapePath(tagName)), nil
+}
+
+// GetCompareURL returns the URL of the changes going from one ref to the other
+func (self *HostingServiceMgr) GetCompareURL(from string, to string) (string, error) {
+	gitService, err := self.getService()
+	if err != nil {
+		return "", err
+	}
+
+	return gitService.getCompareURL(escapePath(from), escapePath(to)), nil
+}
+
+// GetWebDomain returns the domain of the hosting service's website, e.g.
+// github.com, which is what we key the service's API settings by
+func (self *HostingServiceMgr) GetWebDomain() (string, error) {
+	serviceDomain, err := self.getServiceDomain(self.remoteURL)
+	if err != nil {
+		return "", err
+	}
+
+	return serviceDomain.webDomain, nil
+}
+
+// GetPullRequestClient returns a client for the hosting service's API. If
+// apiBaseURL is empty, we use the one that the service usually has.
+func (self *HostingServiceMgr) GetPullRequestClient(auth Auth, apiBaseURL string) (PullRequestClient, error) {
+	serviceDomain, err := self.getServiceDomain(self.remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceDefinition := serviceDomain.serviceDefinition
+	repoInfo, err := serviceDefinition.getRepoInfoFromURL(self.remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiBaseURL == "" {
+		apiBaseURL = serviceDefinition.apiBaseURL(serviceDomain.webDomain)
+	}
+
+	return serviceDefinition.newPullRequestClient(apiBaseURL, auth, repoInfo), nil
+}
+
+// GetPullRequestRefNamespace returns the namespace of the refs in which the
+// hosting service keeps the heads of pull requests, e.g. 'pull' for GitHub,
+// whose pull request 5 has its head in refs/pull/5/head
+func (self *HostingServiceMgr) GetPullRequestRefNamespace() (string, error) {
+	serviceDomain, err := self.getServiceDomain(self.remoteURL)
+	if err != nil {
+		return "", err
+	}
+
+	namespace := serviceDomain.serviceDefinition.pullRequestRefNamespace
+	if namespace == "" {
+		return "", errors.New(self.tr.NoPullRequestRefs)
+	}
+
+	return namespace, nil
+}
+
 func (self *HostingServiceMgr) getService() (*Service, error) {
 	serviceDomain, err := self.getServiceDomain(self.remoteURL)
 	if err != nil {
@@ -71,10 +185,87 @@ func (self *HostingServiceMgr) getService() (*Service, error) {
 		return nil, err
 	}
 
-	return &Service{
+	service := &Service{
 		root:              root,
 		ServiceDefinition: serviceDomain.serviceDefinition,
-	}, nil
+	}
+	// the user's templates for the domain win over those for the provider
+	for _, key := range []string{service.provider, serviceDomain.webDomain} {
+		if overrides, ok := self.configURLTemplates[key]; ok {
+			service.urlTemplates = mergeURLTemplates(service.urlTemplates, overrides)
+		}
+	}
+
+	return service, nil
+}
+
+// mergeURLTemplates returns base with any templates set in overrides applied
+func mergeURLTemplates(base config.URLTemplatesConfig, overrides config.URLTemplatesConfig) config.URLTemplatesConfig {
+	result := base
+	for _, template := range []struct {
+		target   *string
+		override string
+	}{
+		{&result.File, overrides.File},
+		{&result.Line, overrides.Line},
+		{&result.LineRange, overrides.LineRange},
+		{&result.Branch, overrides.Branch},
+		{&result.Tag, overrides.Tag},
+		{&result.Compare, overrides.Compare},
+	} {
+		if template.override != "" {
+			*template.target = template.override
+		}
+	}
+	return result
+}
+
+// ValidateURLTemplates checks that the user's URL templates only have the
+// placeholders we fill in. Anything else, like a misspelt key, would otherwise
+// end up in the URL as it is.
+func ValidateURLTemplates(templates config.URLTemplatesConfig) error {
+	for _, template := range []struct {
+		name     string
+		template string
+		keys     []string
+	}{
+		{"file", templates.File, []string{"Ref", "Path"}},
+		{"line", templates.Line, []string{"Ref", "Path", "Line"}},
+		{"lineRange", templates.LineRange, []string{"Ref", "Path", "StartLine", "EndLine"}},
+		{"branch", templates.Branch, []string{"Branch"}},
+		{"tag", templates.Tag, []string{"Tag"}},
+		{"compare", templates.Compare, []string{"From", "To"}},
+	} {
+		remaining := utils.ResolvePlaceholderString(template.template, placeholderValues(template.keys))
+		if strings.Contains(remaining, "{{") || strings.Contains(remaining, "}}") {
+			placeholders := make([]string, len(template.keys))
+			for i, key := range template.keys {
+				placeholders[i] = "{{." + key + "}}"
+			}
+			return fmt.Errorf("%s: '%s' has a placeholder other than %s", template.name, template.template, strings.Join(placeholders, ", "))
+		}
+	}
+	return nil
+}
+
+// placeholderValues fills in each key with an empty string, which is all we
+// need to see what's left of a template once the keys are filled in
+func placeholderValues(keys []string) map[string]string {
+	values := map[string]string{}
+	for _, key := range keys {
+		values[key] = ""
+	}
+	return values
+}
+
+// escapePath escapes each segment of a slash-separated path like a file path
+// or a branch name, leaving the slashes alone
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
 }
 
 func (self *HostingServiceMgr) getServiceDomain(repoURL string) (*ServiceDomain, error) {
@@ -161,6 +352,15 @@ type ServiceDefinition struct {
 	pullRequestURLIntoTargetBranch  string
 	commitURL                       string
 	regexStrings                    []string
+	// the service keeps the head of pull request N in refs/<namespace>/N/head,
+	// if it has such refs at all
+	pullRequestRefNamespace string
+	// where the service shows files, branches etc
+	urlTemplates config.URLTemplatesConfig
+
+	// where we expect the service's API to be, given its web domain
+	apiBaseURL           func(webDomain string) string
+	newPullRequestClient func(baseURL string, auth Auth, repo *RepoInformation) PullRequestClient
 }
 
 func (self ServiceDefinition) getRepoInfoFromURL(url string) (*RepoInformation, error) {
@@ -195,6 +395,34 @@ func (self *Service) getCommitURL(commitSha string) string {
 	return self.resolveUrl(self.commitURL, map[string]string{"CommitSha": commitSha})
 }
 
+func (self *Service) getFileURL(ref string, path string) string {
+	return self.resolveUrl(self.urlTemplates.File, map[string]string{"Ref": ref, "Path": path})
+}
+
+func (self *Service) getLinesURL(ref string, path string, startLine int, endLine int) string {
+	if startLine == endLine {
+		return self.resolveUrl(self.urlTemplates.Line, map[string]string{
+			"Ref": ref, "Path": path, "Line": strconv.Itoa(startLine),
+		})
+	}
+
+	return self.resolveUrl(self.urlTemplates.LineRange, map[string]string{
+		"Ref": ref, "Path": path, "StartLine": strconv.Itoa(startLine), "EndLine": strconv.Itoa(endLine),
+	})
+}
+
+func (self *Service) getBranchURL(branchName string) string {
+	return self.resolveUrl(self.urlTemplates.Branch, map[string]string{"Branch": branchName})
+}
+
+func (self *Service) getTagURL(tagName string) string {
+	return self.resolveUrl(self.urlTemplates.Tag, map[string]string{"Tag": tagName})
+}
+
+func (self *Service) getCompareURL(from string, to string) string {
+	return self.resolveUrl(self.urlTemplates.Compare, map[string]string{"From": from, "To": to})
+}
+
 func (self *Service) resolveUrl(templateString string, args map[string]string) string {
 	return self.root + utils.ResolvePlaceholderString(templateString, args)
 }
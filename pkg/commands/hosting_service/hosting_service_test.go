@@ -3,6 +3,7 @@ package hosting_service
 import (
 	"testing"
 
+	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/i18n"
 	"github.com/jesseduffield/lazygit/pkg/test"
 	"github.com/stretchr/testify/assert"
@@ -218,7 +219,7 @@ func TestGetPullRequestURL(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, "https://bitbucket.org/johndoe/social_network/pull-requests/new?source=feature%2Fprofile-page&t=1", url)
 			},
-			expectedLoggedErrors: []string{"Unknown git service type: 'noservice'. Expected one of github, bitbucket, gitlab"},
+			expectedLoggedErrors: []string{"Unknown git service type: 'noservice'. Expected one of github, bitbucket, gitlab, gitea"},
 		},
 		{
 			testName:  "Escapes reserved URL characters in from branch name",
@@ -247,9 +248,191 @@ func TestGetPullRequestURL(t *testing.T) {
 		t.Run(s.testName, func(t *testing.T) {
 			tr := i18n.EnglishTranslationSet()
 			log := &test.FakeFieldLogger{}
-			hostingServiceMgr := NewHostingServiceMgr(log, &tr, s.remoteUrl, s.configServiceDomains)
+			hostingServiceMgr := NewHostingServiceMgr(log, &tr, s.remoteUrl, s.configServiceDomains, nil)
 			s.test(hostingServiceMgr.GetPullRequestURL(s.from, s.to))
 			log.AssertErrors(t, s.expectedLoggedErrors)
 		})
 	}
 }
+
+func TestBrowseURLs(t *testing.T) {
+	type scenario struct {
+		testName             string
+		remoteUrl            string
+		configServiceDomains map[string]string
+		configURLTemplates   map[string]config.URLTemplatesConfig
+		getURL               func(*HostingServiceMgr) (string, error)
+		expected             string
+	}
+
+	scenarios := []scenario{
+		{
+			testName:  "Opens a file on github",
+			remoteUrl: "git@github.com:peter/calculator.git",
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetFileURL("abc123", "pkg/my file#1.go")
+			},
+			expected: "https://github.com/peter/calculator/blob/abc123/pkg/my%20file%231.go",
+		},
+		{
+			testName:  "Opens a line on github",
+			remoteUrl: "git@github.com:peter/calculator.git",
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetLinesURL("abc123", "main.go", 7, 7)
+			},
+			expected: "https://github.com/peter/calculator/blob/abc123/main.go#L7",
+		},
+		{
+			testName:  "Opens a range of lines on gitlab",
+			remoteUrl: "git@gitlab.com:peter/calculator.git",
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetLinesURL("abc123", "main.go", 7, 12)
+			},
+			expected: "https://gitlab.com/peter/calculator/-/blob/abc123/main.go#L7-12",
+		},
+		{
+			testName:  "Opens a range of lines on bitbucket",
+			remoteUrl: "git@bitbucket.org:peter/calculator.git",
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetLinesURL("abc123", "main.go", 7, 12)
+			},
+			expected: "https://bitbucket.org/peter/calculator/src/abc123/main.go#lines-7:12",
+		},
+		{
+			testName:  "Opens a branch with slashes in its name on gitea",
+			remoteUrl: "git@gitea.com:peter/calculator.git",
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetBranchURL("feature/multiply")
+			},
+			expected: "https://gitea.com/peter/calculator/src/branch/feature/multiply",
+		},
+		{
+			testName:  "Opens a tag on gitlab",
+			remoteUrl: "https://gitlab.com/peter/calculator.git",
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetTagURL("v1.0.0")
+			},
+			expected: "https://gitlab.com/peter/calculator/-/tags/v1.0.0",
+		},
+		{
+			testName:  "Compares two refs on github",
+			remoteUrl: "git@github.com:peter/calculator.git",
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetCompareURL("master", "feature/multiply")
+			},
+			expected: "https://github.com/peter/calculator/compare/master...feature/multiply",
+		},
+		{
+			testName:  "Compares two refs on bitbucket",
+			remoteUrl: "git@bitbucket.org:peter/calculator.git",
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetCompareURL("master", "feature")
+			},
+			expected: "https://bitbucket.org/peter/calculator/branches/compare/feature%0Dmaster#diff",
+		},
+		{
+			testName:  "Uses the provider's templates from the config",
+			remoteUrl: "git@gitlab.com:peter/calculator.git",
+			configURLTemplates: map[string]config.URLTemplatesConfig{
+				"gitlab": {Tag: "/-/releases/{{.Tag}}"},
+			},
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetTagURL("v1.0.0")
+			},
+			expected: "https://gitlab.com/peter/calculator/-/releases/v1.0.0",
+		},
+		{
+			testName:  "Prefers the domain's templates to the provider's",
+			remoteUrl: "git@git.work.com:peter/calculator.git",
+			configServiceDomains: map[string]string{
+				"git.work.com": "github:code.work.com",
+			},
+			configURLTemplates: map[string]config.URLTemplatesConfig{
+				"github":        {File: "/files/{{.Ref}}/{{.Path}}", Branch: "/branches/{{.Branch}}"},
+				"code.work.com": {File: "/view/{{.Path}}?at={{.Ref}}"},
+			},
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetFileURL("abc123", "main.go")
+			},
+			expected: "https://code.work.com/peter/calculator/view/main.go?at=abc123",
+		},
+		{
+			testName:  "Keeps the templates that the config doesn't override",
+			remoteUrl: "git@git.work.com:peter/calculator.git",
+			configServiceDomains: map[string]string{
+				"git.work.com": "github:code.work.com",
+			},
+			configURLTemplates: map[string]config.URLTemplatesConfig{
+				"code.work.com": {File: "/view/{{.Path}}?at={{.Ref}}"},
+			},
+			getURL: func(mgr *HostingServiceMgr) (string, error) {
+				return mgr.GetLinesURL("abc123", "main.go", 1, 2)
+			},
+			expected: "https://code.work.com/peter/calculator/blob/abc123/main.go#L1-L2",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			tr := i18n.EnglishTranslationSet()
+			hostingServiceMgr := NewHostingServiceMgr(&test.FakeFieldLogger{}, &tr, s.remoteUrl, s.configServiceDomains, s.configURLTemplates)
+			url, err := s.getURL(hostingServiceMgr)
+			assert.NoError(t, err)
+			assert.Equal(t, s.expected, url)
+		})
+	}
+}
+
+func TestGetRepoURL(t *testing.T) {
+	tr := i18n.EnglishTranslationSet()
+	hostingServiceMgr := NewHostingServiceMgr(&test.FakeFieldLogger{}, &tr, "git@git.work.com:peter/lazygit.git", map[string]string{"git.work.com": "gitlab:code.work.com"}, nil)
+
+	repoURL, err := hostingServiceMgr.GetRepoURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://code.work.com/peter/lazygit", repoURL)
+}
+
+func TestValidateURLTemplates(t *testing.T) {
+	for _, serviceDefinition := range serviceDefinitions {
+		assert.NoError(t, ValidateURLTemplates(serviceDefinition.urlTemplates), serviceDefinition.provider)
+	}
+
+	scenarios := []struct {
+		testName    string
+		templates   config.URLTemplatesConfig
+		expectedErr string
+	}{
+		{
+			testName:  "placeholders without a dot",
+			templates: config.URLTemplatesConfig{Branch: "/tree/{{Branch}}"},
+		},
+		{
+			testName:    "misspelt key",
+			templates:   config.URLTemplatesConfig{Line: "/blob/{{.Ref}}/{{.Path}}#L{{.Lne}}"},
+			expectedErr: "line: '/blob/{{.Ref}}/{{.Path}}#L{{.Lne}}' has a placeholder other than {{.Ref}}, {{.Path}}, {{.Line}}",
+		},
+		{
+			testName:    "key from another template",
+			templates:   config.URLTemplatesConfig{Tag: "/tags/{{.Branch}}"},
+			expectedErr: "tag: '/tags/{{.Branch}}' has a placeholder other than {{.Tag}}",
+		},
+		{
+			testName:    "template syntax",
+			templates:   config.URLTemplatesConfig{Compare: "/compare/{{ .From }}...{{.To}}"},
+			expectedErr: "compare: '/compare/{{ .From }}...{{.To}}' has a placeholder other than {{.From}}, {{.To}}",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			err := ValidateURLTemplates(s.templates)
+			if s.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, s.expectedErr)
+			}
+		})
+	}
+}
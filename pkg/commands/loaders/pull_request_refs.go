@@ -0,0 +1,75 @@
+package loaders
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/jesseduffield/lazygit/pkg/common"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// When we can't ask the hosting service's API about pull requests, we can still
+// fetch the refs it keeps their heads in. All we know of a pull request then is
+// its number and head commit, so we go by the commit for the title and author.
+
+type PullRequestRefLoader struct {
+	*common.Common
+	cmd oscommands.ICmdObjBuilder
+}
+
+func NewPullRequestRefLoader(
+	common *common.Common,
+	cmd oscommands.ICmdObjBuilder,
+) *PullRequestRefLoader {
+	return &PullRequestRefLoader{
+		Common: common,
+		cmd:    cmd,
+	}
+}
+
+// Load returns the pull requests whose heads we've fetched into
+// refs/<namespace>/<number>/head, most recent first
+func (self *PullRequestRefLoader) Load(namespace string) ([]*models.PullRequest, error) {
+	output, err := self.cmd.New(
+		fmt.Sprintf(`git for-each-ref --format="%%(refname)%%00%%(objectname)%%00%%(authorname)%%00%%(subject)" %s`, self.cmd.Quote("refs/"+namespace+"/")),
+	).DontLog().RunWithOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests := []*models.PullRequest{}
+	for _, line := range utils.SplitLines(output) {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) < 4 {
+			continue
+		}
+
+		// e.g. refs/pull/5/head
+		refParts := strings.Split(fields[0], "/")
+		if len(refParts) != 4 || refParts[3] != "head" {
+			continue
+		}
+		number, err := strconv.Atoi(refParts[2])
+		if err != nil {
+			continue
+		}
+
+		pullRequests = append(pullRequests, &models.PullRequest{
+			Number:   number,
+			Title:    fields[3],
+			Author:   fields[2],
+			HeadSha:  fields[1],
+			LocalRef: fields[0],
+		})
+	}
+
+	sort.Slice(pullRequests, func(i, j int) bool {
+		return pullRequests[i].Number > pullRequests[j].Number
+	})
+
+	return pullRequests, nil
+}
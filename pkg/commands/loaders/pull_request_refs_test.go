@@ -0,0 +1,30 @@
+package loaders
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPullRequestRefs(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		Expect(
+			`git for-each-ref --format="%(refname)%00%(objectname)%00%(authorname)%00%(subject)" "refs/pull/"`,
+			"refs/pull/5/head\x00aaa\x00Jesse\x00Add a feature\n"+
+				"refs/pull/5/merge\x00bbb\x00Jesse\x00Merge aaa into ccc\n"+
+				"refs/pull/12/head\x00ddd\x00Mark\x00Fix a bug: the sequel\n",
+			nil,
+		)
+
+	loader := NewPullRequestRefLoader(utils.NewDummyCommon(), oscommands.NewDummyCmdObjBuilder(runner))
+	pullRequests, err := loader.Load("pull")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*models.PullRequest{
+		{Number: 12, Title: "Fix a bug: the sequel", Author: "Mark", HeadSha: "ddd", LocalRef: "refs/pull/12/head"},
+		{Number: 5, Title: "Add a feature", Author: "Jesse", HeadSha: "aaa", LocalRef: "refs/pull/5/head"},
+	}, pullRequests)
+	runner.CheckForMissingCalls()
+}
@@ -68,3 +68,33 @@ func (self *ReflogCommitLoader) GetReflogCommits(lastReflogCommit *models.Commit
 
 	return commits, onlyObtainedNewReflogCommits, nil
 }
+
+// GetReflogTimes returns the unix timestamp of each reflog entry, most recent
+// first. Unlike the timestamps of the commits GetReflogCommits returns, these
+// tell us when the entry itself was made.
+func (self *ReflogCommitLoader) GetReflogTimes(filterPath string) ([]int64, error) {
+	filterPathArg := ""
+	if filterPath != "" {
+		filterPathArg = fmt.Sprintf(" --follow -- %s", self.cmd.Quote(filterPath))
+	}
+
+	times := []int64{}
+	cmdObj := self.cmd.New(fmt.Sprintf(`git log -g --date=unix --format="%%gd"%s`, filterPathArg)).DontLog()
+	err := cmdObj.RunAndProcessLines(func(line string) (bool, error) {
+		// the reflog selector looks like HEAD@{1643150483}
+		start := strings.Index(line, "{")
+		end := strings.LastIndex(line, "}")
+		if start == -1 || end < start {
+			return false, nil
+		}
+
+		unixTimestamp, _ := strconv.Atoi(line[start+1 : end])
+		times = append(times, int64(unixTimestamp))
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return times, nil
+}
@@ -157,3 +157,17 @@ func TestGetReflogCommits(t *testing.T) {
 		})
 	}
 }
+
+func TestGetReflogTimes(t *testing.T) {
+	runner := oscommands.NewFakeRunner(t).
+		Expect(`git log -g --date=unix --format="%gd"`, "HEAD@{1643150483}\nHEAD@{1643149435}\n", nil)
+	builder := &ReflogCommitLoader{
+		Common: utils.NewDummyCommon(),
+		cmd:    oscommands.NewDummyCmdObjBuilder(runner),
+	}
+
+	times, err := builder.GetReflogTimes("")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1643150483, 1643149435}, times)
+	runner.CheckForMissingCalls()
+}
@@ -0,0 +1,15 @@
+package models
+
+// ConflictStage is one side of a conflicted file as it appears in the index
+// (see `git ls-files -u`). Stage 1 is the common ancestor, 2 is ours and 3 is
+// theirs. A side which doesn't have the file has no stage at all.
+type ConflictStage struct {
+	Mode  string
+	Sha   string
+	Stage int
+}
+
+// submodules are recorded in the index as a commit rather than a blob
+func (s *ConflictStage) IsSubmodule() bool {
+	return s.Mode == "160000"
+}
@@ -19,6 +19,7 @@ type File struct {
 	DisplayString           string
 	Type                    string // one of 'file', 'directory', and 'other'
 	ShortStatus             string // e.g. 'AD', ' A', 'M ', '??'
+	ResolvedByRerere        bool   // set by the gui when rerere resolved the file's conflicts
 }
 
 // sometimes we need to deal with either a node (which contains a file) or an actual file
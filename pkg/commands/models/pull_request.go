@@ -0,0 +1,78 @@
+package models
+
+import "strconv"
+
+type PullRequestReviewState string
+
+const (
+	PR_REVIEW_NONE              PullRequestReviewState = ""
+	PR_REVIEW_APPROVED          PullRequestReviewState = "approved"
+	PR_REVIEW_CHANGES_REQUESTED PullRequestReviewState = "changesRequested"
+)
+
+type PullRequestCIStatus string
+
+const (
+	PR_CI_NONE    PullRequestCIStatus = ""
+	PR_CI_PENDING PullRequestCIStatus = "pending"
+	PR_CI_SUCCESS PullRequestCIStatus = "success"
+	PR_CI_FAILURE PullRequestCIStatus = "failure"
+)
+
+// PullRequest is an open pull request (or merge request, as GitLab calls them)
+// on the service hosting the repo
+type PullRequest struct {
+	Number int
+	Title  string
+	Author string
+	// the branch the changes are in, and the branch they're to be merged into
+	HeadBranch string
+	BaseBranch string
+	HeadSha    string
+	URL        string
+	IsDraft    bool
+	// the head branch is in someone else's fork of the repo rather than in the
+	// repo itself, so it's not one of our branches even if it has the same name
+	IsFromFork bool
+	// the ref we fetch the pull request's head into, e.g. refs/pull/5/head
+	LocalRef string
+
+	// we only find these out for some pull requests, e.g. those for our own
+	// branches, because it takes a request per pull request
+	ReviewState PullRequestReviewState
+	CIStatus    PullRequestCIStatus
+}
+
+func (pr *PullRequest) RefName() string {
+	return "#" + strconv.Itoa(pr.Number)
+}
+
+func (pr *PullRequest) ID() string {
+	if pr.LocalRef != "" {
+		return pr.LocalRef
+	}
+	return pr.RefName()
+}
+
+func (pr *PullRequest) Description() string {
+	return pr.RefName() + " " + pr.Title
+}
+
+// CombineCIStatuses sums up the statuses of several CI checks: any failure
+// means failure, otherwise anything pending means pending
+func CombineCIStatuses(statuses []PullRequestCIStatus) PullRequestCIStatus {
+	result := PR_CI_NONE
+	for _, status := range statuses {
+		switch status {
+		case PR_CI_FAILURE:
+			return PR_CI_FAILURE
+		case PR_CI_PENDING:
+			result = PR_CI_PENDING
+		case PR_CI_SUCCESS:
+			if result == PR_CI_NONE {
+				result = PR_CI_SUCCESS
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// RecordedResolution is an entry in the rr-cache, which is where rerere keeps
+// track of how conflicts were resolved
+type RecordedResolution struct {
+	ID         string
+	RecordedAt time.Time
+	// false if rerere has only seen the conflict, and not yet its resolution
+	Resolved bool
+	// the first line of the conflict, for telling resolutions apart
+	Preview string
+}
@@ -97,8 +97,19 @@ func (hunk *PatchHunk) formatHeader(oldStart int, oldLength int, newStart int, n
 	return fmt.Sprintf("@@ -%d,%d +%d,%d @@%s\n", oldStart, oldLength, newStart, newLength, heading)
 }
 
-func (hunk *PatchHunk) formatWithChanges(lineIndices []int, reverse bool, startOffset int) (int, string) {
-	bodyLines := hunk.updatedLines(lineIndices, reverse)
+// fromRange returns the first line number and the number of lines the hunk
+// covers on the side of the diff that the patch will be applied to
+func (hunk *PatchHunk) fromRange(reverse bool) (int, int) {
+	lines := hunk.bodyLinesWithoutTrailingEmpty()
+	if reverse {
+		return hunk.newStart, nLinesWithPrefix(lines, []string{"+", " "})
+	}
+	return hunk.oldStart, nLinesWithPrefix(lines, []string{"-", " "})
+}
+
+// formatLines formats the hunk with the given body lines, as returned by
+// updatedLines for the hunk and for any hunks merged into it
+func (hunk *PatchHunk) formatLines(bodyLines []string, reverse bool, startOffset int) (int, string) {
 	startOffset, header, ok := hunk.updatedHeader(bodyLines, startOffset, reverse)
 	if !ok {
 		return startOffset, ""
@@ -0,0 +1,103 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// When the user edits a hunk by hand (as with `git add -e`) they're free to
+// add, remove and change lines, so the hunk headers we gave them are unlikely
+// to still be right. We recount them before handing the patch to git.
+
+// HunkEditCommentChar starts the lines of a hand-edited patch which we ignore
+const HunkEditCommentChar = "#"
+
+// RecountHunks strips the comment lines from a hand-edited patch and
+// recalculates its hunk headers. It returns an empty string if the patch no
+// longer changes anything.
+func RecountHunks(patch string) (string, error) {
+	// trailing blank lines are more likely to be the editor's doing than the
+	// user's, so we drop them
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(patch, "\r\n", "\n"), "\n"), "\n")
+
+	var result strings.Builder
+	var hunkLines []string
+	hunkHeader := ""
+	offset := 0
+	changed := false
+
+	flushHunk := func() {
+		if hunkHeader == "" {
+			return
+		}
+
+		changeCount := nLinesWithPrefix(hunkLines, []string{"+", "-"})
+		if changeCount > 0 {
+			oldStart, _, heading := headerInfo(hunkHeader)
+			oldLength := nLinesWithPrefix(hunkLines, []string{" ", "-"})
+			newLength := nLinesWithPrefix(hunkLines, []string{" ", "+"})
+
+			// an empty side's start refers to the line before the hunk
+			newStart := oldStart + offset
+			if oldLength == 0 {
+				newStart++
+			}
+			if newLength == 0 {
+				newStart--
+			}
+			offset += newLength - oldLength
+
+			result.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@%s\n", oldStart, oldLength, newStart, newLength, heading))
+			for _, line := range hunkLines {
+				result.WriteString(line + "\n")
+			}
+			changed = true
+		}
+
+		hunkHeader = ""
+		hunkLines = nil
+	}
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, HunkEditCommentChar) {
+			continue
+		}
+
+		if strings.HasPrefix(line, "@@") {
+			flushHunk()
+			if !hunkHeaderRegexp.MatchString(line) {
+				return "", fmt.Errorf("line %d: invalid hunk header: %s", i+1, line)
+			}
+			hunkHeader = line
+			continue
+		}
+
+		if hunkHeader == "" {
+			// the file header
+			if line != "" {
+				result.WriteString(line + "\n")
+			}
+			continue
+		}
+
+		if line == "" {
+			// like git, we take an empty line to be an empty line of context,
+			// because some editors strip trailing whitespace
+			line = " "
+		}
+
+		if !utils.IncludesString([]string{" ", "+", "-", "\\"}, line[:1]) {
+			return "", fmt.Errorf("line %d: lines must start with ' ', '+' or '-': %s", i+1, line)
+		}
+		hunkLines = append(hunkLines, line)
+	}
+	flushHunk()
+
+	if !changed {
+		return "", nil
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,133 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecountHunks(t *testing.T) {
+	type scenario struct {
+		name          string
+		patch         string
+		expected      string
+		expectedError string
+	}
+
+	scenarios := []scenario{
+		{
+			name: "unchanged",
+			patch: `--- a/filename
++++ b/filename
+@@ -1,3 +1,3 @@ heading
+ apple
+-grape
++grapes
+ kiwi
+`,
+			expected: `--- a/filename
++++ b/filename
+@@ -1,3 +1,3 @@ heading
+ apple
+-grape
++grapes
+ kiwi
+`,
+		},
+		{
+			name: "lines added and removed, comments and an emptied context line",
+			patch: `--- a/filename
++++ b/filename
+@@ -10,4 +10,4 @@
+ apple
+-grape
++grapes
++mango
+
+ kiwi
+# a comment
+`,
+			expected: `--- a/filename
++++ b/filename
+@@ -10,4 +10,5 @@
+ apple
+-grape
++grapes
++mango
+ 
+ kiwi
+`,
+		},
+		{
+			name: "the second hunk moves to make way for the first",
+			patch: `--- a/filename
++++ b/filename
+@@ -1,1 +1,2 @@
+ apple
++banana
++cherry
+@@ -10,2 +11,1 @@
+ kiwi
+-lime
+`,
+			expected: `--- a/filename
++++ b/filename
+@@ -1,1 +1,3 @@
+ apple
++banana
++cherry
+@@ -10,2 +12,1 @@
+ kiwi
+-lime
+`,
+		},
+		{
+			name: "adding to an empty file",
+			patch: `--- a/filename
++++ b/filename
+@@ -0,0 +1,1 @@
++first
++second
+`,
+			expected: `--- a/filename
++++ b/filename
+@@ -0,0 +1,2 @@
++first
++second
+`,
+		},
+		{
+			name: "no changes left",
+			patch: `--- a/filename
++++ b/filename
+@@ -1,2 +1,2 @@
+ apple
+ grape
+`,
+			expected: "",
+		},
+		{
+			name: "a line with a bad prefix",
+			patch: `--- a/filename
++++ b/filename
+@@ -1,2 +1,2 @@
+ apple
+*grape
+`,
+			expectedError: "line 5: lines must start with ' ', '+' or '-': *grape",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			result, err := RecountHunks(s.patch)
+			if s.expectedError != "" {
+				assert.EqualError(t, err, s.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, s.expected, result)
+		})
+	}
+}
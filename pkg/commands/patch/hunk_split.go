@@ -0,0 +1,160 @@
+package patch
+
+import (
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// Like `git add -p`'s split command, we can split a hunk at the runs of context
+// lines between its changes, so that each change can be staged on its own. The
+// context lines at a split go in both hunks: as trailing context for one and
+// leading context for the next. Git won't apply hunks that overlap like that,
+// so when a selection takes in more than one of the hunks, the patch modifier
+// merges them back together.
+//
+// We identify a split by the new-side line number of the first line of the
+// context run, because when staging, the new side is the working tree, which
+// doesn't change as hunks get staged. That lets us split the hunks again each
+// time the diff is reloaded.
+
+type contextRun struct {
+	// the index in the hunk's body lines of the first and one-past-last lines
+	start int
+	end   int
+
+	oldLineNumber int
+	newLineNumber int
+}
+
+// bodyLinesWithoutTrailingEmpty drops the empty string that follows the final
+// newline of the diff
+func (hunk *PatchHunk) bodyLinesWithoutTrailingEmpty() []string {
+	lines := hunk.bodyLines
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// interiorContextRuns returns the runs of context lines which have changes both
+// before and after them within the hunk
+func (hunk *PatchHunk) interiorContextRuns() []*contextRun {
+	runs := []*contextRun{}
+	var current *contextRun
+	seenChange := false
+
+	oldLineNumber, newLineNumber := hunk.oldStart, hunk.newStart
+	for i, line := range hunk.bodyLinesWithoutTrailingEmpty() {
+		switch line[:1] {
+		case " ":
+			if current == nil && seenChange {
+				current = &contextRun{start: i, oldLineNumber: oldLineNumber, newLineNumber: newLineNumber}
+			}
+			oldLineNumber++
+			newLineNumber++
+		case "-", "+":
+			if current != nil {
+				current.end = i
+				runs = append(runs, current)
+				current = nil
+			}
+			seenChange = true
+			if line[:1] == "-" {
+				oldLineNumber++
+			} else {
+				newLineNumber++
+			}
+		}
+	}
+
+	return runs
+}
+
+// split splits the hunk at those of its interior context runs which start at
+// the given new-side line numbers
+func (hunk *PatchHunk) split(newLineNumbers []int) []*PatchHunk {
+	lines := hunk.bodyLinesWithoutTrailingEmpty()
+
+	pieces := []*PatchHunk{}
+	pieceStart := 0
+	oldStart, newStart := hunk.oldStart, hunk.newStart
+	for _, run := range hunk.interiorContextRuns() {
+		if !utils.IncludesInt(newLineNumbers, run.newLineNumber) {
+			continue
+		}
+
+		pieces = append(pieces, &PatchHunk{
+			oldStart:  oldStart,
+			newStart:  newStart,
+			heading:   hunk.heading,
+			bodyLines: lines[pieceStart:run.end],
+		})
+		pieceStart = run.start
+		oldStart, newStart = run.oldLineNumber, run.newLineNumber
+	}
+
+	if len(pieces) == 0 {
+		return []*PatchHunk{hunk}
+	}
+
+	return append(pieces, &PatchHunk{
+		oldStart:  oldStart,
+		newStart:  newStart,
+		heading:   hunk.heading,
+		bodyLines: lines[pieceStart:],
+	})
+}
+
+// HunkSplitPoints returns the new-side line numbers at which the hunk
+// containing the given line of the diff can be split. It returns nil if the
+// hunk can't be split.
+func HunkSplitPoints(diff string, lineIdx int) []int {
+	for _, hunk := range GetHunksFromDiff(diff) {
+		if lineIdx < hunk.FirstLineIdx || lineIdx > hunk.LastLineIdx() {
+			continue
+		}
+
+		var points []int
+		for _, run := range hunk.interiorContextRuns() {
+			points = append(points, run.newLineNumber)
+		}
+		return points
+	}
+
+	return nil
+}
+
+// SplitHunksAt splits the hunks of the diff at the context runs starting at the
+// given new-side line numbers
+func SplitHunksAt(diff string, newLineNumbers []int) string {
+	hunks := GetHunksFromDiff(diff)
+	if len(hunks) == 0 || len(newLineNumbers) == 0 {
+		return diff
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+
+	var result strings.Builder
+	// everything before the first hunk is the file header
+	for _, line := range lines[:hunks[0].FirstLineIdx] {
+		result.WriteString(line)
+	}
+
+	for _, hunk := range hunks {
+		pieces := hunk.split(newLineNumbers)
+		if len(pieces) == 1 {
+			result.WriteString(lines[hunk.FirstLineIdx])
+			result.WriteString(strings.Join(hunk.bodyLines, ""))
+			continue
+		}
+
+		for _, piece := range pieces {
+			_, header, _ := piece.updatedHeader(piece.bodyLines, piece.newStart-piece.oldStart, false)
+			result.WriteString(header)
+			result.WriteString(strings.Join(piece.bodyLines, ""))
+		}
+	}
+
+	return result.String()
+}
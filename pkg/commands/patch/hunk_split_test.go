@@ -0,0 +1,138 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const splittableDiff = `diff --git a/filename b/filename
+index dcd3485..1ba5540 100644
+--- a/filename
++++ b/filename
+@@ -1,9 +1,9 @@ heading
+ apple
+-grape
++grapes
+ kiwi
+ lemon
+-mango
++mangoes
+ orange
++peach
+ pear
+`
+
+func TestHunkSplitPoints(t *testing.T) {
+	// line 6 is '-grape'
+	assert.EqualValues(t, []int{3, 6}, HunkSplitPoints(splittableDiff, 6))
+	// a hunk with a single change can't be split
+	assert.Nil(t, HunkSplitPoints(simpleDiff, 5))
+}
+
+func TestSplitHunksAt(t *testing.T) {
+	type scenario struct {
+		name           string
+		newLineNumbers []int
+		expected       string
+	}
+
+	scenarios := []scenario{
+		{
+			name:           "no split points",
+			newLineNumbers: nil,
+			expected:       splittableDiff,
+		},
+		{
+			name:           "split everywhere",
+			newLineNumbers: []int{3, 6},
+			expected: `diff --git a/filename b/filename
+index dcd3485..1ba5540 100644
+--- a/filename
++++ b/filename
+@@ -1,4 +1,4 @@ heading
+ apple
+-grape
++grapes
+ kiwi
+ lemon
+@@ -3,4 +3,4 @@ heading
+ kiwi
+ lemon
+-mango
++mangoes
+ orange
+@@ -6,2 +6,3 @@ heading
+ orange
++peach
+ pear
+`,
+		},
+		{
+			name:           "split at the second context run only",
+			newLineNumbers: []int{6},
+			expected: `diff --git a/filename b/filename
+index dcd3485..1ba5540 100644
+--- a/filename
++++ b/filename
+@@ -1,6 +1,6 @@ heading
+ apple
+-grape
++grapes
+ kiwi
+ lemon
+-mango
++mangoes
+ orange
+@@ -6,2 +6,3 @@ heading
+ orange
++peach
+ pear
+`,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			assert.Equal(t, s.expected, SplitHunksAt(splittableDiff, s.newLineNumbers))
+		})
+	}
+}
+
+func TestStagingASplitHunk(t *testing.T) {
+	diff := SplitHunksAt(splittableDiff, []int{3, 6})
+
+	// the second hunk's lines are '-mango' and '+mangoes'
+	hunk := GetHunksFromDiff(diff)[1]
+	result := ModifiedPatchForRange(nil, "filename", diff, hunk.FirstLineIdx, hunk.LastLineIdx(), false, false)
+	assert.Equal(t, `--- a/filename
++++ b/filename
+@@ -3,4 +3,4 @@ heading
+ kiwi
+ lemon
+-mango
++mangoes
+ orange
+`, result)
+}
+
+func TestStagingAcrossSplitHunks(t *testing.T) {
+	diff := SplitHunksAt(splittableDiff, []int{3, 6})
+
+	// from '+grapes' in the first hunk to '-mango' in the second. The hunks
+	// share 'kiwi' and 'lemon', so they go back together as one hunk.
+	hunks := GetHunksFromDiff(diff)
+	result := ModifiedPatchForRange(nil, "filename", diff, hunks[0].FirstLineIdx+3, hunks[1].FirstLineIdx+3, false, false)
+	assert.Equal(t, `--- a/filename
++++ b/filename
+@@ -1,6 +1,6 @@ heading
+ apple
+ grape
++grapes
+ kiwi
+ lemon
+-mango
+ orange
+`, result)
+}
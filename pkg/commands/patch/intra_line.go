@@ -0,0 +1,182 @@
+package patch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// the job of this file is to work out which parts of a changed line actually
+// changed, by comparing it against the line it replaced, so that we can
+// highlight those parts when rendering the diff.
+
+// these are the values for the git.diffHighlight config option
+const (
+	INTRA_LINE_HIGHLIGHT_WORD = "word"
+	INTRA_LINE_HIGHLIGHT_CHAR = "char"
+	INTRA_LINE_HIGHLIGHT_NONE = "none"
+)
+
+// comparing two long lines token by token is quadratic, so beyond this many
+// token pairs we give up and treat everything between the common prefix and
+// suffix as changed
+const MAX_INTRA_LINE_COMPARISONS = 40000
+
+// changedRange is a range of bytes within a line
+type changedRange struct {
+	start int
+	end   int
+}
+
+type lineToken struct {
+	start int
+	end   int
+	text  string
+}
+
+// intraLineChanges returns the ranges of oldLine and newLine which are not
+// shared between the two lines. If the lines have nothing in common we return
+// no ranges, because highlighting the whole of both lines would tell us
+// nothing we didn't already know.
+func intraLineChanges(oldLine string, newLine string, mode string) ([]changedRange, []changedRange) {
+	if mode != INTRA_LINE_HIGHLIGHT_WORD && mode != INTRA_LINE_HIGHLIGHT_CHAR {
+		return nil, nil
+	}
+
+	oldTokens := tokenizeLine(oldLine, mode)
+	newTokens := tokenizeLine(newLine, mode)
+
+	oldUnchanged, newUnchanged := commonTokens(oldTokens, newTokens)
+
+	sharesContent := false
+	for i, token := range oldTokens {
+		if oldUnchanged[i] && strings.TrimSpace(token.text) != "" {
+			sharesContent = true
+			break
+		}
+	}
+	if !sharesContent {
+		return nil, nil
+	}
+
+	return changedRanges(oldTokens, oldUnchanged), changedRanges(newTokens, newUnchanged)
+}
+
+// tokenizeLine splits a line into words, runs of whitespace, and individual
+// symbols, or into individual characters if that's what the user wants
+func tokenizeLine(line string, mode string) []lineToken {
+	tokens := []lineToken{}
+
+	tokenClass := func(r rune) int {
+		if mode == INTRA_LINE_HIGHLIGHT_CHAR {
+			return 0
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		default:
+			return 0
+		}
+	}
+
+	start := 0
+	for start < len(line) {
+		r, size := utf8.DecodeRuneInString(line[start:])
+		end := start + size
+		class := tokenClass(r)
+		// class 0 tokens are always a single character
+		for class != 0 && end < len(line) {
+			next, nextSize := utf8.DecodeRuneInString(line[end:])
+			if tokenClass(next) != class {
+				break
+			}
+			end += nextSize
+		}
+
+		tokens = append(tokens, lineToken{start: start, end: end, text: line[start:end]})
+		start = end
+	}
+
+	return tokens
+}
+
+// commonTokens tells us, for each token of each side, whether it's part of the
+// longest common subsequence of the two sides
+func commonTokens(oldTokens []lineToken, newTokens []lineToken) ([]bool, []bool) {
+	oldUnchanged := make([]bool, len(oldTokens))
+	newUnchanged := make([]bool, len(newTokens))
+
+	prefixLength := 0
+	for prefixLength < len(oldTokens) && prefixLength < len(newTokens) &&
+		oldTokens[prefixLength].text == newTokens[prefixLength].text {
+		oldUnchanged[prefixLength] = true
+		newUnchanged[prefixLength] = true
+		prefixLength++
+	}
+
+	suffixLength := 0
+	for suffixLength < len(oldTokens)-prefixLength && suffixLength < len(newTokens)-prefixLength &&
+		oldTokens[len(oldTokens)-1-suffixLength].text == newTokens[len(newTokens)-1-suffixLength].text {
+		oldUnchanged[len(oldTokens)-1-suffixLength] = true
+		newUnchanged[len(newTokens)-1-suffixLength] = true
+		suffixLength++
+	}
+
+	oldMiddle := oldTokens[prefixLength : len(oldTokens)-suffixLength]
+	newMiddle := newTokens[prefixLength : len(newTokens)-suffixLength]
+	if len(oldMiddle) == 0 || len(newMiddle) == 0 || len(oldMiddle)*len(newMiddle) > MAX_INTRA_LINE_COMPARISONS {
+		return oldUnchanged, newUnchanged
+	}
+
+	// lengths[i][j] is the length of the longest common subsequence of
+	// oldMiddle[i:] and newMiddle[j:]
+	lengths := make([][]int, len(oldMiddle)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(newMiddle)+1)
+	}
+	for i := len(oldMiddle) - 1; i >= 0; i-- {
+		for j := len(newMiddle) - 1; j >= 0; j-- {
+			if oldMiddle[i].text == newMiddle[j].text {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(oldMiddle) && j < len(newMiddle) {
+		if oldMiddle[i].text == newMiddle[j].text {
+			oldUnchanged[prefixLength+i] = true
+			newUnchanged[prefixLength+j] = true
+			i++
+			j++
+		} else if lengths[i+1][j] >= lengths[i][j+1] {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return oldUnchanged, newUnchanged
+}
+
+// changedRanges merges consecutive changed tokens into ranges
+func changedRanges(tokens []lineToken, unchanged []bool) []changedRange {
+	ranges := []changedRange{}
+	for i, token := range tokens {
+		if unchanged[i] {
+			continue
+		}
+		if len(ranges) > 0 && ranges[len(ranges)-1].end == token.start {
+			ranges[len(ranges)-1].end = token.end
+			continue
+		}
+		ranges = append(ranges, changedRange{start: token.start, end: token.end})
+	}
+	return ranges
+}
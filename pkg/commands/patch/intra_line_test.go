@@ -0,0 +1,115 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntraLineChanges(t *testing.T) {
+	scenarios := []struct {
+		testName        string
+		oldLine         string
+		newLine         string
+		mode            string
+		expectedOldDiff []string
+		expectedNewDiff []string
+	}{
+		{
+			testName:        "word mode",
+			oldLine:         "foo(bar, baz)",
+			newLine:         "foo(qux, baz)",
+			mode:            INTRA_LINE_HIGHLIGHT_WORD,
+			expectedOldDiff: []string{"bar"},
+			expectedNewDiff: []string{"qux"},
+		},
+		{
+			testName:        "word mode highlights whole words",
+			oldLine:         "let total = count + 1",
+			newLine:         "let total = counter + 2",
+			mode:            INTRA_LINE_HIGHLIGHT_WORD,
+			expectedOldDiff: []string{"count", "1"},
+			expectedNewDiff: []string{"counter", "2"},
+		},
+		{
+			testName:        "char mode",
+			oldLine:         "let total = count + 1",
+			newLine:         "let total = counter + 2",
+			mode:            INTRA_LINE_HIGHLIGHT_CHAR,
+			expectedOldDiff: []string{"1"},
+			expectedNewDiff: []string{"er", "2"},
+		},
+		{
+			testName:        "insertion only",
+			oldLine:         "a b",
+			newLine:         "a new b",
+			mode:            INTRA_LINE_HIGHLIGHT_WORD,
+			expectedOldDiff: []string{},
+			expectedNewDiff: []string{"new "},
+		},
+		{
+			testName:        "multibyte characters",
+			oldLine:         "héllo wörld",
+			newLine:         "héllo wôrld",
+			mode:            INTRA_LINE_HIGHLIGHT_CHAR,
+			expectedOldDiff: []string{"ö"},
+			expectedNewDiff: []string{"ô"},
+		},
+		{
+			testName:        "nothing in common",
+			oldLine:         "foo",
+			newLine:         "bar",
+			mode:            INTRA_LINE_HIGHLIGHT_WORD,
+			expectedOldDiff: nil,
+			expectedNewDiff: nil,
+		},
+		{
+			testName:        "only whitespace in common",
+			oldLine:         "a b",
+			newLine:         "c d",
+			mode:            INTRA_LINE_HIGHLIGHT_WORD,
+			expectedOldDiff: nil,
+			expectedNewDiff: nil,
+		},
+		{
+			testName:        "disabled",
+			oldLine:         "foo(bar, baz)",
+			newLine:         "foo(qux, baz)",
+			mode:            INTRA_LINE_HIGHLIGHT_NONE,
+			expectedOldDiff: nil,
+			expectedNewDiff: nil,
+		},
+	}
+
+	changedStrings := func(line string, ranges []changedRange) []string {
+		if ranges == nil {
+			return nil
+		}
+		result := []string{}
+		for _, r := range ranges {
+			result = append(result, line[r.start:r.end])
+		}
+		return result
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			oldRanges, newRanges := intraLineChanges(s.oldLine, s.newLine, s.mode)
+			assert.EqualValues(t, s.expectedOldDiff, changedStrings(s.oldLine, oldRanges))
+			assert.EqualValues(t, s.expectedNewDiff, changedStrings(s.newLine, newRanges))
+		})
+	}
+}
+
+func TestChangedLinePairs(t *testing.T) {
+	parser := NewPatchParser(nil, "@@ -1,4 +1,3 @@\n-a\n-b\n+c\n x\n-d\n+e\n+f\n", PatchParserOpts{})
+
+	pairs := changedLinePairs(parser.PatchLines)
+
+	contents := [][2]string{}
+	for _, pair := range pairs {
+		contents = append(contents, [2]string{pair[0].Content, pair[1].Content})
+	}
+	assert.EqualValues(t, [][2]string{{"-a", "+c"}, {"-d", "+e"}}, contents)
+}
@@ -0,0 +1,39 @@
+package patch
+
+import "github.com/jesseduffield/lazygit/pkg/utils"
+
+// OldLineNumber returns the line number on the old side of the diff which
+// corresponds to the given line number on the new side. A line that the diff
+// adds or changes has no such line, so we go with the closest line it
+// replaced, or else the line it was added after.
+func OldLineNumber(diff string, newLineNumber int) int {
+	offset := 0
+	for _, hunk := range GetHunksFromDiff(diff) {
+		oldStart, oldLength := hunk.fromRange(false)
+		newStart, newLength := hunk.fromRange(true)
+
+		// when a side of the hunk is empty, its start is the line before it
+		oldEnd, newEnd := oldStart+oldLength, newStart+newLength
+		if oldLength == 0 {
+			oldEnd++
+		}
+		if newLength == 0 {
+			newEnd++
+		}
+
+		if newLineNumber < newStart || (newLength == 0 && newLineNumber == newStart) {
+			break
+		}
+
+		if newLineNumber < newEnd {
+			if oldLength == 0 {
+				return oldStart
+			}
+			return oldStart + utils.Min(newLineNumber-newStart, oldLength-1)
+		}
+
+		offset = oldEnd - newEnd
+	}
+
+	return newLineNumber + offset
+}
@@ -0,0 +1,49 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// line 2 changed to two lines, lines 5 and 6 deleted, and two lines added
+// after line 8
+const unifiedZeroDiff = `diff --git a/filename b/filename
+index dcd3485..1ba5540 100644
+--- a/filename
++++ b/filename
+@@ -2 +2,2 @@
+-b
++b1
++b2
+@@ -5,2 +5,0 @@
+-e
+-f
+@@ -8,0 +8,2 @@
++h1
++h2
+`
+
+func TestOldLineNumber(t *testing.T) {
+	scenarios := []struct {
+		newLineNumber int
+		expected      int
+	}{
+		{newLineNumber: 1, expected: 1},
+		{newLineNumber: 2, expected: 2},
+		{newLineNumber: 3, expected: 2},
+		{newLineNumber: 4, expected: 3},
+		{newLineNumber: 5, expected: 4},
+		{newLineNumber: 6, expected: 7},
+		{newLineNumber: 7, expected: 8},
+		{newLineNumber: 8, expected: 8},
+		{newLineNumber: 9, expected: 8},
+		{newLineNumber: 10, expected: 9},
+	}
+
+	for _, s := range scenarios {
+		assert.Equal(t, s.expected, OldLineNumber(unifiedZeroDiff, s.newLineNumber), s.newLineNumber)
+	}
+
+	assert.Equal(t, 4, OldLineNumber("", 4))
+}
@@ -0,0 +1,204 @@
+package patch
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+)
+
+// A custom patch can be saved to disk and shared, and a patch file (ours or
+// anybody else's) can be loaded back into the patch manager so that you can
+// pick the parts of it you want before applying it.
+
+type patchFileDiff struct {
+	name         string
+	changeStatus string
+	diff         string
+}
+
+// matches the signature that git format-patch puts at the end of a patch
+var patchSignatureRegex = regexp.MustCompile(`\n-- \n[^\n]*\n*$`)
+
+// splitPatch splits a patch into the diffs of the individual files it changes.
+// Anything before the first diff (e.g. the email headers of a patch made with
+// git format-patch) is dropped.
+func splitPatch(patch string) []*patchFileDiff {
+	patch = patchSignatureRegex.ReplaceAllString(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+	lines := strings.Split(strings.TrimRight(patch, "\n"), "\n")
+
+	// a patch made with git has a 'diff' line heading each file, but a patch
+	// made with plain old diff -u may only have the '---'/'+++' lines
+	hasDiffLines := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "diff ") {
+			hasDiffLines = true
+			break
+		}
+	}
+
+	isFileStart := func(i int) bool {
+		if hasDiffLines {
+			return strings.HasPrefix(lines[i], "diff ")
+		}
+		return strings.HasPrefix(lines[i], "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")
+	}
+
+	fileDiffs := []*patchFileDiff{}
+	var current []string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		fileDiffs = append(fileDiffs, newPatchFileDiff(current))
+		current = nil
+	}
+
+	for i, line := range lines {
+		if isFileStart(i) {
+			flush()
+			current = []string{}
+		}
+		if current != nil {
+			current = append(current, line)
+		}
+	}
+	flush()
+
+	return fileDiffs
+}
+
+func newPatchFileDiff(lines []string) *patchFileDiff {
+	oldName, newName := "", ""
+	changeStatus := "M"
+	for _, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(line, "new file mode"):
+			changeStatus = "A"
+		case strings.HasPrefix(line, "deleted file mode"):
+			changeStatus = "D"
+		case strings.HasPrefix(line, "--- "):
+			oldName = pathFromHeader(strings.TrimPrefix(line, "--- "))
+			if oldName == "" {
+				changeStatus = "A"
+			}
+		case strings.HasPrefix(line, "+++ "):
+			newName = pathFromHeader(strings.TrimPrefix(line, "+++ "))
+			if newName == "" {
+				changeStatus = "D"
+			}
+		case strings.HasPrefix(line, "diff --git ") && newName == "":
+			// binary diffs have no ---/+++ lines so this is all we've got
+			if idx := strings.LastIndex(line, " b/"); idx != -1 {
+				newName = line[idx+len(" b/"):]
+			}
+		}
+	}
+
+	name := newName
+	if name == "" {
+		name = oldName
+	}
+
+	return &patchFileDiff{
+		name:         name,
+		changeStatus: changeStatus,
+		diff:         strings.Join(lines, "\n") + "\n",
+	}
+}
+
+// pathFromHeader returns the path from a '---' or '+++' line, or an empty
+// string if it's /dev/null
+func pathFromHeader(header string) string {
+	// diff -u puts a timestamp after the path
+	if idx := strings.Index(header, "\t"); idx != -1 {
+		header = header[:idx]
+	}
+
+	if header == "/dev/null" {
+		return ""
+	}
+
+	for _, prefix := range []string{"a/", "b/"} {
+		if strings.HasPrefix(header, prefix) {
+			return strings.TrimPrefix(header, prefix)
+		}
+	}
+
+	return header
+}
+
+// LoadPatch starts a new patch from the contents of a patch file, with every
+// file included in full. It returns false if the patch has no diffs in it.
+func (p *PatchManager) LoadPatch(name string, patch string) bool {
+	fileDiffs := splitPatch(patch)
+	if len(fileDiffs) == 0 {
+		return false
+	}
+
+	p.Start("", name, false, false)
+	p.loadedFiles = []*models.CommitFile{}
+	for _, fileDiff := range fileDiffs {
+		info := &fileInfo{diff: fileDiff.diff}
+		p.addFileWhole(info)
+		p.fileInfoMap[fileDiff.name] = info
+		p.loadedFiles = append(p.loadedFiles, &models.CommitFile{
+			Name:         fileDiff.name,
+			ChangeStatus: fileDiff.changeStatus,
+		})
+	}
+
+	return true
+}
+
+// LoadedFromFile tells us whether the patch was loaded from a patch file rather
+// than built from a commit
+func (p *PatchManager) LoadedFromFile() bool {
+	return p.loadedFiles != nil
+}
+
+// LoadedFiles returns the files of a patch loaded from a patch file
+func (p *PatchManager) LoadedFiles() []*models.CommitFile {
+	return p.loadedFiles
+}
+
+// LoadedFileDiff returns the original diff of a file in a patch loaded from a
+// patch file
+func (p *PatchManager) LoadedFileDiff(filename string) string {
+	info, ok := p.fileInfoMap[filename]
+	if !ok {
+		return ""
+	}
+	return info.diff
+}
+
+// RenderPatchFile renders the patch in a form that git apply can read, for
+// saving to a patch file
+func (p *PatchManager) RenderPatchFile() string {
+	result := ""
+	for _, patch := range p.renderEachFilePatch(true) {
+		if !strings.HasSuffix(patch, "\n") {
+			patch += "\n"
+		}
+		result += patch
+	}
+	return result
+}
+
+// ApplyPatchesWithFallback applies the patch with the given flags, falling
+// back to a three-way merge if it doesn't apply cleanly. We apply the whole
+// patch in one go, because git apply either applies all of it or none of it,
+// so a file which fails to apply never leaves the others applied without it.
+func (p *PatchManager) ApplyPatchesWithFallback(flags ...string) error {
+	patch := p.RenderPatchFile()
+	if err := p.applyPatch(patch, flags...); err != nil {
+		threeWayFlags := append(append([]string{}, flags...), "3way")
+		return p.applyPatch(patch, threeWayFlags...)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const formatPatch = `From 1234567 Mon Sep 17 00:00:00 2001
+From: Jesse <jesse@example.com>
+Subject: [PATCH] change some things
+
+---
+ a.txt | 2 +-
+ b.txt | 1 +
+ c.txt | 1 -
+ 3 files changed
+
+diff --git a/a.txt b/a.txt
+index dcd3485..1ba5540 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ apple
+-grape
++grapes
+diff --git a/b.txt b/b.txt
+new file mode 100644
+index 0000000..9daeafb
+--- /dev/null
++++ b/b.txt
+@@ -0,0 +1 @@
++new
+diff --git a/c.txt b/c.txt
+deleted file mode 100644
+index 9daeafb..0000000
+--- a/c.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-old
+-- 
+2.34.1
+
+`
+
+func TestSplitPatch(t *testing.T) {
+	type scenario struct {
+		name     string
+		patch    string
+		expected []*patchFileDiff
+	}
+
+	scenarios := []scenario{
+		{
+			name:     "no diffs",
+			patch:    "just some text\n",
+			expected: []*patchFileDiff{},
+		},
+		{
+			name:  "git format-patch output",
+			patch: formatPatch,
+			expected: []*patchFileDiff{
+				{
+					name:         "a.txt",
+					changeStatus: "M",
+					diff:         "diff --git a/a.txt b/a.txt\nindex dcd3485..1ba5540 100644\n--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n apple\n-grape\n+grapes\n",
+				},
+				{
+					name:         "b.txt",
+					changeStatus: "A",
+					diff:         "diff --git a/b.txt b/b.txt\nnew file mode 100644\nindex 0000000..9daeafb\n--- /dev/null\n+++ b/b.txt\n@@ -0,0 +1 @@\n+new\n",
+				},
+				{
+					name:         "c.txt",
+					changeStatus: "D",
+					diff:         "diff --git a/c.txt b/c.txt\ndeleted file mode 100644\nindex 9daeafb..0000000\n--- a/c.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-old\n",
+				},
+			},
+		},
+		{
+			name:  "plain diff -u output",
+			patch: "--- dir/a.txt\t2021-01-01 00:00:00\n+++ dir/a.txt\t2021-01-02 00:00:00\n@@ -1 +1 @@\n-a\n+b\n",
+			expected: []*patchFileDiff{
+				{
+					name:         "dir/a.txt",
+					changeStatus: "M",
+					diff:         "--- dir/a.txt\t2021-01-01 00:00:00\n+++ dir/a.txt\t2021-01-02 00:00:00\n@@ -1 +1 @@\n-a\n+b\n",
+				},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, splitPatch(s.patch))
+		})
+	}
+}
+
+func TestLoadPatch(t *testing.T) {
+	applied := []string{}
+	patchManager := NewPatchManager(
+		utils.NewDummyLog(),
+		func(patch string, flags ...string) error {
+			applied = append(applied, patch)
+			return nil
+		},
+		func(from string, to string, reverse bool, filename string, plain bool) (string, error) {
+			t.Fatal("a loaded patch shouldn't need to ask git for diffs")
+			return "", nil
+		},
+	)
+
+	assert.False(t, patchManager.LoadPatch("empty.patch", "nothing here"))
+	assert.False(t, patchManager.Active())
+
+	assert.True(t, patchManager.LoadPatch("my.patch", formatPatch))
+	assert.True(t, patchManager.LoadedFromFile())
+	assert.Equal(t, "my.patch", patchManager.To)
+	assert.EqualValues(t, []*models.CommitFile{
+		{Name: "a.txt", ChangeStatus: "M"},
+		{Name: "b.txt", ChangeStatus: "A"},
+		{Name: "c.txt", ChangeStatus: "D"},
+	}, patchManager.LoadedFiles())
+	assert.Equal(t, WHOLE, patchManager.GetFileStatus("b.txt", "my.patch"))
+
+	// leaving a file out means it's left out of the saved patch too
+	assert.NoError(t, patchManager.RemoveFile("c.txt"))
+	assert.Equal(t,
+		patchManager.LoadedFileDiff("a.txt")+patchManager.LoadedFileDiff("b.txt"),
+		patchManager.RenderPatchFile(),
+	)
+
+	assert.NoError(t, patchManager.ApplyPatchesWithFallback("cached"))
+	assert.EqualValues(t, []string{patchManager.RenderPatchFile()}, applied)
+
+	patchManager.Reset()
+	assert.False(t, patchManager.LoadedFromFile())
+}
+
+func TestApplyPatchesWithFallback(t *testing.T) {
+	type appliedPatch struct {
+		patch string
+		flags []string
+	}
+
+	applied := []appliedPatch{}
+	patchManager := NewPatchManager(
+		utils.NewDummyLog(),
+		func(patch string, flags ...string) error {
+			applied = append(applied, appliedPatch{patch: patch, flags: flags})
+			for _, flag := range flags {
+				if flag == "3way" {
+					return nil
+				}
+			}
+			return errors.New("patch does not apply")
+		},
+		func(from string, to string, reverse bool, filename string, plain bool) (string, error) {
+			return "", nil
+		},
+	)
+	assert.True(t, patchManager.LoadPatch("my.patch", formatPatch))
+
+	assert.NoError(t, patchManager.ApplyPatchesWithFallback("cached"))
+	// both attempts apply every file at once, so that a file which doesn't
+	// apply can't leave the others applied without it
+	patch := patchManager.RenderPatchFile()
+	assert.EqualValues(t, []appliedPatch{
+		{patch: patch, flags: []string{"cached"}},
+		{patch: patch, flags: []string{"cached", "3way"}},
+	}, applied)
+}
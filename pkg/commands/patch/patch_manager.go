@@ -4,6 +4,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -47,6 +48,9 @@ type PatchManager struct {
 
 	// loadFileDiff loads the diff of a file, for a given to (typically a commit SHA)
 	loadFileDiff loadFileDiffFunc
+
+	// loadedFiles is only set when the patch was loaded from a patch file
+	loadedFiles []*models.CommitFile
 }
 
 // NewPatchManager returns a new PatchManager
@@ -65,6 +69,7 @@ func (p *PatchManager) Start(from, to string, reverse bool, canRebase bool) {
 	p.reverse = reverse
 	p.CanRebase = canRebase
 	p.fileInfoMap = map[string]*fileInfo{}
+	p.loadedFiles = nil
 }
 
 func (p *PatchManager) addFileWhole(info *fileInfo) {
@@ -182,7 +187,7 @@ func (p *PatchManager) RenderPatchForFile(filename string, plain bool, reverse b
 	if plain {
 		return patch
 	}
-	parser := NewPatchParser(p.Log, patch)
+	parser := NewPatchParser(p.Log, patch, PatchParserOpts{})
 
 	// not passing included lines because we don't want to see them in the secondary panel
 	return parser.Render(-1, -1, nil)
@@ -283,6 +288,7 @@ func (p *PatchManager) ApplyPatches(reverse bool) error {
 func (p *PatchManager) Reset() {
 	p.To = ""
 	p.fileInfoMap = map[string]*fileInfo{}
+	p.loadedFiles = nil
 }
 
 func (p *PatchManager) Active() bool {
@@ -82,12 +82,37 @@ outer:
 		}
 	}
 
-	// step 2 is collecting all the hunks with new headers
+	// step 2 is merging hunks which overlap. Hunks we've split share the
+	// context lines at the split, and git won't apply overlapping hunks, so like
+	// `git add -p` we put them back together, leaving out the shared lines
+	type mergedHunk struct {
+		hunk      *PatchHunk
+		bodyLines []string
+		end       int
+	}
+	mergedHunks := []*mergedHunk{}
+	for _, hunk := range hunksInRange {
+		bodyLines := hunk.updatedLines(lineIndices, reverse)
+		start, length := hunk.fromRange(reverse)
+
+		if len(mergedHunks) > 0 {
+			previous := mergedHunks[len(mergedHunks)-1]
+			if overlap := previous.end - start; overlap > 0 && overlap <= len(bodyLines) {
+				previous.bodyLines = append(previous.bodyLines, bodyLines[overlap:]...)
+				previous.end = start + length
+				continue
+			}
+		}
+
+		mergedHunks = append(mergedHunks, &mergedHunk{hunk: hunk, bodyLines: bodyLines, end: start + length})
+	}
+
+	// step 3 is collecting all the hunks with new headers
 	startOffset := 0
 	formattedHunks := ""
 	var formattedHunk string
-	for _, hunk := range hunksInRange {
-		startOffset, formattedHunk = hunk.formatWithChanges(lineIndices, reverse, startOffset)
+	for _, merged := range mergedHunks {
+		startOffset, formattedHunk = merged.hunk.formatLines(merged.bodyLines, reverse, startOffset)
 		formattedHunks += formattedHunk
 	}
 
@@ -2,9 +2,12 @@ package patch
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/links"
+	"github.com/jesseduffield/lazygit/pkg/syntax"
 	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -28,6 +31,26 @@ const (
 type PatchLine struct {
 	Kind    PatchLineKind
 	Content string // something like '+ hello' (note the first character is not removed)
+
+	// the parts of the content which differ from the line this one replaced (or
+	// was replaced by)
+	changedRanges []changedRange
+
+	syntaxTokens []syntax.Token
+
+	// the links in a commit's message
+	links []links.Link
+}
+
+type PatchParserOpts struct {
+	// one of 'word', 'char' or 'none'. See git.diffHighlight in the user config
+	IntraLineHighlight string
+
+	SyntaxHighlighting bool
+	// optional
+	SyntaxHighlightCache *SyntaxHighlightCache
+	// optional, for highlighting the links in a commit's message
+	Linkifier *links.Linkifier
 }
 
 type PatchParser struct {
@@ -36,12 +59,23 @@ type PatchParser struct {
 	PatchHunks     []*PatchHunk
 	HunkStarts     []int
 	StageableLines []int // rename to mention we're talking about indexes
+	opts           PatchParserOpts
 }
 
 // NewPatchParser builds a new branch list builder
-func NewPatchParser(log *logrus.Entry, patch string) *PatchParser {
+func NewPatchParser(log *logrus.Entry, patch string, opts PatchParserOpts) *PatchParser {
 	hunkStarts, stageableLines, patchLines := parsePatch(patch)
 
+	setIntraLineChanges(patchLines, opts.IntraLineHighlight)
+	if opts.SyntaxHighlighting {
+		setSyntaxTokens(patchLines, opts.SyntaxHighlightCache)
+	}
+	for _, patchLine := range patchLines {
+		if patchLine.Kind == COMMIT_DESCRIPTION {
+			patchLine.links = opts.Linkifier.Find(patchLine.Content)
+		}
+	}
+
 	patchHunks := GetHunksFromDiff(patch)
 
 	return &PatchParser{
@@ -50,6 +84,7 @@ func NewPatchParser(log *logrus.Entry, patch string) *PatchParser {
 		StageableLines: stageableLines,
 		PatchLines:     patchLines,
 		PatchHunks:     patchHunks,
+		opts:           opts,
 	}
 }
 
@@ -112,6 +147,17 @@ func (l *PatchLine) render(selected bool, included bool) string {
 		textStyle = theme.DefaultTextColor
 	}
 
+	if len(l.links) > 0 {
+		if selected {
+			textStyle = textStyle.MergeStyle(theme.SelectedRangeBgColor)
+		}
+		return links.Render(content, l.links, textStyle)
+	}
+
+	if len(l.changedRanges) > 0 || len(l.syntaxTokens) > 0 {
+		return coloredStringWithHighlights(textStyle, content, l.syntaxTokens, l.changedRanges, selected, included)
+	}
+
 	return coloredString(textStyle, content, selected, included)
 }
 
@@ -132,6 +178,127 @@ func coloredString(textStyle style.TextStyle, str string, selected bool, include
 	return firstCharStyle.Sprint(str[:1]) + textStyle.Sprint(str[1:])
 }
 
+// coloredStringWithHighlights is like coloredString but it also colours the
+// given syntax tokens and highlights the given changed ranges, none of which
+// include the first character
+func coloredStringWithHighlights(textStyle style.TextStyle, str string, tokens []syntax.Token, ranges []changedRange, selected bool, included bool) string {
+	firstCharStyle := textStyle
+	if selected {
+		firstCharStyle = firstCharStyle.MergeStyle(theme.SelectedRangeBgColor)
+	}
+	if included {
+		firstCharStyle = firstCharStyle.MergeStyle(style.BgGreen)
+	}
+
+	return firstCharStyle.Sprint(str[:1]) + highlightedString(textStyle, str, 1, tokens, ranges, selected)
+}
+
+// highlightedString renders str from the given offset onwards. Syntax tokens
+// take their colour from the syntax theme, changed ranges are highlighted on
+// top of that, and the selection colour goes on top of everything.
+func highlightedString(textStyle style.TextStyle, str string, offset int, tokens []syntax.Token, ranges []changedRange, selected bool) string {
+	clamp := func(index int) int {
+		return utils.Min(utils.Max(index, offset), len(str))
+	}
+
+	boundaries := []int{offset, len(str)}
+	for _, token := range tokens {
+		boundaries = append(boundaries, clamp(token.Start), clamp(token.End))
+	}
+	for _, r := range ranges {
+		boundaries = append(boundaries, clamp(r.start), clamp(r.end))
+	}
+	sort.Ints(boundaries)
+
+	result := ""
+	for i := 0; i < len(boundaries)-1; i++ {
+		start, end := boundaries[i], boundaries[i+1]
+		if start >= end {
+			continue
+		}
+
+		segmentStyle := textStyle
+		for _, token := range tokens {
+			if token.Start <= start && end <= token.End {
+				segmentStyle = token.Kind.Style()
+			}
+		}
+		for _, r := range ranges {
+			if r.start <= start && end <= r.end {
+				segmentStyle = textStyle.SetReverse()
+			}
+		}
+		if selected {
+			segmentStyle = segmentStyle.MergeStyle(theme.SelectedRangeBgColor)
+		}
+
+		result += segmentStyle.Sprint(str[start:end])
+	}
+
+	return result
+}
+
+// setIntraLineChanges pairs up each deletion with the corresponding addition
+// in the block of additions that follows it, so that we can highlight what
+// changed between the two
+func setIntraLineChanges(patchLines []*PatchLine, mode string) {
+	if mode == INTRA_LINE_HIGHLIGHT_NONE || mode == "" {
+		return
+	}
+
+	for _, pair := range changedLinePairs(patchLines) {
+		deletion, addition := pair[0], pair[1]
+		if len(deletion.Content) < 1 || len(addition.Content) < 1 {
+			continue
+		}
+
+		deletionRanges, additionRanges := intraLineChanges(deletion.Content[1:], addition.Content[1:], mode)
+		deletion.changedRanges = offsetRanges(deletionRanges, 1)
+		addition.changedRanges = offsetRanges(additionRanges, 1)
+	}
+}
+
+// changedLinePairs returns the i-th deletion of each block of changes paired
+// with the i-th addition of the same block
+func changedLinePairs(patchLines []*PatchLine) [][2]*PatchLine {
+	pairs := [][2]*PatchLine{}
+	deletions := []*PatchLine{}
+	additions := []*PatchLine{}
+
+	flush := func() {
+		for i := 0; i < utils.Min(len(deletions), len(additions)); i++ {
+			pairs = append(pairs, [2]*PatchLine{deletions[i], additions[i]})
+		}
+		deletions = []*PatchLine{}
+		additions = []*PatchLine{}
+	}
+
+	for _, patchLine := range patchLines {
+		switch patchLine.Kind {
+		case DELETION:
+			if len(additions) > 0 {
+				flush()
+			}
+			deletions = append(deletions, patchLine)
+		case ADDITION:
+			additions = append(additions, patchLine)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return pairs
+}
+
+func offsetRanges(ranges []changedRange, offset int) []changedRange {
+	result := make([]changedRange, len(ranges))
+	for i, r := range ranges {
+		result[i] = changedRange{start: r.start + offset, end: r.end + offset}
+	}
+	return result
+}
+
 func parsePatch(patch string) ([]int, []int, []*PatchLine) {
 	lines := strings.Split(patch, "\n")
 	hunkStarts := []int{}
@@ -156,6 +323,10 @@ func parsePatch(patch string) ([]int, []int, []*PatchLine) {
 			} else {
 				lineKind = COMMIT_DESCRIPTION
 			}
+		} else if strings.HasPrefix(line, "diff") {
+			// a diff can contain several files, each with their own header
+			pastFirstHunkHeader = false
+			lineKind = PATCH_HEADER
 		} else if firstChar == "@" {
 			pastFirstHunkHeader = true
 			hunkStarts = append(hunkStarts, index)
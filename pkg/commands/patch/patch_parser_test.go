@@ -0,0 +1,43 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/jesseduffield/lazygit/pkg/links"
+	"github.com/stretchr/testify/assert"
+)
+
+const commitWithLinksDiff = `commit 0123456789abcdef
+Author: Jesse <jesse@example.com>
+
+    Fix PROJ-123
+
+    See https://example.com/PROJ-124
+diff --git a/notes.txt b/notes.txt
+--- a/notes.txt
++++ b/notes.txt
+@@ -1 +1 @@
+-PROJ-125
++PROJ-126
+`
+
+func TestCommitDescriptionLinks(t *testing.T) {
+	linkifier, err := links.NewLinkifier([]config.IssueTrackerConfig{{Pattern: `PROJ-\d+`, URL: "https://jira.work.com/browse/{{.Match}}"}}, "")
+	assert.NoError(t, err)
+	parser := NewPatchParser(nil, commitWithLinksDiff, PatchParserOpts{Linkifier: linkifier})
+
+	linkTexts := func(line *PatchLine) []string {
+		result := []string{}
+		for _, link := range line.links {
+			result = append(result, link.Text)
+		}
+		return result
+	}
+
+	assert.EqualValues(t, []string{"PROJ-123"}, linkTexts(parser.PatchLines[3]))
+	assert.EqualValues(t, []string{"https://example.com/PROJ-124"}, linkTexts(parser.PatchLines[5]))
+	// we only look for links in the commit's message, not in the diff
+	assert.EqualValues(t, []string{}, linkTexts(parser.PatchLines[10]))
+	assert.EqualValues(t, []string{}, linkTexts(parser.PatchLines[11]))
+}
@@ -0,0 +1,206 @@
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/syntax"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/mattn/go-runewidth"
+)
+
+// the job of this file is to lay out a diff with the old version of each hunk
+// on the left and the new version on the right, so that each changed line sits
+// next to the line it replaced.
+
+const (
+	SIDE_BY_SIDE_SEPARATOR = "│"
+	SIDE_BY_SIDE_TAB_WIDTH = 4
+	// any narrower than this and there's no point showing the columns at all
+	MIN_SIDE_BY_SIDE_CONTENT_WIDTH = 8
+)
+
+type sideBySideCell struct {
+	kind       PatchLineKind
+	lineNumber int
+	content    string // without the leading '+', '-' or ' '
+
+	// the parts of the content that differ from the other side of the row
+	changedRanges []changedRange
+
+	syntaxTokens []syntax.Token
+}
+
+type sideBySideRow struct {
+	// lines outside of hunk bodies (e.g. file headers) span both columns
+	fullWidthLine *PatchLine
+
+	left  *sideBySideCell
+	right *sideBySideCell
+}
+
+// RenderSideBySide returns the coloured string of the diff with old and new
+// lines in separate columns, given the number of columns we have to work with
+func (p *PatchParser) RenderSideBySide(width int) string {
+	rows, maxLineNumber := p.sideBySideRows()
+
+	lineNumberWidth := len(strconv.Itoa(maxLineNumber))
+	columnWidth := (width - runewidth.StringWidth(SIDE_BY_SIDE_SEPARATOR)) / 2
+	if columnWidth-lineNumberWidth-1 < MIN_SIDE_BY_SIDE_CONTENT_WIDTH {
+		return p.Render(-1, -1, nil)
+	}
+
+	renderedRows := make([]string, len(rows))
+	for i, row := range rows {
+		renderedRows[i] = row.render(columnWidth, lineNumberWidth)
+	}
+
+	result := strings.Join(renderedRows, "\n")
+	if strings.TrimSpace(utils.Decolorise(result)) == "" {
+		return ""
+	}
+	return result
+}
+
+func (p *PatchParser) sideBySideRows() ([]*sideBySideRow, int) {
+	rows := []*sideBySideRow{}
+	maxLineNumber := 0
+
+	// consecutive deletions and additions are held back until we reach the end
+	// of the change, so that we can pair them up
+	deletions := []*sideBySideCell{}
+	additions := []*sideBySideCell{}
+	flushChanges := func() {
+		for i := 0; i < utils.Max(len(deletions), len(additions)); i++ {
+			row := &sideBySideRow{}
+			if i < len(deletions) {
+				row.left = deletions[i]
+			}
+			if i < len(additions) {
+				row.right = additions[i]
+			}
+			if row.left != nil && row.right != nil {
+				row.left.changedRanges, row.right.changedRanges = intraLineChanges(
+					row.left.content, row.right.content, p.opts.IntraLineHighlight,
+				)
+			}
+			rows = append(rows, row)
+		}
+		deletions = []*sideBySideCell{}
+		additions = []*sideBySideCell{}
+	}
+
+	inHunk := false
+	oldLineNumber := 0
+	newLineNumber := 0
+	newCell := func(line *PatchLine, lineNumber int) *sideBySideCell {
+		maxLineNumber = utils.Max(maxLineNumber, lineNumber)
+		content, syntaxTokens := expandTabs(line.Content[1:], offsetTokens(line.syntaxTokens, -1))
+		return &sideBySideCell{kind: line.Kind, lineNumber: lineNumber, content: content, syntaxTokens: syntaxTokens}
+	}
+
+	for _, line := range p.PatchLines {
+		if line.Kind == HUNK_HEADER {
+			// combined diffs (as shown for merge commits) have more than two sides
+			// so we leave those hunks as they are
+			match := hunkHeaderRegexp.FindStringSubmatch(line.Content)
+			inHunk = match != nil
+			if inHunk {
+				oldLineNumber = utils.MustConvertToInt(match[1])
+				newLineNumber = utils.MustConvertToInt(match[2])
+			}
+		} else if inHunk && line.Content != "" {
+			switch line.Kind {
+			case DELETION:
+				deletions = append(deletions, newCell(line, oldLineNumber))
+				oldLineNumber++
+				continue
+			case ADDITION:
+				additions = append(additions, newCell(line, newLineNumber))
+				newLineNumber++
+				continue
+			case CONTEXT:
+				flushChanges()
+				rows = append(rows, &sideBySideRow{
+					left:  newCell(line, oldLineNumber),
+					right: newCell(line, newLineNumber),
+				})
+				oldLineNumber++
+				newLineNumber++
+				continue
+			}
+		}
+
+		flushChanges()
+		rows = append(rows, &sideBySideRow{fullWidthLine: line})
+	}
+	flushChanges()
+
+	return rows, maxLineNumber
+}
+
+func (row *sideBySideRow) render(columnWidth int, lineNumberWidth int) string {
+	if row.fullWidthLine != nil {
+		return row.fullWidthLine.render(false, false)
+	}
+
+	return row.left.render(columnWidth, lineNumberWidth) +
+		theme.DefaultTextColor.Sprint(SIDE_BY_SIDE_SEPARATOR) +
+		row.right.render(columnWidth, lineNumberWidth)
+}
+
+func (cell *sideBySideCell) render(width int, lineNumberWidth int) string {
+	// a change with fewer lines on one side leaves a gap on that side
+	if cell == nil {
+		return strings.Repeat(" ", width)
+	}
+
+	var textStyle style.TextStyle
+	switch cell.kind {
+	case ADDITION:
+		textStyle = style.FgGreen
+	case DELETION:
+		textStyle = style.FgRed
+	default:
+		textStyle = theme.DefaultTextColor
+	}
+
+	lineNumber := fmt.Sprintf("%*d ", lineNumberWidth, cell.lineNumber)
+	contentWidth := width - len(lineNumber)
+	content := runewidth.Truncate(cell.content, contentWidth, "")
+	padding := strings.Repeat(" ", contentWidth-runewidth.StringWidth(content))
+
+	return textStyle.Sprint(lineNumber) + highlightedString(textStyle, content, 0, cell.syntaxTokens, cell.changedRanges, false) + padding
+}
+
+// expandTabs replaces tabs with spaces so that our columns line up, moving the
+// syntax tokens along to match
+func expandTabs(content string, tokens []syntax.Token) (string, []syntax.Token) {
+	if !strings.Contains(content, "\t") {
+		return content, tokens
+	}
+
+	tab := strings.Repeat(" ", SIDE_BY_SIDE_TAB_WIDTH)
+	expanded := strings.Builder{}
+	// where each byte of the content ends up in the expanded content
+	newIndices := make([]int, len(content)+1)
+	for i := 0; i < len(content); i++ {
+		newIndices[i] = expanded.Len()
+		if content[i] == '\t' {
+			expanded.WriteString(tab)
+		} else {
+			expanded.WriteByte(content[i])
+		}
+	}
+	newIndices[len(content)] = expanded.Len()
+
+	newTokens := make([]syntax.Token, len(tokens))
+	for i, token := range tokens {
+		newTokens[i] = syntax.Token{Kind: token.Kind, Start: newIndices[token.Start], End: newIndices[token.End]}
+	}
+
+	return expanded.String(), newTokens
+}
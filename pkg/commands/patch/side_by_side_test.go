@@ -0,0 +1,92 @@
+package patch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const sideBySideDiff = `diff --git a/filename b/filename
+index dcd3485..1ba5540 100644
+--- a/filename
++++ b/filename
+@@ -8,4 +8,4 @@ heading
+ apple
+-grape
+-orange
++grapes
+ kiwi
++mango
+diff --git a/other b/other
+index e69de29..9daeafb 100644
+--- a/other
++++ b/other
+@@ -0,0 +1 @@
++test
+`
+
+func TestRenderSideBySide(t *testing.T) {
+	scenarios := []struct {
+		testName string
+		diff     string
+		width    int
+		expected []string
+	}{
+		{
+			testName: "pairs up deletions with additions",
+			diff:     sideBySideDiff,
+			width:    41,
+			expected: []string{
+				"diff --git a/filename b/filename",
+				"index dcd3485..1ba5540 100644",
+				"--- a/filename",
+				"+++ b/filename",
+				"@@ -8,4 +8,4 @@ heading",
+				" 8 apple            │ 8 apple            ",
+				" 9 grape            │ 9 grapes           ",
+				"10 orange           │                    ",
+				"11 kiwi             │10 kiwi             ",
+				"                    │11 mango            ",
+				"diff --git a/other b/other",
+				"index e69de29..9daeafb 100644",
+				"--- a/other",
+				"+++ b/other",
+				"@@ -0,0 +1 @@",
+				"                    │ 1 test             ",
+				" ",
+			},
+		},
+		{
+			testName: "truncates long lines",
+			diff:     "@@ -1 +1 @@\n-a very long line indeed\n+a very long line\n",
+			width:    25,
+			expected: []string{
+				"@@ -1 +1 @@",
+				"1 a very lon│1 a very lon",
+				" ",
+			},
+		},
+		{
+			testName: "falls back to a unified diff when there is no room",
+			diff:     "@@ -1 +1 @@\n-old\n+new\n",
+			width:    10,
+			expected: []string{
+				"@@ -1 +1 @@",
+				"-old",
+				"+new",
+				" ",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			parser := NewPatchParser(nil, s.diff, PatchParserOpts{IntraLineHighlight: INTRA_LINE_HIGHLIGHT_WORD})
+			result := utils.Decolorise(parser.RenderSideBySide(s.width))
+			assert.Equal(t, strings.Join(s.expected, "\n"), result)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package patch
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/jesseduffield/lazygit/pkg/syntax"
+)
+
+// once we've highlighted this many files we start again from scratch, so that
+// the cache doesn't grow forever
+const MAX_SYNTAX_HIGHLIGHT_CACHE_ENTRIES = 50
+
+// SyntaxHighlightCache holds on to the syntax highlighting of each file in the
+// diffs we've parsed, so that when the same diff is parsed again (as happens
+// whenever the staging panel refreshes) we don't need to highlight it again.
+// The gui keeps one cache per view.
+type SyntaxHighlightCache struct {
+	mutex   sync.Mutex
+	entries map[string]*syntaxHighlightCacheEntry
+}
+
+type syntaxHighlightCacheEntry struct {
+	content string
+	tokens  [][]syntax.Token
+}
+
+func NewSyntaxHighlightCache() *SyntaxHighlightCache {
+	return &SyntaxHighlightCache{entries: map[string]*syntaxHighlightCacheEntry{}}
+}
+
+func (self *SyntaxHighlightCache) get(path string, content string) ([][]syntax.Token, bool) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	entry, ok := self.entries[path]
+	if !ok || entry.content != content {
+		return nil, false
+	}
+	return entry.tokens, true
+}
+
+func (self *SyntaxHighlightCache) set(path string, content string, tokens [][]syntax.Token) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if len(self.entries) >= MAX_SYNTAX_HIGHLIGHT_CACHE_ENTRIES {
+		self.entries = map[string]*syntaxHighlightCacheEntry{}
+	}
+	self.entries[path] = &syntaxHighlightCacheEntry{content: content, tokens: tokens}
+}
+
+// setSyntaxTokens highlights the lines of each file in the diff according to
+// the file's language
+func setSyntaxTokens(patchLines []*PatchLine, cache *SyntaxHighlightCache) {
+	for _, fileLines := range splitPatchLinesByFile(patchLines) {
+		path := pathOfFile(fileLines)
+		language := syntax.LanguageForPath(path)
+		if language == nil {
+			continue
+		}
+
+		contents := make([]string, len(fileLines))
+		for i, line := range fileLines {
+			contents[i] = line.Content
+		}
+		content := strings.Join(contents, "\n")
+
+		var tokens [][]syntax.Token
+		ok := false
+		if cache != nil {
+			tokens, ok = cache.get(path, content)
+		}
+		if !ok {
+			tokens = highlightFileLines(fileLines, language)
+			if cache != nil {
+				cache.set(path, content, tokens)
+			}
+		}
+
+		for i, line := range fileLines {
+			line.syntaxTokens = tokens[i]
+		}
+	}
+}
+
+func splitPatchLinesByFile(patchLines []*PatchLine) [][]*PatchLine {
+	result := [][]*PatchLine{}
+	start := 0
+	for i, line := range patchLines {
+		if i > start && line.Kind == PATCH_HEADER && strings.HasPrefix(line.Content, "diff ") {
+			result = append(result, patchLines[start:i])
+			start = i
+		}
+	}
+	return append(result, patchLines[start:])
+}
+
+// pathOfFile finds the path of the file in its header, preferring the new path
+// because the old one is /dev/null for a new file
+func pathOfFile(fileLines []*PatchLine) string {
+	path := ""
+	for _, line := range fileLines {
+		if line.Kind != PATCH_HEADER {
+			continue
+		}
+		if strings.HasPrefix(line.Content, "+++ ") && !strings.HasSuffix(line.Content, "/dev/null") {
+			return strings.TrimSpace(line.Content[4:])
+		}
+		if strings.HasPrefix(line.Content, "--- ") {
+			path = strings.TrimSpace(line.Content[4:])
+		}
+	}
+	return path
+}
+
+// highlightFileLines returns the tokens for each line of a file's diff. The old
+// and new versions of the file are highlighted separately, because e.g. a
+// comment that was opened on a deleted line doesn't carry on into the added
+// lines that follow it.
+func highlightFileLines(fileLines []*PatchLine, language *syntax.Language) [][]syntax.Token {
+	tokens := make([][]syntax.Token, len(fileLines))
+	oldState := syntax.State{}
+	newState := syntax.State{}
+
+	for i, line := range fileLines {
+		if line.Kind == HUNK_HEADER {
+			// we can't know what came before the hunk
+			oldState = syntax.State{}
+			newState = syntax.State{}
+			continue
+		}
+		if line.Content == "" {
+			continue
+		}
+
+		content := line.Content[1:]
+		var lineTokens []syntax.Token
+		switch line.Kind {
+		case ADDITION:
+			lineTokens, newState = language.HighlightLine(content, newState)
+		case DELETION:
+			lineTokens, oldState = language.HighlightLine(content, oldState)
+		case CONTEXT:
+			if oldState == newState {
+				lineTokens, newState = language.HighlightLine(content, newState)
+				oldState = newState
+			} else {
+				lineTokens, newState = language.HighlightLine(content, newState)
+				_, oldState = language.HighlightLine(content, oldState)
+			}
+		default:
+			continue
+		}
+
+		tokens[i] = offsetTokens(lineTokens, 1)
+	}
+
+	return tokens
+}
+
+func offsetTokens(tokens []syntax.Token, offset int) []syntax.Token {
+	result := make([]syntax.Token, len(tokens))
+	for i, token := range tokens {
+		result[i] = syntax.Token{Kind: token.Kind, Start: token.Start + offset, End: token.End + offset}
+	}
+	return result
+}
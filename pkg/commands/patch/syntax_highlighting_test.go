@@ -0,0 +1,56 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/syntax"
+	"github.com/stretchr/testify/assert"
+)
+
+const syntaxHighlightingDiff = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,2 @@
+-func a() {}
++func b() {} // renamed
+diff --git a/notes.txt b/notes.txt
+--- a/notes.txt
++++ b/notes.txt
+@@ -1 +1 @@
+-func
++func
+`
+
+func TestSetSyntaxTokens(t *testing.T) {
+	cache := NewSyntaxHighlightCache()
+	parser := NewPatchParser(nil, syntaxHighlightingDiff, PatchParserOpts{SyntaxHighlighting: true, SyntaxHighlightCache: cache})
+
+	tokenTexts := func(line *PatchLine) []string {
+		result := []string{}
+		for _, token := range line.syntaxTokens {
+			result = append(result, line.Content[token.Start:token.End])
+		}
+		return result
+	}
+
+	assert.EqualValues(t, []string{"func"}, tokenTexts(parser.PatchLines[4]))
+	assert.EqualValues(t, []string{"func", "// renamed"}, tokenTexts(parser.PatchLines[5]))
+	// we don't know how to highlight a .txt file
+	assert.EqualValues(t, []string{}, tokenTexts(parser.PatchLines[11]))
+
+	// the tokens are cached for the next time we parse the same diff
+	cachedTokens, ok := cache.get("b/main.go", joinedContent(parser.PatchLines[0:6]))
+	assert.True(t, ok)
+	assert.Equal(t, syntax.KEYWORD, cachedTokens[5][0].Kind)
+}
+
+func joinedContent(lines []*PatchLine) string {
+	result := ""
+	for i, line := range lines {
+		if i > 0 {
+			result += "\n"
+		}
+		result += line.Content
+	}
+	return result
+}
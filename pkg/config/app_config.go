@@ -43,6 +43,7 @@ type AppConfigurer interface {
 	GetUserConfigPaths() []string
 	GetUserConfigDir() string
 	ReloadUserConfig() error
+	SetUserConfig(userConfig *UserConfig)
 
 	GetAppState() *AppState
 	SaveAppState() error
@@ -221,6 +222,12 @@ func (c *AppConfig) ReloadUserConfig() error {
 	return nil
 }
 
+// SetUserConfig lets us restore a previous user config, e.g. when a freshly
+// reloaded one turns out to be invalid
+func (c *AppConfig) SetUserConfig(userConfig *UserConfig) {
+	c.UserConfig = userConfig
+}
+
 func configFilePath(filename string) (string, error) {
 	folder, err := findOrCreateConfigDir()
 	if err != nil {
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"reflect"
+)
+
+// MergeKeybindingConfigs returns a copy of base with every key that has been
+// set in override applied on top of it. This is how we obtain the keybindings
+// for a context which has overrides in the 'contexts' section of the config.
+func MergeKeybindingConfigs(base KeybindingConfig, override KeybindingConfig) KeybindingConfig {
+	result := base
+	mergeKeybindingValues(reflect.ValueOf(&result).Elem(), reflect.ValueOf(override))
+	// overrides don't nest
+	result.Contexts = nil
+	return result
+}
+
+func mergeKeybindingValues(target reflect.Value, override reflect.Value) {
+	switch override.Kind() {
+	case reflect.Struct:
+		for i := 0; i < override.NumField(); i++ {
+			mergeKeybindingValues(target.Field(i), override.Field(i))
+		}
+	case reflect.String:
+		if override.String() != "" {
+			target.SetString(override.String())
+		}
+	case reflect.Slice:
+		if override.Len() > 0 {
+			target.Set(override)
+		}
+	}
+}
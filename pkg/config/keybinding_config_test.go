@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeKeybindingConfigs(t *testing.T) {
+	base := GetDefaultConfig().Keybinding
+	base.Contexts = map[string]KeybindingConfig{
+		"merging": {
+			Universal: KeybindingUniversalConfig{PrevBlock: "K"},
+			Main:      KeybindingMainConfig{PickBothHunks: "B"},
+		},
+	}
+
+	result := MergeKeybindingConfigs(base, base.Contexts["merging"])
+
+	assert.EqualValues(t, "K", result.Universal.PrevBlock)
+	assert.EqualValues(t, "B", result.Main.PickBothHunks)
+	// untouched keys keep their values
+	assert.EqualValues(t, base.Universal.NextBlock, result.Universal.NextBlock)
+	assert.EqualValues(t, base.Universal.JumpToBlock, result.Universal.JumpToBlock)
+	assert.Nil(t, result.Contexts)
+	// the base must not be mutated
+	assert.EqualValues(t, "<left>", base.Universal.PrevBlock)
+}
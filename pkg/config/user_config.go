@@ -1,5 +1,9 @@
 package config
 
+import (
+	"strings"
+)
+
 type UserConfig struct {
 	Gui                  GuiConfig        `yaml:"gui"`
 	Git                  GitConfig        `yaml:"git"`
@@ -11,16 +15,49 @@ type UserConfig struct {
 	QuitOnTopLevelReturn bool             `yaml:"quitOnTopLevelReturn"`
 	Keybinding           KeybindingConfig `yaml:"keybinding"`
 	// OS determines what defaults are set for opening files and links
-	OS                   OSConfig          `yaml:"os,omitempty"`
-	DisableStartupPopups bool              `yaml:"disableStartupPopups"`
-	CustomCommands       []CustomCommand   `yaml:"customCommands"`
-	Services             map[string]string `yaml:"services"`
-	NotARepository       string            `yaml:"notARepository"`
+	OS                   OSConfig             `yaml:"os,omitempty"`
+	DisableStartupPopups bool                 `yaml:"disableStartupPopups"`
+	CustomCommands       []CustomCommand      `yaml:"customCommands"`
+	Services             map[string]string    `yaml:"services"`
+	HostingService       HostingServiceConfig `yaml:"hostingService"`
+	NotARepository       string               `yaml:"notARepository"`
 }
 
 type RefresherConfig struct {
-	RefreshInterval int `yaml:"refreshInterval"`
-	FetchInterval   int `yaml:"fetchInterval"`
+	RefreshInterval     int `yaml:"refreshInterval"`
+	FetchInterval       int `yaml:"fetchInterval"`
+	PullRequestInterval int `yaml:"pullRequestInterval"`
+}
+
+type HostingServiceConfig struct {
+	// if true, we ask the hosting service's API about the repo's pull requests
+	PullRequests bool `yaml:"pullRequests"`
+	// API tokens keyed by web domain, e.g. github.com. Where there's no token,
+	// we ask git's credential helper for a password.
+	Tokens map[string]string `yaml:"tokens"`
+	// API base URLs keyed by web domain, for when the API isn't where we'd
+	// expect it to be
+	APIBaseURLs map[string]string `yaml:"apiBaseUrls"`
+	// overrides for the paths at which the service shows files, branches etc,
+	// keyed by web domain or by provider, e.g. gitlab
+	URLTemplates map[string]URLTemplatesConfig `yaml:"urlTemplates"`
+}
+
+// URLTemplatesConfig holds the paths, relative to the repo's web URL, of the
+// pages we open in the browser. Empty templates are left to the service.
+type URLTemplatesConfig struct {
+	// a file at a commit, e.g. /blob/{{.Ref}}/{{.Path}}
+	File string `yaml:"file"`
+	// a line of a file, e.g. /blob/{{.Ref}}/{{.Path}}#L{{.Line}}
+	Line string `yaml:"line"`
+	// a range of lines, e.g. /blob/{{.Ref}}/{{.Path}}#L{{.StartLine}}-L{{.EndLine}}
+	LineRange string `yaml:"lineRange"`
+	// e.g. /tree/{{.Branch}}
+	Branch string `yaml:"branch"`
+	// e.g. /releases/tag/{{.Tag}}
+	Tag string `yaml:"tag"`
+	// the changes between two refs, e.g. /compare/{{.From}}...{{.To}}
+	Compare string `yaml:"compare"`
 }
 
 type GuiConfig struct {
@@ -43,6 +80,7 @@ type GuiConfig struct {
 	ShowRandomTip            bool               `yaml:"showRandomTip"`
 	ShowCommandLog           bool               `yaml:"showCommandLog"`
 	CommandLogSize           int                `yaml:"commandLogSize"`
+	SyntaxHighlighting       bool               `yaml:"syntaxHighlighting"`
 }
 
 type ThemeConfig struct {
@@ -75,6 +113,10 @@ type GitConfig struct {
 	ParseEmoji      bool      `yaml:"parseEmoji"`
 	Log             LogConfig `yaml:"log"`
 	DiffContextSize int       `yaml:"diffContextSize"`
+	// one of 'word' | 'char' | 'none'
+	DiffHighlight string `yaml:"diffHighlight"`
+	// references to issues in commit messages that we link to the issue
+	IssueTrackers []IssueTrackerConfig `yaml:"issueTrackers"`
 }
 
 type PagingConfig struct {
@@ -90,6 +132,9 @@ type CommitConfig struct {
 type MergingConfig struct {
 	ManualCommit bool   `yaml:"manualCommit"`
 	Args         string `yaml:"args"`
+	// when auto-resolving trivial conflicts, treat sides that differ only in
+	// whitespace as the same
+	AutoResolveIgnoringWhitespace bool `yaml:"autoResolveIgnoringWhitespace"`
 }
 
 type LogConfig struct {
@@ -102,6 +147,15 @@ type CommitPrefixConfig struct {
 	Replace string `yaml:"replace"`
 }
 
+type IssueTrackerConfig struct {
+	// a regex for references to issues, e.g. 'PROJ-\d+'
+	Pattern string `yaml:"pattern"`
+	// the URL of the referenced issue. {{.Match}} is the reference, the
+	// pattern's named groups go by their names, and {{.RepoURL}} is the repo's
+	// page on the hosting service's website
+	URL string `yaml:"url"`
+}
+
 type UpdateConfig struct {
 	Method string `yaml:"method"`
 	Days   int64  `yaml:"days"`
@@ -117,6 +171,9 @@ type KeybindingConfig struct {
 	CommitFiles KeybindingCommitFilesConfig `yaml:"commitFiles"`
 	Main        KeybindingMainConfig        `yaml:"main"`
 	Submodules  KeybindingSubmodulesConfig  `yaml:"submodules"`
+	// Contexts lets you override the above keybindings for a given context
+	// (e.g. 'merging' or 'tags'), keyed by context
+	Contexts map[string]KeybindingConfig `yaml:"contexts"`
 }
 
 // damn looks like we have some inconsistencies here with -alt and -alt1
@@ -174,6 +231,7 @@ type KeybindingUniversalConfig struct {
 	PrevScreenMode               string   `yaml:"prevScreenMode"`
 	Undo                         string   `yaml:"undo"`
 	Redo                         string   `yaml:"redo"`
+	UndoHistory                  string   `yaml:"undoHistory"`
 	FilteringMenu                string   `yaml:"filteringMenu"`
 	DiffingMenu                  string   `yaml:"diffingMenu"`
 	DiffingMenuAlt               string   `yaml:"diffingMenu-alt"`
@@ -183,8 +241,12 @@ type KeybindingUniversalConfig struct {
 	AppendNewline                string   `yaml:"appendNewline"`
 	ExtrasMenu                   string   `yaml:"extrasMenu"`
 	ToggleWhitespaceInDiffView   string   `yaml:"toggleWhitespaceInDiffView"`
+	ToggleSplitDiffView          string   `yaml:"toggleSplitDiffView"`
 	IncreaseContextInDiffView    string   `yaml:"increaseContextInDiffView"`
 	DecreaseContextInDiffView    string   `yaml:"decreaseContextInDiffView"`
+	OpenInBrowserMenu            string   `yaml:"openInBrowserMenu"`
+	// Leader is what '<leader>' expands to in a key sequence like '<leader> b'
+	Leader string `yaml:"leader"`
 }
 
 type KeybindingStatusConfig struct {
@@ -208,6 +270,7 @@ type KeybindingFilesConfig struct {
 	ToggleTreeView           string `yaml:"toggleTreeView"`
 	OpenMergeTool            string `yaml:"openMergeTool"`
 	OpenStatusFilter         string `yaml:"openStatusFilter"`
+	AutoResolveConflicts     string `yaml:"autoResolveConflicts"`
 }
 
 type KeybindingBranchesConfig struct {
@@ -224,6 +287,7 @@ type KeybindingBranchesConfig struct {
 	PushTag                string `yaml:"pushTag"`
 	SetUpstream            string `yaml:"setUpstream"`
 	FetchRemote            string `yaml:"fetchRemote"`
+	DiffPullRequest        string `yaml:"diffPullRequest"`
 }
 
 type KeybindingCommitsConfig struct {
@@ -249,6 +313,7 @@ type KeybindingCommitsConfig struct {
 	OpenLogMenu                  string `yaml:"openLogMenu"`
 	OpenInBrowser                string `yaml:"openInBrowser"`
 	ViewBisectOptions            string `yaml:"viewBisectOptions"`
+	OpenLinkedIssue              string `yaml:"openLinkedIssue"`
 }
 
 type KeybindingStashConfig struct {
@@ -260,10 +325,14 @@ type KeybindingCommitFilesConfig struct {
 }
 
 type KeybindingMainConfig struct {
-	ToggleDragSelect    string `yaml:"toggleDragSelect"`
-	ToggleDragSelectAlt string `yaml:"toggleDragSelect-alt"`
-	ToggleSelectHunk    string `yaml:"toggleSelectHunk"`
-	PickBothHunks       string `yaml:"pickBothHunks"`
+	ToggleDragSelect     string `yaml:"toggleDragSelect"`
+	ToggleDragSelectAlt  string `yaml:"toggleDragSelect-alt"`
+	ToggleSelectHunk     string `yaml:"toggleSelectHunk"`
+	PickBothHunks        string `yaml:"pickBothHunks"`
+	ToggleThreeWayView   string `yaml:"toggleThreeWayView"`
+	EditHunk             string `yaml:"editHunk"`
+	SplitHunk            string `yaml:"splitHunk"`
+	AutoResolveConflicts string `yaml:"autoResolveConflicts"`
 }
 
 type KeybindingSubmodulesConfig struct {
@@ -288,26 +357,82 @@ type OSConfig struct {
 }
 
 type CustomCommand struct {
-	Key         string                `yaml:"key"`
-	Context     string                `yaml:"context"`
+	// optional if the command is in a command menu, or if you only want to run
+	// it from the keybindings menu
+	Key string `yaml:"key"`
+	// one or more contexts separated by commas, e.g. 'localBranches, remoteBranches',
+	// or given as a list
+	Context     CustomCommandContext  `yaml:"context"`
 	Command     string                `yaml:"command"`
 	Subprocess  bool                  `yaml:"subprocess"`
 	Prompts     []CustomCommandPrompt `yaml:"prompts"`
 	LoadingText string                `yaml:"loadingText"`
 	Description string                `yaml:"description"`
 	Stream      bool                  `yaml:"stream"`
+	// where to show the command's output: one of 'none', 'popup', 'mainPanel'
+	// and 'log'. Defaults to 'log' if stream is set, otherwise 'none'.
+	Output string                 `yaml:"output"`
+	After  CustomCommandAfterHook `yaml:"after"`
+	// a condition which must hold for the command to be available, e.g.
+	// 'glob "*.sql" .SelectedFile.Name'
+	When string `yaml:"when"`
+	// if set, the key opens a menu of these commands rather than running a
+	// command. They take their context from this command.
+	CommandMenu []CustomCommand `yaml:"commandMenu"`
+}
+
+// CustomCommandContext is the context, or comma-separated contexts, of a custom
+// command. In the config it can also be a list of contexts, which we join with
+// commas so that both forms read the same.
+type CustomCommandContext string
+
+func (self *CustomCommandContext) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var contexts []string
+	if err := unmarshal(&contexts); err == nil {
+		*self = CustomCommandContext(strings.Join(contexts, ","))
+		return nil
+	}
+
+	var context string
+	if err := unmarshal(&context); err != nil {
+		return err
+	}
+	*self = CustomCommandContext(context)
+	return nil
+}
+
+// CustomCommandAfterHook is what to do once a custom command has run
+type CustomCommandAfterHook struct {
+	// the scopes to refresh, e.g. 'files' and 'branches'. Defaults to all of them
+	Refresh []string `yaml:"refresh"`
+	// a regexp for finding a ref in the output, which then gets selected. If the
+	// regexp has a group named 'ref' we use that, otherwise the whole match.
+	SelectRef string `yaml:"selectRef"`
+	// whether to copy the output to the clipboard
+	CopyOutput bool `yaml:"copyOutput"`
 }
 
 type CustomCommandPrompt struct {
-	Type  string `yaml:"type"` // one of 'input' and 'menu'
+	// one of 'input', 'confirm', 'menu', 'multiSelect' and 'menuFromCommand'
+	Type  string `yaml:"type"`
 	Title string `yaml:"title"`
+	// if set, the response is available in templates as {{.Form.<key>}}
+	Key string `yaml:"key"`
 
 	// this only apply to prompts
-	InitialValue string `yaml:"initialValue"`
+	InitialValue string                         `yaml:"initialValue"`
+	Suggestions  CustomCommandPromptSuggestions `yaml:"suggestions"`
 
-	// this only applies to menus
+	// this only applies to confirmations
+	Body string `yaml:"body"`
+
+	// this only applies to menus and multi-selects
 	Options []CustomCommandMenuOption
 
+	// this only applies to multi-selects: what goes between the selected values
+	// in the response. Defaults to a space.
+	Separator string `yaml:"separator"`
+
 	// this only applies to menuFromCommand
 	Command     string `yaml:"command"`
 	Filter      string `yaml:"filter"`
@@ -315,6 +440,11 @@ type CustomCommandPrompt struct {
 	LabelFormat string `yaml:"labelFormat"`
 }
 
+type CustomCommandPromptSuggestions struct {
+	// one of 'branches', 'remotes', 'tags', 'files' and 'authors'
+	Preset string `yaml:"preset"`
+}
+
 type CustomCommandMenuOption struct {
 	Name        string `yaml:"name"`
 	Description string `yaml:"description"`
@@ -350,6 +480,7 @@ func GetDefaultConfig() *UserConfig {
 			ShowFileTree:             true,
 			ShowRandomTip:            true,
 			CommandLogSize:           8,
+			SyntaxHighlighting:       false,
 		},
 		Git: GitConfig{
 			Paging: PagingConfig{
@@ -360,8 +491,9 @@ func GetDefaultConfig() *UserConfig {
 				SignOff: false,
 			},
 			Merging: MergingConfig{
-				ManualCommit: false,
-				Args:         "",
+				ManualCommit:                  false,
+				Args:                          "",
+				AutoResolveIgnoringWhitespace: false,
 			},
 			Log: LogConfig{
 				Order:     "topo-order",
@@ -375,10 +507,13 @@ func GetDefaultConfig() *UserConfig {
 			CommitPrefixes:      map[string]CommitPrefixConfig(nil),
 			ParseEmoji:          false,
 			DiffContextSize:     3,
+			DiffHighlight:       "none",
+			IssueTrackers:       []IssueTrackerConfig(nil),
 		},
 		Refresher: RefresherConfig{
-			RefreshInterval: 10,
-			FetchInterval:   60,
+			RefreshInterval:     10,
+			FetchInterval:       60,
+			PullRequestInterval: 60,
 		},
 		Update: UpdateConfig{
 			Method: "prompt",
@@ -444,6 +579,7 @@ func GetDefaultConfig() *UserConfig {
 				PrevScreenMode:               "_",
 				Undo:                         "z",
 				Redo:                         "<c-z>",
+				UndoHistory:                  "Z",
 				FilteringMenu:                "<c-s>",
 				DiffingMenu:                  "W",
 				DiffingMenuAlt:               "<c-e>",
@@ -452,8 +588,11 @@ func GetDefaultConfig() *UserConfig {
 				AppendNewline:                "<a-enter>",
 				ExtrasMenu:                   "@",
 				ToggleWhitespaceInDiffView:   "<c-w>",
+				ToggleSplitDiffView:          "|",
 				IncreaseContextInDiffView:    "}",
 				DecreaseContextInDiffView:    "{",
+				OpenInBrowserMenu:            "G",
+				Leader:                       "\\",
 			},
 			Status: KeybindingStatusConfig{
 				CheckForUpdate:      "u",
@@ -475,6 +614,7 @@ func GetDefaultConfig() *UserConfig {
 				ToggleTreeView:           "`",
 				OpenMergeTool:            "M",
 				OpenStatusFilter:         "<c-b>",
+				AutoResolveConflicts:     "u",
 			},
 			Branches: KeybindingBranchesConfig{
 				CopyPullRequestURL:     "<c-y>",
@@ -490,6 +630,7 @@ func GetDefaultConfig() *UserConfig {
 				PushTag:                "P",
 				SetUpstream:            "u",
 				FetchRemote:            "f",
+				DiffPullRequest:        "d",
 			},
 			Commits: KeybindingCommitsConfig{
 				SquashDown:                   "s",
@@ -514,6 +655,7 @@ func GetDefaultConfig() *UserConfig {
 				OpenLogMenu:                  "<c-l>",
 				OpenInBrowser:                "o",
 				ViewBisectOptions:            "b",
+				OpenLinkedIssue:              "I",
 			},
 			Stash: KeybindingStashConfig{
 				PopStash: "g",
@@ -522,10 +664,14 @@ func GetDefaultConfig() *UserConfig {
 				CheckoutCommitFile: "c",
 			},
 			Main: KeybindingMainConfig{
-				ToggleDragSelect:    "v",
-				ToggleDragSelectAlt: "V",
-				ToggleSelectHunk:    "a",
-				PickBothHunks:       "b",
+				ToggleDragSelect:     "v",
+				ToggleDragSelectAlt:  "V",
+				ToggleSelectHunk:     "a",
+				PickBothHunks:        "b",
+				ToggleThreeWayView:   "t",
+				EditHunk:             "E",
+				SplitHunk:            "s",
+				AutoResolveConflicts: "u",
 			},
 			Submodules: KeybindingSubmodulesConfig{
 				Init:     "i",
@@ -537,6 +683,12 @@ func GetDefaultConfig() *UserConfig {
 		DisableStartupPopups: false,
 		CustomCommands:       []CustomCommand(nil),
 		Services:             map[string]string(nil),
-		NotARepository:       "prompt",
+		HostingService: HostingServiceConfig{
+			PullRequests: false,
+			Tokens:       map[string]string(nil),
+			APIBaseURLs:  map[string]string(nil),
+			URLTemplates: map[string]URLTemplatesConfig(nil),
+		},
+		NotARepository: "prompt",
 	}
 }
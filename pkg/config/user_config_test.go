@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	yaml "github.com/jesseduffield/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomCommandContextUnmarshal(t *testing.T) {
+	scenarios := []struct {
+		testName  string
+		content   string
+		expected  CustomCommandContext
+		expectErr bool
+	}{
+		{"single context", "context: 'files'", "files", false},
+		{"comma-separated contexts", "context: 'localBranches, remoteBranches'", "localBranches, remoteBranches", false},
+		{"list of contexts", "context: ['localBranches', 'remoteBranches']", "localBranches,remoteBranches", false},
+		{"block list of contexts", "context:\n  - files\n  - commits", "files,commits", false},
+		{"not a context", "context: {files: true}", "", true},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			customCommand := CustomCommand{}
+			err := yaml.Unmarshal([]byte(s.content), &customCommand)
+			if s.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, customCommand.Context)
+		})
+	}
+}
@@ -21,6 +21,20 @@ func (gui *Gui) mainSectionChildren() []*boxlayout.Box {
 		}
 	}
 
+	if gui.showingThreeWayMerge() {
+		// the base, ours and theirs versions go above the file being resolved
+		return []*boxlayout.Box{
+			{
+				Window: "secondary",
+				Weight: 1,
+			},
+			{
+				Window: "main",
+				Weight: 2,
+			},
+		}
+	}
+
 	main := "main"
 	secondary := "secondary"
 	if gui.secondaryViewFocused() {
@@ -111,7 +125,7 @@ func (gui *Gui) infoSectionChildren(informationStr string, appStatus string) []*
 }
 
 func (gui *Gui) splitMainPanelSideBySide() bool {
-	if !gui.isMainPanelSplit() {
+	if !gui.isMainPanelSplit() || gui.showingThreeWayMerge() {
 		return false
 	}
 
@@ -31,9 +31,12 @@ func (gui *Gui) branchesRenderToMain() error {
 	if branch == nil {
 		task = NewRenderStringTask(gui.Tr.NoBranchesThisRepo)
 	} else {
-		cmdObj := gui.Git.Branch.GetGraphCmdObj(branch.Name)
-
-		task = NewRunPtyTask(cmdObj.GetCmd())
+		cmdObj, err := gui.Git.Branch.GetGraphCmdObj(branch.Name)
+		if err != nil {
+			task = NewRenderStringTask(err.Error())
+		} else {
+			task = NewRunPtyTask(cmdObj.GetCmd())
+		}
 	}
 
 	return gui.refreshMainViews(refreshMainOpts{
@@ -303,7 +306,10 @@ func (gui *Gui) deleteNamedBranch(selectedBranch *models.Branch, force bool) err
 		prompt: message,
 		handleConfirm: func() error {
 			gui.logAction(gui.Tr.Actions.DeleteBranch)
-			if err := gui.Git.Branch.Delete(selectedBranch.Name, force); err != nil {
+			err := gui.journalDeleteBranch(selectedBranch.Name, func() error {
+				return gui.Git.Branch.Delete(selectedBranch.Name, force)
+			})
+			if err != nil {
 				errMessage := err.Error()
 				if !force && strings.Contains(errMessage, "git branch -D ") {
 					return gui.deleteNamedBranch(selectedBranch, true)
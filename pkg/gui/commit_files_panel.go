@@ -42,11 +42,17 @@ func (gui *Gui) commitFilesRenderToMain() error {
 		return nil
 	}
 
-	to := gui.State.CommitFileTreeViewModel.GetParent()
-	from, reverse := gui.getFromAndReverseArgsForDiff(to)
-
-	cmdObj := gui.Git.WorkingTree.ShowFileDiffCmdObj(from, to, reverse, node.GetPath(), false)
-	task := NewRunPtyTask(cmdObj.GetCmd())
+	var task updateTask
+	if gui.showingLoadedPatch() {
+		diff := gui.Git.Patch.PatchManager.LoadedFileDiff(node.GetPath())
+		task = NewRenderStringTask(patch.NewPatchParser(gui.Log, diff, gui.patchParserOpts("main")).Render(-1, -1, nil))
+	} else {
+		to := gui.State.CommitFileTreeViewModel.GetParent()
+		from, reverse := gui.getFromAndReverseArgsForDiff(to)
+
+		cmdObj := gui.Git.WorkingTree.ShowFileDiffCmdObj(from, to, reverse, node.GetPath(), false)
+		task = gui.newDiffTask(cmdObj)
+	}
 
 	return gui.refreshMainViews(refreshMainOpts{
 		main: &viewUpdateOpts{
@@ -63,6 +69,10 @@ func (gui *Gui) handleCheckoutCommitFile() error {
 		return nil
 	}
 
+	if gui.showingLoadedPatch() {
+		return gui.createErrorPanel(gui.Tr.NotAvailableForLoadedPatch)
+	}
+
 	gui.logAction(gui.Tr.Actions.CheckoutFile)
 	if err := gui.Git.WorkingTree.CheckoutFile(gui.State.CommitFileTreeViewModel.GetParent(), node.GetPath()); err != nil {
 		return gui.surfaceError(err)
@@ -76,6 +86,10 @@ func (gui *Gui) handleDiscardOldFileChange() error {
 		return err
 	}
 
+	if gui.showingLoadedPatch() {
+		return gui.createErrorPanel(gui.Tr.NotAvailableForLoadedPatch)
+	}
+
 	fileName := gui.getSelectedCommitFileName()
 
 	return gui.ask(askOpts{
@@ -107,9 +121,15 @@ func (gui *Gui) refreshCommitFilesView() error {
 	to := gui.State.Panels.CommitFiles.refName
 	from, reverse := gui.getFromAndReverseArgsForDiff(to)
 
-	files, err := gui.Git.Loaders.CommitFiles.GetFilesInDiff(from, to, reverse)
-	if err != nil {
-		return gui.surfaceError(err)
+	var files []*models.CommitFile
+	if gui.showingLoadedPatch() {
+		files = gui.Git.Patch.PatchManager.LoadedFiles()
+	} else {
+		var err error
+		files, err = gui.Git.Loaders.CommitFiles.GetFilesInDiff(from, to, reverse)
+		if err != nil {
+			return gui.surfaceError(err)
+		}
 	}
 	gui.State.CommitFileTreeViewModel.SetParent(to)
 	gui.State.CommitFileTreeViewModel.SetFiles(files)
@@ -170,7 +190,8 @@ func (gui *Gui) handleToggleFileForPatch() error {
 			return gui.surfaceError(err)
 		}
 
-		if gui.Git.Patch.PatchManager.IsEmpty() {
+		// a loaded patch has nowhere to come back from, so we keep it around
+		if gui.Git.Patch.PatchManager.IsEmpty() && !gui.Git.Patch.PatchManager.LoadedFromFile() {
 			gui.Git.Patch.PatchManager.Reset()
 		}
 
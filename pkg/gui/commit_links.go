@@ -0,0 +1,110 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/links"
+)
+
+// We highlight the URLs in commit messages, along with references to issues in
+// the issue trackers from the user's config. Clicking on one opens it, as does
+// picking it from the menu of the selected commit's links.
+
+// getLinkifier returns the linkifier for the repo's remote. We render commits
+// all the time, so we only build it again when the user config or the remote
+// has changed, rather than compiling the issue trackers' regexes every time.
+func (gui *Gui) getLinkifier() *links.Linkifier {
+	gui.Mutexes.LinkifierMutex.Lock()
+	defer gui.Mutexes.LinkifierMutex.Unlock()
+
+	remoteURL := gui.Git.Config.GetRemoteURL()
+	if gui.linkifier != nil && gui.linkifierRemoteURL == remoteURL {
+		return gui.linkifier
+	}
+
+	// issue trackers which need the repo's URL go without if we can't work it out
+	repoURL, _ := gui.getHostingServiceMgr().GetRepoURL()
+
+	linkifier, err := links.NewLinkifier(gui.UserConfig.Git.IssueTrackers, repoURL)
+	if err != nil {
+		// we've already validated the config, so this shouldn't happen
+		gui.Log.Error(err)
+		return nil
+	}
+
+	gui.linkifier = linkifier
+	gui.linkifierRemoteURL = remoteURL
+	return linkifier
+}
+
+// resetLinkifier makes us build the linkifier again the next time we need it,
+// e.g. because the user config has changed
+func (gui *Gui) resetLinkifier() {
+	gui.Mutexes.LinkifierMutex.Lock()
+	defer gui.Mutexes.LinkifierMutex.Unlock()
+
+	gui.linkifier = nil
+}
+
+// openLinkUnderCursor opens the link that the user has clicked on in the view,
+// if they've clicked on one. We go by the word under the cursor, which works
+// for anything without spaces in it.
+func (gui *Gui) openLinkUnderCursor(view *gocui.View) (bool, error) {
+	cx, cy := view.Cursor()
+	word, err := view.Word(cx, cy)
+	if err != nil {
+		return false, nil
+	}
+
+	found := gui.getLinkifier().Find(word)
+	if len(found) == 0 {
+		return false, nil
+	}
+
+	gui.logAction(gui.Tr.Actions.OpenLinkedIssue)
+	return true, gui.OSCommand.OpenLink(found[0].URL)
+}
+
+func (gui *Gui) handleClickSelectedCommit() error {
+	if opened, err := gui.openLinkUnderCursor(gui.Views.Commits); opened {
+		return err
+	}
+
+	return gui.handleViewCommitFiles()
+}
+
+func (gui *Gui) handleOpenLinkedIssue() error {
+	commit := gui.getSelectedLocalCommit()
+	if commit == nil {
+		return nil
+	}
+
+	message, err := gui.Git.Commit.GetCommitMessage(commit.Sha)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	menuItems := []*menuItem{}
+	seenURLs := map[string]bool{}
+	for _, link := range gui.getLinkifier().Find(message) {
+		link := link
+		if seenURLs[link.URL] {
+			continue
+		}
+		seenURLs[link.URL] = true
+
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{link.Text, style.FgBlue.Sprint(link.URL)},
+			onPress: func() error {
+				gui.logAction(gui.Tr.Actions.OpenLinkedIssue)
+				return gui.OSCommand.OpenLink(link.URL)
+			},
+		})
+	}
+
+	if len(menuItems) == 0 {
+		return gui.createErrorPanel(gui.Tr.NoLinkedIssues)
+	}
+
+	return gui.createMenu(gui.Tr.LinkedIssuesTitle, menuItems, createMenuOptions{showCancel: true})
+}
@@ -0,0 +1,36 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/jesseduffield/lazygit/pkg/commands/git_config"
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLinkifierIsReused(t *testing.T) {
+	gui := NewDummyGui()
+	gui.UserConfig.Git.IssueTrackers = []config.IssueTrackerConfig{
+		{Pattern: `#(?P<number>\d+)`, URL: "{{.RepoURL}}/issues/{{.number}}"},
+	}
+	setRemoteURL := func(remoteURL string) {
+		gitConfig := git_config.NewFakeGitConfig(map[string]string{"remote.origin.url": remoteURL})
+		gui.Git = &commands.GitCommand{Config: git_commands.NewConfigCommands(gui.Common, gitConfig, nil)}
+	}
+
+	setRemoteURL("git@github.com:peter/lazygit.git")
+	linkifier := gui.getLinkifier()
+	assert.Same(t, linkifier, gui.getLinkifier())
+	assert.Equal(t, "https://github.com/peter/lazygit/issues/5", linkifier.Find("fixes #5")[0].URL)
+
+	// the issue links depend on the remote
+	setRemoteURL("git@github.com:mark/lazygit.git")
+	linkifier = gui.getLinkifier()
+	assert.Equal(t, "https://github.com/mark/lazygit/issues/5", linkifier.Find("fixes #5")[0].URL)
+
+	// and on the user config
+	gui.resetLinkifier()
+	assert.NotSame(t, linkifier, gui.getLinkifier())
+}
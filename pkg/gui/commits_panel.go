@@ -46,7 +46,7 @@ func (gui *Gui) branchCommitsRenderToMain() error {
 		task = NewRenderStringTask(gui.Tr.NoCommitsThisBranch)
 	} else {
 		cmdObj := gui.Git.Commit.ShowCmdObj(commit.Sha, gui.State.Modes.Filtering.GetPath())
-		task = NewRunPtyTask(cmdObj.GetCmd())
+		task = gui.newDiffTask(cmdObj)
 	}
 
 	return gui.refreshMainViews(refreshMainOpts{
@@ -304,13 +304,16 @@ func (gui *Gui) handleMidRebaseCommand(action string) (bool, error) {
 		return true, gui.createErrorPanel(gui.Tr.LcRewordNotSupported)
 	}
 
-	gui.logAction("Update rebase TODO")
+	gui.logAction(gui.Tr.Actions.UpdateRebaseTodo)
 	gui.logCommand(
 		fmt.Sprintf("Updating rebase action of commit %s to '%s'", selectedCommit.ShortSha(), action),
 		false,
 	)
 
-	if err := gui.Git.Rebase.EditRebaseTodo(gui.State.Panels.Commits.SelectedLineIdx, action); err != nil {
+	description := journalDescription(gui.Tr.Actions.UpdateRebaseTodo, fmt.Sprintf("%s %s", action, selectedCommit.ShortSha()))
+	if err := gui.withRebaseJournal(description, false, func() error {
+		return gui.Git.Rebase.EditRebaseTodo(gui.State.Panels.Commits.SelectedLineIdx, action)
+	}); err != nil {
 		return false, gui.surfaceError(err)
 	}
 
@@ -360,7 +363,10 @@ func (gui *Gui) handleCommitMoveDown() error {
 		gui.logAction(gui.Tr.Actions.MoveCommitDown)
 		gui.logCommand(fmt.Sprintf("Moving commit %s down", selectedCommit.ShortSha()), false)
 
-		if err := gui.Git.Rebase.MoveTodoDown(index); err != nil {
+		description := journalDescription(gui.Tr.Actions.MoveCommitDown, selectedCommit.ShortSha())
+		if err := gui.withRebaseJournal(description, false, func() error {
+			return gui.Git.Rebase.MoveTodoDown(index)
+		}); err != nil {
 			return gui.surfaceError(err)
 		}
 		gui.State.Panels.Commits.SelectedLineIdx++
@@ -397,7 +403,10 @@ func (gui *Gui) handleCommitMoveUp() error {
 			false,
 		)
 
-		if err := gui.Git.Rebase.MoveTodoDown(index - 1); err != nil {
+		description := journalDescription(gui.Tr.Actions.MoveCommitUp, selectedCommit.ShortSha())
+		if err := gui.withRebaseJournal(description, false, func() error {
+			return gui.Git.Rebase.MoveTodoDown(index - 1)
+		}); err != nil {
 			return gui.surfaceError(err)
 		}
 		gui.State.Panels.Commits.SelectedLineIdx--
@@ -0,0 +1,290 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/jesseduffield/lazygit/pkg/commands/hosting_service"
+	"github.com/jesseduffield/lazygit/pkg/commands/patch"
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/jesseduffield/lazygit/pkg/gui/presentation"
+	"github.com/jesseduffield/lazygit/pkg/gui/presentation/authors"
+	"github.com/jesseduffield/lazygit/pkg/links"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// editors tend to write a file in several steps (truncate, write, rename etc)
+// so we wait for things to settle down before reloading the config
+const CONFIG_RELOAD_DEBOUNCE = time.Millisecond * 200
+
+// we watch the directories containing the config files rather than the files
+// themselves, because many editors save by writing a new file and renaming it
+// over the old one, which would otherwise lose us our watch.
+func (gui *Gui) watchConfigFilesForChanges() {
+	if replaying() {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		gui.Log.Error(err)
+		return
+	}
+
+	watchedDirs := map[string]bool{}
+	for _, path := range gui.Config.GetUserConfigPaths() {
+		dir := filepath.Dir(path)
+		if watchedDirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			// swallowing errors here because hot reloading is only a nicety
+			gui.Log.Error(err)
+			continue
+		}
+		watchedDirs[dir] = true
+	}
+
+	gui.configWatcher = watcher
+
+	go utils.Safe(func() {
+		// a nil channel blocks forever, which is what we want when nothing has changed
+		var reload <-chan time.Time
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op == fsnotify.Chmod || !gui.isUserConfigPath(event.Name) {
+					continue
+				}
+				reload = time.After(CONFIG_RELOAD_DEBOUNCE)
+			case <-reload:
+				reload = nil
+				gui.OnUIThread(gui.onUserConfigFileChanged)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				if err != nil {
+					gui.Log.Error(err)
+				}
+			}
+		}
+	})
+}
+
+func (gui *Gui) isUserConfigPath(path string) bool {
+	for _, configPath := range gui.Config.GetUserConfigPaths() {
+		if filepath.Clean(configPath) == filepath.Clean(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (gui *Gui) onUserConfigFileChanged() error {
+	// we need to obtain these before swapping out the config so that we know
+	// which keybindings to remove
+	oldBindings := gui.getAllKeybindings()
+
+	if err := gui.reloadUserConfig(); err != nil {
+		return gui.createErrorPanel(fmt.Sprintf(gui.Tr.UserConfigReloadError, err.Error()))
+	}
+
+	gui.Log.Info("reloaded user config")
+
+	return gui.applyUserConfig(oldBindings)
+}
+
+// reloadUserConfig reads the user config files again, keeping the last good
+// config if the new one doesn't parse or isn't valid
+func (gui *Gui) reloadUserConfig() error {
+	oldUserConfig := gui.Config.GetUserConfig()
+
+	if err := gui.Config.ReloadUserConfig(); err != nil {
+		return err
+	}
+
+	if err := gui.validateUserConfig(gui.Config.GetUserConfig()); err != nil {
+		gui.Config.SetUserConfig(oldUserConfig)
+		return err
+	}
+
+	return nil
+}
+
+// applyUserConfig re-applies everything we derive from the user config at
+// startup, so that changes take effect without restarting lazygit
+func (gui *Gui) applyUserConfig(oldBindings []*Binding) error {
+	gui.UserConfig = gui.Config.GetUserConfig()
+
+	gui.applyGocuiConfig()
+
+	if err := gui.setColorScheme(); err != nil {
+		return err
+	}
+
+	gui.resetLinkifier()
+
+	authors.SetCustomAuthors(gui.UserConfig.Gui.AuthorColors)
+	presentation.SetCustomBranches(gui.UserConfig.Gui.BranchColors)
+
+	if err := gui.resetKeybindings(oldBindings); err != nil {
+		return err
+	}
+
+	close(gui.refresherStopChan)
+	gui.refresherStopChan = make(chan struct{})
+	gui.startBackgroundRefresh()
+	if gui.UserConfig.Git.AutoFetch {
+		gui.startBackgroundFetchTicker()
+	}
+	gui.startBackgroundPullRequestRefresh()
+
+	return gui.refreshSidePanels(refreshOptions{mode: ASYNC})
+}
+
+// validateUserConfig catches the things which we'd otherwise only discover
+// (and exit on) when setting up keybindings and timers, so that a bad edit to
+// the config file can't take down a running lazygit.
+func (gui *Gui) validateUserConfig(userConfig *config.UserConfig) error {
+	if err := gui.validateKeybindingConfig(userConfig.Keybinding); err != nil {
+		return err
+	}
+
+	for contextKey, override := range userConfig.Keybinding.Contexts {
+		if _, ok := gui.contextForContextKey(ContextKey(contextKey)); !ok {
+			return fmt.Errorf("Unknown context in keybinding.contexts: %s", contextKey)
+		}
+		if err := gui.validateKeybindingConfig(config.MergeKeybindingConfigs(userConfig.Keybinding, override)); err != nil {
+			return fmt.Errorf("%s (in keybinding.contexts.%s)", err.Error(), contextKey)
+		}
+	}
+
+	for _, customCommand := range userConfig.CustomCommands {
+		if err := gui.validateCustomCommand(customCommand, userConfig.Keybinding.Universal.Leader, false); err != nil {
+			return err
+		}
+	}
+
+	// custom commands are allowed to take precedence over our own bindings, so
+	// we only check them for conflicts amongst themselves
+	conflicts := append(
+		getKeybindingConflicts(gui.getCustomCommandKeybindings(userConfig.CustomCommands)),
+		getKeybindingConflicts(gui.getInitialKeybindingsForConfig(userConfig.Keybinding))...,
+	)
+	if len(conflicts) > 0 {
+		return fmt.Errorf("Conflicting keybindings:\n%s", strings.Join(conflicts, "\n"))
+	}
+
+	if userConfig.Refresher.RefreshInterval <= 0 {
+		return errors.New("refresher.refreshInterval must be greater than zero")
+	}
+
+	if userConfig.Refresher.FetchInterval <= 0 {
+		return errors.New("refresher.fetchInterval must be greater than zero")
+	}
+
+	if userConfig.HostingService.PullRequests && userConfig.Refresher.PullRequestInterval <= 0 {
+		return errors.New("refresher.pullRequestInterval must be greater than zero")
+	}
+
+	if err := git_commands.ValidateBranchLogCmd(userConfig.Git.BranchLogCmd); err != nil {
+		return fmt.Errorf("Error parsing git.branchLogCmd: %v", err)
+	}
+
+	if err := git_commands.ValidateEditCommandTemplate(userConfig.OS.EditCommandTemplate); err != nil {
+		return fmt.Errorf("Error parsing os.editCommandTemplate: %v", err)
+	}
+
+	for key, urlTemplates := range userConfig.HostingService.URLTemplates {
+		if err := hosting_service.ValidateURLTemplates(urlTemplates); err != nil {
+			return fmt.Errorf("Error in hostingService.urlTemplates.%s.%v", key, err)
+		}
+	}
+
+	if _, err := links.NewLinkifier(userConfig.Git.IssueTrackers, ""); err != nil {
+		return fmt.Errorf("Error parsing git.issueTrackers: %v", err)
+	}
+
+	switch userConfig.Git.DiffHighlight {
+	case patch.INTRA_LINE_HIGHLIGHT_WORD, patch.INTRA_LINE_HIGHLIGHT_CHAR, patch.INTRA_LINE_HIGHLIGHT_NONE:
+	default:
+		return fmt.Errorf("git.diffHighlight must be one of 'word', 'char' or 'none', got: %s", userConfig.Git.DiffHighlight)
+	}
+
+	return nil
+}
+
+func (gui *Gui) validateKeybindingConfig(keybindingConfig config.KeybindingConfig) error {
+	leader := keybindingConfig.Universal.Leader
+
+	if err := validateKeys(reflect.ValueOf(keybindingConfig), leader); err != nil {
+		return err
+	}
+
+	if len(keybindingConfig.Universal.JumpToBlock) != 5 {
+		return errors.New("Jump to block keybindings cannot be set. Exactly 5 keybindings must be supplied.")
+	}
+
+	// these keys are handed straight to gocui or our popups, which only deal in
+	// single keys
+	singleKeys := map[string]string{
+		"return":        keybindingConfig.Universal.Return,
+		"confirm":       keybindingConfig.Universal.Confirm,
+		"confirm-alt1":  keybindingConfig.Universal.ConfirmAlt1,
+		"togglePanel":   keybindingConfig.Universal.TogglePanel,
+		"nextMatch":     keybindingConfig.Universal.NextMatch,
+		"prevMatch":     keybindingConfig.Universal.PrevMatch,
+		"appendNewline": keybindingConfig.Universal.AppendNewline,
+	}
+	for name, keyStr := range singleKeys {
+		key, err := keyFromString(keyStr, leader)
+		if err != nil {
+			return err
+		}
+		if _, ok := key.(keySequence); ok {
+			return fmt.Errorf("keybinding.universal.%s must be a single key, not a sequence", name)
+		}
+	}
+
+	return nil
+}
+
+// validateKeys walks the keybinding config struct, checking that every key
+// can be parsed
+func validateKeys(value reflect.Value, leader string) error {
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			// an empty leader just means the user doesn't want one
+			if value.Type().Field(i).Name == "Leader" && leader == "" {
+				continue
+			}
+			if err := validateKeys(value.Field(i), leader); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateKeys(value.Index(i), leader); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if _, err := keyFromString(value.String(), leader); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
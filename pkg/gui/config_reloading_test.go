@@ -0,0 +1,95 @@
+package gui
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newGuiWithUserConfigFile returns a dummy gui whose user config is read from
+// a file with the given contents, along with a function for changing them
+func newGuiWithUserConfigFile(t *testing.T, content string) (*Gui, func(string)) {
+	dir, err := ioutil.TempDir("", "lazygit-config")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "config.yml")
+	writeConfig := func(content string) {
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	writeConfig(content)
+
+	gui := NewDummyGui()
+	appConfig := gui.Config.(*config.AppConfig)
+	appConfig.UserConfigPaths = []string{path}
+	assert.NoError(t, appConfig.ReloadUserConfig())
+	gui.UserConfig = appConfig.GetUserConfig()
+
+	return gui, writeConfig
+}
+
+func TestReloadUserConfig(t *testing.T) {
+	gui, writeConfig := newGuiWithUserConfigFile(t, "refresher:\n  refreshInterval: 5\n")
+	oldUserConfig := gui.Config.GetUserConfig()
+
+	writeConfig("refresher:\n  refreshInterval: 20\n")
+	assert.NoError(t, gui.reloadUserConfig())
+	assert.Equal(t, 20, gui.Config.GetUserConfig().Refresher.RefreshInterval)
+	assert.NotSame(t, oldUserConfig, gui.Config.GetUserConfig())
+}
+
+func TestReloadUserConfigKeepsLastGoodConfig(t *testing.T) {
+	scenarios := []struct {
+		testName      string
+		content       string
+		expectedError string
+	}{
+		{
+			testName:      "invalid config",
+			content:       "refresher:\n  refreshInterval: 0\n",
+			expectedError: "refresher.refreshInterval must be greater than zero",
+		},
+		{
+			testName:      "invalid yaml",
+			content:       "refresher: [\n",
+			expectedError: "did not find expected node content",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			gui, writeConfig := newGuiWithUserConfigFile(t, "refresher:\n  refreshInterval: 5\n")
+			oldUserConfig := gui.Config.GetUserConfig()
+
+			writeConfig(s.content)
+			err := gui.reloadUserConfig()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), s.expectedError)
+			assert.Same(t, oldUserConfig, gui.Config.GetUserConfig())
+			assert.Equal(t, 5, gui.Config.GetUserConfig().Refresher.RefreshInterval)
+		})
+	}
+}
+
+func TestResetKeybindingsAfterReload(t *testing.T) {
+	gui, writeConfig := newGuiWithUserConfigFile(t, "")
+	gui.g = &gocui.Gui{}
+	assert.NoError(t, gui.setKeybindings(gui.getAllKeybindings()))
+
+	oldBindings := gui.getAllKeybindings()
+	writeConfig("keybinding:\n  universal:\n    quit: Q\n")
+	assert.NoError(t, gui.reloadUserConfig())
+	// this is what applyUserConfig does before resetting the keybindings
+	gui.UserConfig = gui.Config.GetUserConfig()
+	assert.NoError(t, gui.resetKeybindings(oldBindings))
+
+	// deleting a keybinding only fails if it isn't there
+	assert.Error(t, gui.g.DeleteKeybinding("", 'q', gocui.ModNone))
+	assert.NoError(t, gui.g.DeleteKeybinding("", 'Q', gocui.ModNone))
+}
@@ -0,0 +1,141 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// Not every conflict can be resolved by picking hunks in the merging panel.
+// When one side deleted the file, or the file is binary, or it's a submodule,
+// the choices are about the file as a whole, so we offer them in a menu.
+
+func (gui *Gui) handleCreateConflictMenu(file *models.File) error {
+	stages, err := gui.Git.WorkingTree.ConflictStages(file.Name)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	var menuItems []*menuItem
+	switch file.ShortStatus {
+	case "DU", "UD":
+		menuItems = []*menuItem{
+			gui.conflictMenuItem(gui.Tr.LcKeepDeleted, gui.Tr.KeepDeletedDescription, func() error {
+				return gui.Git.WorkingTree.RemoveConflictedFile(file.Name)
+			}),
+			gui.conflictMenuItem(gui.Tr.LcKeepModified, gui.Tr.KeepModifiedDescription, func() error {
+				return gui.Git.WorkingTree.StageFile(file.Name)
+			}),
+		}
+	case "DD":
+		menuItems = []*menuItem{
+			gui.conflictMenuItem(gui.Tr.LcDeleteFile, gui.Tr.DeleteFileDescription, func() error {
+				return gui.Git.WorkingTree.RemoveConflictedFile(file.Name)
+			}),
+		}
+	case "AU", "UA":
+		menuItems = []*menuItem{
+			gui.conflictMenuItem(gui.Tr.LcKeepFile, gui.Tr.KeepFileDescription, func() error {
+				return gui.Git.WorkingTree.StageFile(file.Name)
+			}),
+			gui.conflictMenuItem(gui.Tr.LcDeleteFile, gui.Tr.DeleteFileDescription, func() error {
+				return gui.Git.WorkingTree.RemoveConflictedFile(file.Name)
+			}),
+		}
+	default:
+		if isSubmoduleConflict(stages) {
+			menuItems = gui.submoduleConflictMenuItems(file, stages)
+		} else {
+			menuItems = []*menuItem{
+				gui.conflictMenuItem(gui.Tr.LcUseOurs, gui.Tr.UseOursDescription, func() error {
+					if err := gui.Git.WorkingTree.CheckoutOurs(file.Name); err != nil {
+						return err
+					}
+					return gui.Git.WorkingTree.StageFile(file.Name)
+				}),
+				gui.conflictMenuItem(gui.Tr.LcUseTheirs, gui.Tr.UseTheirsDescription, func() error {
+					if err := gui.Git.WorkingTree.CheckoutTheirs(file.Name); err != nil {
+						return err
+					}
+					return gui.Git.WorkingTree.StageFile(file.Name)
+				}),
+				gui.conflictMenuItem(gui.Tr.LcMarkAsResolved, gui.Tr.MarkAsResolvedDescription, func() error {
+					return gui.Git.WorkingTree.StageFile(file.Name)
+				}),
+			}
+		}
+	}
+
+	title := fmt.Sprintf(gui.Tr.ResolveConflictTitle, gui.conflictDescription(file.ShortStatus))
+	return gui.createMenu(title, menuItems, createMenuOptions{showCancel: true})
+}
+
+func (gui *Gui) submoduleConflictMenuItems(file *models.File, stages []*models.ConflictStage) []*menuItem {
+	menuItems := []*menuItem{}
+
+	for _, stage := range stages {
+		stage := stage
+		var label, description string
+		switch stage.Stage {
+		case 2:
+			label, description = gui.Tr.LcUseOurSubmoduleCommit, gui.Tr.UseOurSubmoduleCommitDescription
+		case 3:
+			label, description = gui.Tr.LcUseTheirSubmoduleCommit, gui.Tr.UseTheirSubmoduleCommitDescription
+		default:
+			continue
+		}
+
+		label = fmt.Sprintf(label, utils.ShortSha(stage.Sha))
+		menuItems = append(menuItems, gui.conflictMenuItem(label, description, func() error {
+			return gui.Git.Submodule.ResolveConflictWithCommit(file.Name, stage.Sha)
+		}))
+	}
+
+	return append(menuItems, gui.conflictMenuItem(gui.Tr.LcUseCheckedOutSubmoduleCommit, gui.Tr.UseCheckedOutCommitDescription, func() error {
+		return gui.Git.WorkingTree.StageFile(file.Name)
+	}))
+}
+
+func (gui *Gui) conflictMenuItem(label string, description string, resolve func() error) *menuItem {
+	return &menuItem{
+		displayStrings: []string{label, style.FgBlackLighter.Sprint(description)},
+		onPress: func() error {
+			gui.logAction(gui.Tr.Actions.ResolveConflict)
+			if err := resolve(); err != nil {
+				return gui.surfaceError(err)
+			}
+
+			return gui.refreshSidePanels(refreshOptions{mode: ASYNC, scope: []RefreshableView{FILES}})
+		},
+	}
+}
+
+func (gui *Gui) conflictDescription(shortStatus string) string {
+	switch shortStatus {
+	case "DU":
+		return gui.Tr.ConflictDeletedByUs
+	case "UD":
+		return gui.Tr.ConflictDeletedByThem
+	case "DD":
+		return gui.Tr.ConflictBothDeleted
+	case "AU":
+		return gui.Tr.ConflictAddedByUs
+	case "UA":
+		return gui.Tr.ConflictAddedByThem
+	case "AA":
+		return gui.Tr.ConflictBothAdded
+	default:
+		return gui.Tr.ConflictBothModified
+	}
+}
+
+func isSubmoduleConflict(stages []*models.ConflictStage) bool {
+	for _, stage := range stages {
+		if stage.IsSubmodule() {
+			return true
+		}
+	}
+	return false
+}
@@ -206,6 +206,9 @@ func (gui *Gui) activateContext(c Context, opts ...OnFocusOpts) error {
 	}
 	originalViewContextKey := ContextKey(v.Context)
 
+	// a key sequence started in one context shouldn't finish in another
+	gui.cancelKeySequence()
+
 	// ensure that any other window for which this view was active is now set to the default for that window.
 	gui.setViewAsActiveForWindow(v)
 
@@ -413,7 +416,7 @@ func (gui *Gui) changeMainViewsContext(contextKey ContextKey) {
 	}
 
 	switch contextKey {
-	case MAIN_NORMAL_CONTEXT_KEY, MAIN_PATCH_BUILDING_CONTEXT_KEY, MAIN_STAGING_CONTEXT_KEY, MAIN_MERGING_CONTEXT_KEY:
+	case MAIN_NORMAL_CONTEXT_KEY, MAIN_PATCH_BUILDING_CONTEXT_KEY, MAIN_STAGING_CONTEXT_KEY, MAIN_MERGING_CONTEXT_KEY, MAIN_MERGE_RESULT_CONTEXT_KEY:
 		gui.Views.Main.Context = string(contextKey)
 		gui.Views.Secondary.Context = string(contextKey)
 	default:
@@ -9,6 +9,7 @@ const (
 	REMOTES_CONTEXT_KEY             ContextKey = "remotes"
 	REMOTE_BRANCHES_CONTEXT_KEY     ContextKey = "remoteBranches"
 	TAGS_CONTEXT_KEY                ContextKey = "tags"
+	PULL_REQUESTS_CONTEXT_KEY       ContextKey = "pullRequests"
 	BRANCH_COMMITS_CONTEXT_KEY      ContextKey = "commits"
 	REFLOG_COMMITS_CONTEXT_KEY      ContextKey = "reflogCommits"
 	SUB_COMMITS_CONTEXT_KEY         ContextKey = "subCommits"
@@ -16,6 +17,7 @@ const (
 	STASH_CONTEXT_KEY               ContextKey = "stash"
 	MAIN_NORMAL_CONTEXT_KEY         ContextKey = "normal"
 	MAIN_MERGING_CONTEXT_KEY        ContextKey = "merging"
+	MAIN_MERGE_RESULT_CONTEXT_KEY   ContextKey = "mergeResult"
 	MAIN_PATCH_BUILDING_CONTEXT_KEY ContextKey = "patchBuilding"
 	MAIN_STAGING_CONTEXT_KEY        ContextKey = "staging"
 	MENU_CONTEXT_KEY                ContextKey = "menu"
@@ -35,6 +37,7 @@ var allContextKeys = []ContextKey{
 	REMOTES_CONTEXT_KEY,
 	REMOTE_BRANCHES_CONTEXT_KEY,
 	TAGS_CONTEXT_KEY,
+	PULL_REQUESTS_CONTEXT_KEY,
 	BRANCH_COMMITS_CONTEXT_KEY,
 	REFLOG_COMMITS_CONTEXT_KEY,
 	SUB_COMMITS_CONTEXT_KEY,
@@ -42,6 +45,7 @@ var allContextKeys = []ContextKey{
 	STASH_CONTEXT_KEY,
 	MAIN_NORMAL_CONTEXT_KEY,
 	MAIN_MERGING_CONTEXT_KEY,
+	MAIN_MERGE_RESULT_CONTEXT_KEY,
 	MAIN_PATCH_BUILDING_CONTEXT_KEY,
 	MAIN_STAGING_CONTEXT_KEY,
 	MENU_CONTEXT_KEY,
@@ -63,6 +67,7 @@ type ContextTree struct {
 	Remotes        IListContext
 	RemoteBranches IListContext
 	Tags           IListContext
+	PullRequests   IListContext
 	BranchCommits  IListContext
 	CommitFiles    IListContext
 	ReflogCommits  IListContext
@@ -73,6 +78,7 @@ type ContextTree struct {
 	Staging        Context
 	PatchBuilding  Context
 	Merging        Context
+	MergeResult    Context
 	Credentials    Context
 	Confirmation   Context
 	CommitMessage  Context
@@ -89,6 +95,7 @@ func (gui *Gui) allContexts() []Context {
 		gui.State.Contexts.Remotes,
 		gui.State.Contexts.RemoteBranches,
 		gui.State.Contexts.Tags,
+		gui.State.Contexts.PullRequests,
 		gui.State.Contexts.BranchCommits,
 		gui.State.Contexts.CommitFiles,
 		gui.State.Contexts.ReflogCommits,
@@ -100,6 +107,7 @@ func (gui *Gui) allContexts() []Context {
 		gui.State.Contexts.Normal,
 		gui.State.Contexts.Staging,
 		gui.State.Contexts.Merging,
+		gui.State.Contexts.MergeResult,
 		gui.State.Contexts.PatchBuilding,
 		gui.State.Contexts.SubCommits,
 		gui.State.Contexts.Suggestions,
@@ -126,6 +134,7 @@ func (gui *Gui) contextTree() ContextTree {
 		SubCommits:     gui.subCommitsListContext(),
 		Branches:       gui.branchesListContext(),
 		Tags:           gui.tagsListContext(),
+		PullRequests:   gui.pullRequestsListContext(),
 		Stash:          gui.stashListContext(),
 		Normal: &BasicContext{
 			OnFocus: func(opts ...OnFocusOpts) error {
@@ -173,6 +182,13 @@ func (gui *Gui) contextTree() ContextTree {
 			Key:             MAIN_MERGING_CONTEXT_KEY,
 			OnGetOptionsMap: gui.getMergingOptions,
 		},
+		MergeResult: &BasicContext{
+			OnFocusLost:     gui.onMergeResultFocusLost,
+			Kind:            MAIN_CONTEXT,
+			ViewName:        "main",
+			Key:             MAIN_MERGE_RESULT_CONTEXT_KEY,
+			OnGetOptionsMap: gui.getMergeResultOptions,
+		},
 		Credentials: &BasicContext{
 			OnFocus:  OnFocusWrapper(gui.handleCredentialsViewFocused),
 			Kind:     PERSISTENT_POPUP,
@@ -253,6 +269,10 @@ func (tree ContextTree) initialViewTabContextMap() map[string][]tabContext {
 				tab:      "Tags",
 				contexts: []Context{tree.Tags},
 			},
+			{
+				tab:      "Pull Requests",
+				contexts: []Context{tree.PullRequests},
+			},
 		},
 		"commits": {
 			{
@@ -1,18 +1,26 @@
 package gui
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
+	"fmt"
 	"log"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/jesseduffield/lazygit/pkg/commands/types/enums"
 	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/gui/types"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
@@ -26,11 +34,53 @@ type CustomCommandObjects struct {
 	SelectedRemoteBranch   *models.RemoteBranch
 	SelectedRemote         *models.Remote
 	SelectedTag            *models.Tag
+	SelectedPullRequest    *models.PullRequest
 	SelectedStashEntry     *models.StashEntry
 	SelectedCommitFile     *models.CommitFile
 	SelectedCommitFilePath string
 	CheckedOutBranch       *models.Branch
 	PromptResponses        []string
+	// the responses to prompts which have a key, by key. A multi-select prompt's
+	// response is a utils.StringList, and any other's is a string.
+	Form map[string]interface{}
+
+	// absolute paths
+	RepoRoot string
+	GitDir   string
+
+	IsRebasing  bool
+	IsMerging   bool
+	IsBisecting bool
+
+	// the lines selected in the staging view, if that's where we are
+	SelectedLineRange *LineRange
+	// the range between the commit marked for diffing and the selected commit,
+	// if we're in diffing mode with a commit selected
+	SelectedCommitRange *CommitRange
+	// the paths of the files with staged changes
+	StagedFiles []string
+
+	// the upstream of the checked out branch, if it has one
+	UpstreamRemote string
+	UpstreamBranch string
+
+	// the ref being diffed against, in diffing mode
+	DiffRef string
+	// the path being filtered by, in filtering mode
+	FilterPath string
+}
+
+// LineRange is the line numbers, counting from one, of the first and last lines
+// of a range in the new version of a file
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// CommitRange is a range of commits, for use as `{{.From}}..{{.To}}`
+type CommitRange struct {
+	From string
+	To   string
 }
 
 type commandMenuEntry struct {
@@ -38,8 +88,64 @@ type commandMenuEntry struct {
 	value string
 }
 
-func (gui *Gui) resolveTemplate(templateStr string, promptResponses []string) (string, error) {
-	objects := CustomCommandObjects{
+// customCommandResponses holds the responses to a custom command's prompts
+type customCommandResponses struct {
+	prompts []config.CustomCommandPrompt
+	// each response as a string, which for a multi-select prompt is its values
+	// joined by the prompt's separator
+	values []string
+	// the values of the multi-select prompts, which are nil for other prompts
+	lists [][]string
+}
+
+func newCustomCommandResponses(prompts []config.CustomCommandPrompt) *customCommandResponses {
+	return &customCommandResponses{
+		prompts: prompts,
+		values:  make([]string, len(prompts)),
+		lists:   make([][]string, len(prompts)),
+	}
+}
+
+func (self *customCommandResponses) set(idx int, value string) {
+	self.values[idx] = value
+}
+
+// setList sets the response to a multi-select prompt. We keep the values apart
+// so that a command can quote each of them.
+func (self *customCommandResponses) setList(idx int, values []string, separator string) {
+	self.values[idx] = strings.Join(values, separator)
+	self.lists[idx] = values
+}
+
+func (self *customCommandResponses) form() map[string]interface{} {
+	form := map[string]interface{}{}
+	for i, prompt := range self.prompts {
+		if prompt.Key == "" {
+			continue
+		}
+		if self.lists[i] != nil {
+			form[prompt.Key] = utils.StringList(self.lists[i])
+		} else {
+			form[prompt.Key] = self.values[i]
+		}
+	}
+	return form
+}
+
+func (gui *Gui) getCustomCommandObjects(responses *customCommandResponses) CustomCommandObjects {
+	repoRoot, gitDir, err := gui.Git.Status.RepoPaths()
+	if err != nil {
+		gui.Log.Error(err)
+	}
+
+	workingTreeState := gui.Git.Status.WorkingTreeState()
+
+	upstreamRemote, upstreamBranch := "", ""
+	if branch := gui.currentBranch(); branch != nil {
+		upstreamRemote, upstreamBranch = branch.UpstreamRemote, branch.UpstreamBranch
+	}
+
+	return CustomCommandObjects{
 		SelectedFile:           gui.getSelectedFile(),
 		SelectedPath:           gui.getSelectedPath(),
 		SelectedLocalCommit:    gui.getSelectedLocalCommit(),
@@ -48,74 +154,235 @@ func (gui *Gui) resolveTemplate(templateStr string, promptResponses []string) (s
 		SelectedRemoteBranch:   gui.getSelectedRemoteBranch(),
 		SelectedRemote:         gui.getSelectedRemote(),
 		SelectedTag:            gui.getSelectedTag(),
+		SelectedPullRequest:    gui.getSelectedPullRequest(),
 		SelectedStashEntry:     gui.getSelectedStashEntry(),
 		SelectedCommitFile:     gui.getSelectedCommitFile(),
 		SelectedCommitFilePath: gui.getSelectedCommitFilePath(),
 		SelectedSubCommit:      gui.getSelectedSubCommit(),
 		CheckedOutBranch:       gui.currentBranch(),
-		PromptResponses:        promptResponses,
+		PromptResponses:        responses.values,
+		Form:                   responses.form(),
+		RepoRoot:               repoRoot,
+		GitDir:                 gitDir,
+		IsRebasing:             workingTreeState == enums.REBASE_MODE_REBASING,
+		IsMerging:              workingTreeState == enums.REBASE_MODE_MERGING,
+		IsBisecting:            gui.State.BisectInfo != nil && gui.State.BisectInfo.Started(),
+		SelectedLineRange:      gui.getSelectedStagingLineRange(),
+		SelectedCommitRange:    gui.getSelectedCommitRange(),
+		StagedFiles:            gui.getStagedFilePaths(),
+		UpstreamRemote:         upstreamRemote,
+		UpstreamBranch:         upstreamBranch,
+		DiffRef:                gui.State.Modes.Diffing.Ref,
+		FilterPath:             gui.State.Modes.Filtering.GetPath(),
+	}
+}
+
+func (gui *Gui) getSelectedStagingLineRange() *LineRange {
+	state := gui.State.Panels.LineByLine
+	if state == nil || gui.currentContext().GetKey() != MAIN_STAGING_CONTEXT_KEY {
+		return nil
+	}
+
+	start, end := state.SelectedLineNumbers()
+	return &LineRange{Start: start, End: end}
+}
+
+func (gui *Gui) getSelectedCommitRange() *CommitRange {
+	if !gui.State.Modes.Diffing.Active() {
+		return nil
 	}
 
-	return utils.ResolveTemplate(templateStr, objects)
+	var commit *models.Commit
+	switch gui.currentContext().GetKey() {
+	case BRANCH_COMMITS_CONTEXT_KEY:
+		commit = gui.getSelectedLocalCommit()
+	case REFLOG_COMMITS_CONTEXT_KEY:
+		commit = gui.getSelectedReflogCommit()
+	case SUB_COMMITS_CONTEXT_KEY:
+		commit = gui.getSelectedSubCommit()
+	}
+	if commit == nil {
+		return nil
+	}
+
+	return &CommitRange{From: gui.State.Modes.Diffing.Ref, To: commit.Sha}
+}
+
+func (gui *Gui) getStagedFilePaths() []string {
+	paths := []string{}
+	for _, file := range gui.State.FileTreeViewModel.GetAllFiles() {
+		if file.HasStagedChanges {
+			paths = append(paths, file.Name)
+		}
+	}
+	return paths
+}
+
+func (gui *Gui) resolveTemplate(templateStr string, responses *customCommandResponses) (string, error) {
+	return utils.ResolveTemplateWithFuncs(templateStr, gui.getCustomCommandObjects(responses), gui.customCommandTemplateFuncs())
+}
+
+// resolveShellTemplate is for the templates of commands that we run, so it
+// escapes the values that go into them
+func (gui *Gui) resolveShellTemplate(templateStr string, responses *customCommandResponses) (string, error) {
+	return utils.ResolveShellTemplate(templateStr, gui.getCustomCommandObjects(responses), gui.customCommandTemplateFuncs(), gui.OSCommand.Quote)
+}
+
+// customCommandConditionFuncs are the functions available in a custom
+// command's 'when' condition, on top of text/template's own
+var customCommandConditionFuncs = template.FuncMap{
+	"glob": func(pattern string, name string) (bool, error) {
+		return filepath.Match(pattern, name)
+	},
+	"matches": func(pattern string, str string) (bool, error) {
+		return regexp.MatchString(pattern, str)
+	},
+}
+
+// customCommandTemplateFuncs are the functions available in a custom command's
+// templates, so that values can be passed to the shell safely
+func (gui *Gui) customCommandTemplateFuncs() template.FuncMap {
+	funcs := template.FuncMap{
+		"quote":    gui.OSCommand.Quote,
+		"base":     filepath.Base,
+		"dir":      filepath.Dir,
+		"ext":      filepath.Ext,
+		"joinPath": filepath.Join,
+	}
+	for name, f := range customCommandConditionFuncs {
+		funcs[name] = f
+	}
+	return funcs
+}
+
+// evaluateCondition evaluates a 'when' condition: a template pipeline like
+// `.CheckedOutBranch.UpstreamBranch`, optionally wrapped in braces, which holds
+// if it's not empty in the same sense as a template's 'if'. A condition that
+// refers to a field of something that isn't there, e.g. the selected file when
+// there are no files, doesn't hold.
+func evaluateCondition(condition string, objects CustomCommandObjects) (bool, error) {
+	tmpl, err := parseCondition(condition)
+	if err != nil {
+		return false, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, objects); err != nil {
+		if strings.Contains(err.Error(), "nil pointer evaluating") {
+			return false, nil
+		}
+		return false, fmt.Errorf("Error evaluating custom command condition '%s': %v", condition, err)
+	}
+
+	return buf.String() == "true", nil
 }
 
-func (gui *Gui) inputPrompt(prompt config.CustomCommandPrompt, promptResponses []string, responseIdx int, wrappedF func() error) error {
-	title, err := gui.resolveTemplate(prompt.Title, promptResponses)
+func parseCondition(condition string) (*template.Template, error) {
+	pipeline := strings.TrimSpace(condition)
+	pipeline = strings.TrimSuffix(strings.TrimPrefix(pipeline, "{{"), "}}")
+
+	tmpl, err := template.New("when").
+		Funcs(utils.TemplateFuncs).
+		Funcs(customCommandConditionFuncs).
+		Parse("{{if " + pipeline + "}}true{{end}}")
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing custom command condition '%s': %v", condition, err)
+	}
+
+	return tmpl, nil
+}
+
+// customCommandApplies tells us whether a custom command's 'when' condition
+// holds right now
+func (gui *Gui) customCommandApplies(customCommand config.CustomCommand) (bool, error) {
+	if customCommand.When == "" {
+		return true, nil
+	}
+
+	return evaluateCondition(customCommand.When, gui.getCustomCommandObjects(newCustomCommandResponses(nil)))
+}
+
+func (gui *Gui) inputPrompt(prompt config.CustomCommandPrompt, responses *customCommandResponses, responseIdx int, wrappedF func() error) error {
+	title, err := gui.resolveTemplate(prompt.Title, responses)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	initialValue, err := gui.resolveTemplate(prompt.InitialValue, responses)
 	if err != nil {
 		return gui.surfaceError(err)
 	}
 
-	initialValue, err := gui.resolveTemplate(prompt.InitialValue, promptResponses)
+	findSuggestionsFunc, err := gui.getCustomCommandSuggestionsFunc(prompt.Suggestions.Preset)
 	if err != nil {
 		return gui.surfaceError(err)
 	}
 
 	return gui.prompt(promptOpts{
-		title:          title,
-		initialContent: initialValue,
+		title:               title,
+		initialContent:      initialValue,
+		findSuggestionsFunc: findSuggestionsFunc,
 		handleConfirm: func(str string) error {
-			promptResponses[responseIdx] = str
+			responses.set(responseIdx, str)
 			return wrappedF()
 		},
 	})
 }
 
-func (gui *Gui) menuPrompt(prompt config.CustomCommandPrompt, promptResponses []string, responseIdx int, wrappedF func() error) error {
-	// need to make a menu here some how
-	menuItems := make([]*menuItem, len(prompt.Options))
-	for i, option := range prompt.Options {
-		option := option
+type resolvedMenuOption struct {
+	name        string
+	description string
+	value       string
+}
 
+func (gui *Gui) resolveMenuOptions(options []config.CustomCommandMenuOption, responses *customCommandResponses) ([]resolvedMenuOption, error) {
+	result := make([]resolvedMenuOption, len(options))
+	for i, option := range options {
 		nameTemplate := option.Name
 		if nameTemplate == "" {
 			// this allows you to only pass values rather than bother with names/descriptions
 			nameTemplate = option.Value
 		}
-		name, err := gui.resolveTemplate(nameTemplate, promptResponses)
+		name, err := gui.resolveTemplate(nameTemplate, responses)
 		if err != nil {
-			return gui.surfaceError(err)
+			return nil, err
 		}
 
-		description, err := gui.resolveTemplate(option.Description, promptResponses)
+		description, err := gui.resolveTemplate(option.Description, responses)
 		if err != nil {
-			return gui.surfaceError(err)
+			return nil, err
 		}
 
-		value, err := gui.resolveTemplate(option.Value, promptResponses)
+		value, err := gui.resolveTemplate(option.Value, responses)
 		if err != nil {
-			return gui.surfaceError(err)
+			return nil, err
 		}
 
+		result[i] = resolvedMenuOption{name: name, description: description, value: value}
+	}
+
+	return result, nil
+}
+
+func (gui *Gui) menuPrompt(prompt config.CustomCommandPrompt, responses *customCommandResponses, responseIdx int, wrappedF func() error) error {
+	options, err := gui.resolveMenuOptions(prompt.Options, responses)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	menuItems := make([]*menuItem, len(options))
+	for i, option := range options {
+		option := option
 		menuItems[i] = &menuItem{
-			displayStrings: []string{name, style.FgYellow.Sprint(description)},
+			displayStrings: []string{option.name, style.FgYellow.Sprint(option.description)},
 			onPress: func() error {
-				promptResponses[responseIdx] = value
+				responses.set(responseIdx, option.value)
 				return wrappedF()
 			},
 		}
 	}
 
-	title, err := gui.resolveTemplate(prompt.Title, promptResponses)
+	title, err := gui.resolveTemplate(prompt.Title, responses)
 	if err != nil {
 		return gui.surfaceError(err)
 	}
@@ -123,6 +390,111 @@ func (gui *Gui) menuPrompt(prompt config.CustomCommandPrompt, promptResponses []
 	return gui.createMenu(title, menuItems, createMenuOptions{showCancel: true})
 }
 
+func (gui *Gui) confirmPrompt(prompt config.CustomCommandPrompt, responses *customCommandResponses, responseIdx int, wrappedF func() error) error {
+	title, err := gui.resolveTemplate(prompt.Title, responses)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	body, err := gui.resolveTemplate(prompt.Body, responses)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	return gui.ask(askOpts{
+		title:  title,
+		prompt: body,
+		handleConfirm: func() error {
+			responses.set(responseIdx, "true")
+			return wrappedF()
+		},
+	})
+}
+
+// multiSelectPrompt shows a menu in which pressing an option toggles whether
+// it's selected, until the user confirms the selection
+func (gui *Gui) multiSelectPrompt(prompt config.CustomCommandPrompt, responses *customCommandResponses, responseIdx int, wrappedF func() error) error {
+	options, err := gui.resolveMenuOptions(prompt.Options, responses)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	title, err := gui.resolveTemplate(prompt.Title, responses)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	separator := prompt.Separator
+	if separator == "" {
+		separator = " "
+	}
+
+	selected := make([]bool, len(options))
+
+	var showMenu func(selectedLineIdx int) error
+	showMenu = func(selectedLineIdx int) error {
+		menuItems := []*menuItem{
+			{
+				displayStrings: []string{style.FgGreen.Sprint(gui.Tr.LcConfirmSelection), ""},
+				onPress: func() error {
+					values := []string{}
+					for i, option := range options {
+						if selected[i] {
+							values = append(values, option.value)
+						}
+					}
+					responses.setList(responseIdx, values, separator)
+					return wrappedF()
+				},
+			},
+		}
+
+		for i, option := range options {
+			i := i
+			checkbox := "[ ]"
+			if selected[i] {
+				checkbox = "[x]"
+			}
+			menuItems = append(menuItems, &menuItem{
+				displayStrings: []string{checkbox + " " + option.name, style.FgYellow.Sprint(option.description)},
+				onPress: func() error {
+					selected[i] = !selected[i]
+					// the confirm item comes first
+					return showMenu(i + 1)
+				},
+			})
+		}
+
+		if err := gui.createMenu(title, menuItems, createMenuOptions{showCancel: true}); err != nil {
+			return err
+		}
+		gui.State.Panels.Menu.SelectedLineIdx = selectedLineIdx
+		gui.State.Contexts.Menu.FocusLine()
+		return nil
+	}
+
+	return showMenu(0)
+}
+
+func (gui *Gui) getCustomCommandSuggestionsFunc(preset string) (func(string) []*types.Suggestion, error) {
+	switch preset {
+	case "":
+		return nil, nil
+	case "branches":
+		return gui.getBranchNameSuggestionsFunc(), nil
+	case "remotes":
+		return gui.getRemoteSuggestionsFunc(), nil
+	case "tags":
+		return gui.getTagsSuggestionsFunc(), nil
+	case "files":
+		return gui.getFilePathSuggestionsFunc(), nil
+	case "authors":
+		return gui.getAuthorsSuggestionsFunc(), nil
+	default:
+		return nil, fmt.Errorf("Unknown suggestions preset '%s' for custom command prompt. Permitted presets: branches, remotes, tags, files, authors", preset)
+	}
+}
+
 func (gui *Gui) GenerateMenuCandidates(commandOutput, filter, valueFormat, labelFormat string) ([]commandMenuEntry, error) {
 	reg, err := regexp.Compile(filter)
 	if err != nil {
@@ -189,15 +561,15 @@ func (gui *Gui) GenerateMenuCandidates(commandOutput, filter, valueFormat, label
 	return candidates, err
 }
 
-func (gui *Gui) menuPromptFromCommand(prompt config.CustomCommandPrompt, promptResponses []string, responseIdx int, wrappedF func() error) error {
+func (gui *Gui) menuPromptFromCommand(prompt config.CustomCommandPrompt, responses *customCommandResponses, responseIdx int, wrappedF func() error) error {
 	// Collect cmd to run from config
-	cmdStr, err := gui.resolveTemplate(prompt.Command, promptResponses)
+	cmdStr, err := gui.resolveShellTemplate(prompt.Command, responses)
 	if err != nil {
 		return gui.surfaceError(err)
 	}
 
 	// Collect Filter regexp
-	filter, err := gui.resolveTemplate(prompt.Filter, promptResponses)
+	filter, err := gui.resolveTemplate(prompt.Filter, responses)
 	if err != nil {
 		return gui.surfaceError(err)
 	}
@@ -220,13 +592,13 @@ func (gui *Gui) menuPromptFromCommand(prompt config.CustomCommandPrompt, promptR
 		menuItems[i] = &menuItem{
 			displayStrings: []string{candidates[i].label},
 			onPress: func() error {
-				promptResponses[responseIdx] = candidates[i].value
+				responses.set(responseIdx, candidates[i].value)
 				return wrappedF()
 			},
 		}
 	}
 
-	title, err := gui.resolveTemplate(prompt.Title, promptResponses)
+	title, err := gui.resolveTemplate(prompt.Title, responses)
 	if err != nil {
 		return gui.surfaceError(err)
 	}
@@ -236,10 +608,20 @@ func (gui *Gui) menuPromptFromCommand(prompt config.CustomCommandPrompt, promptR
 
 func (gui *Gui) handleCustomCommandKeybinding(customCommand config.CustomCommand) func() error {
 	return func() error {
-		promptResponses := make([]string, len(customCommand.Prompts))
+		outputMode, err := getCustomCommandOutputMode(customCommand)
+		if err != nil {
+			return gui.createErrorPanel(err.Error())
+		}
+
+		refreshScopes, err := getScopesFromNames(customCommand.After.Refresh)
+		if err != nil {
+			return gui.createErrorPanel(err.Error())
+		}
+
+		responses := newCustomCommandResponses(customCommand.Prompts)
 
 		f := func() error {
-			cmdStr, err := gui.resolveTemplate(customCommand.Command, promptResponses)
+			cmdStr, err := gui.resolveShellTemplate(customCommand.Command, responses)
 			if err != nil {
 				return gui.surfaceError(err)
 			}
@@ -255,14 +637,19 @@ func (gui *Gui) handleCustomCommandKeybinding(customCommand config.CustomCommand
 			return gui.WithWaitingStatus(loadingText, func() error {
 				gui.logAction(gui.Tr.Actions.CustomCommand)
 				cmdObj := gui.OSCommand.Cmd.NewShell(cmdStr)
-				if customCommand.Stream {
+				if outputMode == "log" {
 					cmdObj.StreamOutput()
 				}
-				err := cmdObj.Run()
+				var output string
+				if outputMode == "mainPanel" {
+					output, err = gui.streamCustomCommandOutput(cmdObj, customCommandTitle(customCommand))
+				} else {
+					output, err = cmdObj.RunWithOutput()
+				}
 				if err != nil {
 					return gui.surfaceError(err)
 				}
-				return gui.refreshSidePanels(refreshOptions{})
+				return gui.afterCustomCommand(customCommand, outputMode, refreshScopes, output)
 			})
 		}
 
@@ -280,18 +667,26 @@ func (gui *Gui) handleCustomCommandKeybinding(customCommand config.CustomCommand
 			switch prompt.Type {
 			case "input":
 				f = func() error {
-					return gui.inputPrompt(prompt, promptResponses, idx, wrappedF)
+					return gui.inputPrompt(prompt, responses, idx, wrappedF)
+				}
+			case "confirm":
+				f = func() error {
+					return gui.confirmPrompt(prompt, responses, idx, wrappedF)
 				}
 			case "menu":
 				f = func() error {
-					return gui.menuPrompt(prompt, promptResponses, idx, wrappedF)
+					return gui.menuPrompt(prompt, responses, idx, wrappedF)
+				}
+			case "multiSelect":
+				f = func() error {
+					return gui.multiSelectPrompt(prompt, responses, idx, wrappedF)
 				}
 			case "menuFromCommand":
 				f = func() error {
-					return gui.menuPromptFromCommand(prompt, promptResponses, idx, wrappedF)
+					return gui.menuPromptFromCommand(prompt, responses, idx, wrappedF)
 				}
 			default:
-				return gui.createErrorPanel("custom command prompt must have a type of 'input', 'menu' or 'menuFromCommand'")
+				return gui.createErrorPanel("custom command prompt must have a type of 'input', 'confirm', 'menu', 'multiSelect' or 'menuFromCommand'")
 			}
 
 		}
@@ -300,48 +695,458 @@ func (gui *Gui) handleCustomCommandKeybinding(customCommand config.CustomCommand
 	}
 }
 
-func (gui *Gui) GetCustomCommandKeybindings() []*Binding {
-	bindings := []*Binding{}
-	customCommands := gui.UserConfig.CustomCommands
+func getCustomCommandOutputMode(customCommand config.CustomCommand) (string, error) {
+	switch customCommand.Output {
+	case "":
+		if customCommand.Stream {
+			return "log", nil
+		}
+		return "none", nil
+	case "none", "popup", "mainPanel", "log":
+		return customCommand.Output, nil
+	default:
+		return "", fmt.Errorf("custom command output must be one of 'none', 'popup', 'mainPanel' or 'log'. Key: %s, Command: %s", customCommand.Key, customCommand.Command)
+	}
+}
 
-	for _, customCommand := range customCommands {
-		var viewName string
-		var contexts []string
-		switch customCommand.Context {
-		case "global":
-			viewName = ""
-		case "":
-			log.Fatalf("Error parsing custom command keybindings: context not provided (use context: 'global' for the global context). Key: %s, Command: %s", customCommand.Key, customCommand.Command)
-		default:
-			context, ok := gui.contextForContextKey(ContextKey(customCommand.Context))
+// afterCustomCommand refreshes what the command may have changed, runs its
+// after hook, and then shows its output. The output goes last so that
+// refreshing doesn't render over it in the main panel.
+func (gui *Gui) afterCustomCommand(customCommand config.CustomCommand, outputMode string, refreshScopes []RefreshableView, output string) error {
+	if err := gui.refreshSidePanels(refreshOptions{scope: refreshScopes, mode: SYNC}); err != nil {
+		return err
+	}
+
+	if customCommand.After.SelectRef != "" {
+		ref, err := findRefInOutput(customCommand.After.SelectRef, output)
+		if err != nil {
+			return gui.surfaceError(err)
+		}
+		if ref != "" {
+			gui.OnUIThread(func() error { return gui.selectRef(ref) })
+		}
+	}
+
+	if customCommand.After.CopyOutput {
+		if err := gui.OSCommand.CopyToClipboard(output); err != nil {
+			return gui.surfaceError(err)
+		}
+		gui.raiseToast(gui.Tr.CustomCommandOutputCopied)
+	}
+
+	title := customCommandTitle(customCommand)
+	switch outputMode {
+	case "popup":
+		return gui.ask(askOpts{
+			title:  title,
+			prompt: strings.TrimSpace(output),
+		})
+	case "mainPanel":
+		// we've already shown the output as it came in, but refreshing may have
+		// rendered something else in the main panel since
+		return gui.refreshMainViews(refreshMainOpts{
+			main: &viewUpdateOpts{
+				title: title,
+				task:  NewRenderStringTask(output),
+			},
+		})
+	}
+
+	return nil
+}
+
+func customCommandTitle(customCommand config.CustomCommand) string {
+	if customCommand.Description != "" {
+		return customCommand.Description
+	}
+	return customCommand.Command
+}
+
+// streamCustomCommandOutput runs a custom command, showing its output in the
+// main panel as it comes in, and returns the whole of the output once the
+// command has finished. Unlike the commands we run in the main panel ourselves,
+// a custom command may be changing things, so we don't stop it if something
+// else is shown in the main panel in the meantime; we stop showing its output.
+func (gui *Gui) streamCustomCommandOutput(cmdObj oscommands.ICmdObj, title string) (string, error) {
+	cmd := cmdObj.GetCmd()
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	cmd.Stderr = cmd.Stdout
+
+	gui.OSCommand.LogCommand(cmdObj.ToString(), true)
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	// the key keeps the scroll position while the output grows, and tells us
+	// whether it's still the output that's showing
+	key := fmt.Sprintf("custom command %d", time.Now().UnixNano())
+	manager := gui.getManager(gui.Views.Main)
+	output := &strings.Builder{}
+	shown := false
+	lastRendered := time.Time{}
+	render := func() {
+		if manager.GetTaskKey() == key {
+			shown = true
+		} else if shown {
+			return
+		}
+
+		lastRendered = time.Now()
+		_ = gui.refreshMainViews(refreshMainOpts{
+			main: &viewUpdateOpts{
+				title: title,
+				task:  NewRenderStringTaskWithKey(output.String(), key),
+			},
+		})
+	}
+
+	render()
+	reader := bufio.NewReader(stdoutPipe)
+	for {
+		line, readErr := reader.ReadString('\n')
+		output.WriteString(line)
+		if readErr != nil {
+			break
+		}
+		if time.Since(lastRendered) > time.Millisecond*100 {
+			render()
+		}
+	}
+	render()
+
+	return output.String(), cmd.Wait()
+}
+
+// findRefInOutput returns the first ref in a custom command's output that the
+// regexp matches, or an empty string if there isn't one
+func findRefInOutput(pattern string, output string) (string, error) {
+	reg, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", errors.New("unable to parse selectRef regex, error: " + err.Error())
+	}
+
+	match := reg.FindStringSubmatch(output)
+	if match == nil {
+		return "", nil
+	}
+
+	for groupIdx, group := range reg.SubexpNames() {
+		if group == "ref" {
+			return match[groupIdx], nil
+		}
+	}
+	return match[0], nil
+}
+
+// selectRef selects the branch, tag or commit that the ref names, in that
+// order of preference
+func (gui *Gui) selectRef(ref string) error {
+	for i, branch := range gui.State.Branches {
+		if branch.Name == ref {
+			gui.State.Panels.Branches.SelectedLineIdx = i
+			return gui.pushContext(gui.State.Contexts.Branches)
+		}
+	}
+
+	for i, tag := range gui.State.Tags {
+		if tag.Name == ref {
+			gui.State.Panels.Tags.SelectedLineIdx = i
+			return gui.pushContext(gui.State.Contexts.Tags)
+		}
+	}
+
+	for i, commit := range gui.State.Commits {
+		if strings.HasPrefix(commit.Sha, ref) {
+			gui.State.Panels.Commits.SelectedLineIdx = i
+			return gui.pushContext(gui.State.Contexts.BranchCommits)
+		}
+	}
+
+	gui.Log.Warnf("custom command output named ref '%s', which we couldn't find", ref)
+	return nil
+}
+
+// customCommandScope is a view that a custom command's keybinding is registered
+// against, along with the contexts within that view
+type customCommandScope struct {
+	viewName string
+	contexts []string
+}
+
+// getCustomCommandScopes returns the views and contexts that a custom command's
+// keybinding should be registered against. A command can name several
+// contexts, separated by commas, which may belong to different views.
+func (gui *Gui) getCustomCommandScopes(customCommand config.CustomCommand) ([]customCommandScope, error) {
+	contextKeys := []string{}
+	for _, contextKey := range strings.Split(string(customCommand.Context), ",") {
+		if contextKey = strings.TrimSpace(contextKey); contextKey != "" {
+			contextKeys = append(contextKeys, contextKey)
+		}
+	}
+
+	if len(contextKeys) == 0 {
+		return nil, fmt.Errorf("Error parsing custom command keybindings: context not provided (use context: 'global' for the global context). Key: %s, Command: %s", customCommand.Key, customCommand.Command)
+	}
+
+	if utils.IncludesString(contextKeys, "global") {
+		if len(contextKeys) > 1 {
+			return nil, fmt.Errorf("Error parsing custom command keybindings: the global context can't be combined with other contexts. Key: %s, Command: %s", customCommand.Key, customCommand.Command)
+		}
+		return []customCommandScope{{viewName: ""}}, nil
+	}
+
+	scopes := []customCommandScope{}
+outer:
+	for _, contextKey := range contextKeys {
+		context, ok := gui.contextForContextKey(ContextKey(contextKey))
+		if !ok {
 			// stupid golang making me build an array of strings for this.
 			allContextKeyStrings := make([]string, len(allContextKeys))
 			for i := range allContextKeys {
 				allContextKeyStrings[i] = string(allContextKeys[i])
 			}
-			if !ok {
-				log.Fatalf("Error when setting custom command keybindings: unknown context: %s. Key: %s, Command: %s.\nPermitted contexts: %s", customCommand.Context, customCommand.Key, customCommand.Command, strings.Join(allContextKeyStrings, ", "))
+			return nil, fmt.Errorf("Error when setting custom command keybindings: unknown context: %s. Key: %s, Command: %s.\nPermitted contexts: %s", contextKey, customCommand.Key, customCommand.Command, strings.Join(allContextKeyStrings, ", "))
+		}
+
+		// here we assume that a given context will always belong to the same view.
+		// Currently this is a safe bet but it's by no means guaranteed in the long term
+		// and we might need to make some changes in the future to support it.
+		viewName := context.GetViewName()
+		for i := range scopes {
+			if scopes[i].viewName == viewName {
+				scopes[i].contexts = append(scopes[i].contexts, contextKey)
+				continue outer
 			}
-			// here we assume that a given context will always belong to the same view.
-			// Currently this is a safe bet but it's by no means guaranteed in the long term
-			// and we might need to make some changes in the future to support it.
-			viewName = context.GetViewName()
-			contexts = []string{customCommand.Context}
 		}
+		scopes = append(scopes, customCommandScope{viewName: viewName, contexts: []string{contextKey}})
+	}
 
-		description := customCommand.Description
-		if description == "" {
-			description = customCommand.Command
+	return scopes, nil
+}
+
+// validateCustomCommand checks the things about a custom command which would
+// otherwise make us exit when we set up its keybinding
+func (gui *Gui) validateCustomCommand(customCommand config.CustomCommand, leader string, inCommandMenu bool) error {
+	if !inCommandMenu {
+		if _, err := gui.getCustomCommandScopes(customCommand); err != nil {
+			return err
 		}
+	}
 
-		bindings = append(bindings, &Binding{
-			ViewName:    viewName,
-			Contexts:    contexts,
-			Key:         gui.getKey(customCommand.Key),
-			Modifier:    gocui.ModNone,
-			Handler:     gui.handleCustomCommandKeybinding(customCommand),
-			Description: description,
-		})
+	// a command doesn't need a key if it's in a command menu, or if it's only
+	// to be run from the keybindings menu
+	if customCommand.Key != "" {
+		key, err := keyFromString(customCommand.Key, leader)
+		if err != nil {
+			return err
+		}
+		// we only bind single keys within a menu
+		if _, ok := key.(keySequence); ok && inCommandMenu {
+			return fmt.Errorf("Error parsing custom command keybindings: a command in a command menu can't have a key sequence. Key: %s, Command: %s", customCommand.Key, customCommand.Command)
+		}
+	}
+
+	if err := gui.validateCustomCommandTemplates(customCommand); err != nil {
+		return fmt.Errorf("Error parsing custom command templates: %v. Key: %s, Command: %s", err, customCommand.Key, customCommand.Command)
+	}
+
+	outputMode, err := getCustomCommandOutputMode(customCommand)
+	if err != nil {
+		return err
+	}
+	// we don't capture the output of a command we stream to the command log or
+	// run as a subprocess, so these would quietly get nothing
+	if (outputMode == "log" || customCommand.Subprocess) && (customCommand.After.SelectRef != "" || customCommand.After.CopyOutput) {
+		return fmt.Errorf("custom command after.selectRef and after.copyOutput need the command's output, so they can't be used with output 'log' or subprocess. Key: %s, Command: %s", customCommand.Key, customCommand.Command)
+	}
+
+	for _, subCommand := range customCommand.CommandMenu {
+		if err := gui.validateCustomCommand(subCommand, leader, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateCustomCommandTemplates parses a custom command's templates, so that
+// we find out about syntax errors before the user presses the key
+func (gui *Gui) validateCustomCommandTemplates(customCommand config.CustomCommand) error {
+	funcs := gui.customCommandTemplateFuncs()
+
+	shellTemplates := []string{customCommand.Command}
+	templates := []string{}
+	for _, prompt := range customCommand.Prompts {
+		shellTemplates = append(shellTemplates, prompt.Command)
+		templates = append(templates, prompt.Title, prompt.InitialValue, prompt.Body, prompt.Filter)
+		for _, option := range prompt.Options {
+			templates = append(templates, option.Name, option.Description, option.Value)
+		}
+	}
+
+	for _, templateStr := range shellTemplates {
+		if _, err := utils.ParseShellTemplate(templateStr, funcs); err != nil {
+			return err
+		}
+	}
+
+	for _, templateStr := range templates {
+		if _, err := utils.ParseTemplate(templateStr, funcs); err != nil {
+			return err
+		}
+	}
+
+	if customCommand.When != "" {
+		if _, err := parseCondition(customCommand.When); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getApplicableCustomCommands leaves out the custom commands whose 'when'
+// condition doesn't hold. We keep the ones whose condition is broken so that
+// the user finds out about it.
+func (gui *Gui) getApplicableCustomCommands(customCommands []config.CustomCommand) []config.CustomCommand {
+	result := []config.CustomCommand{}
+	for _, customCommand := range customCommands {
+		if applies, err := gui.customCommandApplies(customCommand); err == nil && !applies {
+			continue
+		}
+		result = append(result, customCommand)
+	}
+	return result
+}
+
+// getApplicableCustomCommandKeybindings is for the keybindings menu, so unlike
+// getCustomCommandKeybindings it includes the commands which don't have a key:
+// the menu is the only way to run them.
+func (gui *Gui) getApplicableCustomCommandKeybindings() []*Binding {
+	return gui.customCommandBindings(gui.getApplicableCustomCommands(gui.UserConfig.CustomCommands), true)
+}
+
+// handleKeyWithoutCustomCommands runs the inbuilt keybinding which a custom
+// command's keybinding hides, for when the custom command doesn't apply
+func (gui *Gui) handleKeyWithoutCustomCommands(key interface{}) error {
+	view := gui.g.CurrentView()
+
+	var globalBinding *Binding
+	for _, binding := range gui.GetInitialKeybindings() {
+		if !reflect.DeepEqual(binding.Key, key) || binding.Modifier != gocui.ModNone {
+			continue
+		}
+
+		if binding.ViewName == "" {
+			if globalBinding == nil {
+				globalBinding = binding
+			}
+			continue
+		}
+
+		if view != nil && binding.ViewName == view.Name() && (len(binding.Contexts) == 0 || utils.IncludesString(binding.Contexts, view.Context)) {
+			return binding.Handler()
+		}
+	}
+
+	if globalBinding != nil {
+		return globalBinding.Handler()
+	}
+
+	return nil
+}
+
+// runCustomCommand runs a custom command, or if it has a command menu, opens
+// that
+func (gui *Gui) runCustomCommand(customCommand config.CustomCommand) func() error {
+	if len(customCommand.CommandMenu) == 0 {
+		return gui.handleCustomCommandKeybinding(customCommand)
+	}
+
+	return func() error {
+		customCommands := gui.getApplicableCustomCommands(customCommand.CommandMenu)
+
+		menuItems := make([]*menuItem, len(customCommands))
+		for i, subCommand := range customCommands {
+			subCommand := subCommand
+			var key interface{}
+			keyDisplay := ""
+			if subCommand.Key != "" {
+				key = gui.getKey(subCommand.Key)
+				keyDisplay = GetKeyDisplay(key)
+			}
+			description := customCommandTitle(subCommand)
+			if len(subCommand.CommandMenu) > 0 {
+				description = opensMenuStyle(description)
+			}
+
+			menuItems[i] = &menuItem{
+				displayStrings: []string{keyDisplay, description},
+				key:            key,
+				onPress: func() error {
+					applies, err := gui.customCommandApplies(subCommand)
+					if err != nil {
+						return gui.createErrorPanel(err.Error())
+					}
+					if !applies {
+						return nil
+					}
+					return gui.runCustomCommand(subCommand)()
+				},
+			}
+		}
+
+		return gui.createMenu(customCommandTitle(customCommand), menuItems, createMenuOptions{showCancel: true})
+	}
+}
+
+func (gui *Gui) getCustomCommandKeybindings(customCommands []config.CustomCommand) []*Binding {
+	return gui.customCommandBindings(customCommands, false)
+}
+
+func (gui *Gui) customCommandBindings(customCommands []config.CustomCommand, includeKeyless bool) []*Binding {
+	bindings := []*Binding{}
+
+	for _, customCommand := range customCommands {
+		customCommand := customCommand
+		if customCommand.Key == "" && !includeKeyless {
+			continue
+		}
+
+		scopes, err := gui.getCustomCommandScopes(customCommand)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var key interface{}
+		if customCommand.Key != "" {
+			key = gui.getKey(customCommand.Key)
+		}
+		handler := gui.runCustomCommand(customCommand)
+
+		for _, scope := range scopes {
+			bindings = append(bindings, &Binding{
+				ViewName: scope.viewName,
+				Contexts: scope.contexts,
+				Key:      key,
+				Modifier: gocui.ModNone,
+				Handler: func() error {
+					applies, err := gui.customCommandApplies(customCommand)
+					if err != nil {
+						return gui.createErrorPanel(err.Error())
+					}
+					if !applies {
+						return gui.handleKeyWithoutCustomCommands(key)
+					}
+					return handler()
+				},
+				Description: customCommandTitle(customCommand),
+				OpensMenu:   len(customCommand.CommandMenu) > 0,
+			})
+		}
 	}
 
 	return bindings
@@ -3,6 +3,9 @@ package gui
 import (
 	"testing"
 
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/jesseduffield/lazygit/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -62,3 +65,417 @@ func TestGuiGenerateMenuCandidates(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomCommandResponsesForm(t *testing.T) {
+	responses := newCustomCommandResponses([]config.CustomCommandPrompt{
+		{Type: "input", Key: "Branch"},
+		{Type: "menu"},
+		{Type: "confirm", Key: "Force"},
+		{Type: "multiSelect", Key: "Remotes"},
+	})
+	responses.set(0, "feature")
+	responses.set(1, "hotfix")
+	responses.setList(3, []string{"origin", "upstream"}, ",")
+
+	assert.EqualValues(t, []string{"feature", "hotfix", "", "origin,upstream"}, responses.values)
+	assert.EqualValues(t, map[string]interface{}{
+		"Branch":  "feature",
+		"Force":   "",
+		"Remotes": utils.StringList{"origin", "upstream"},
+	}, responses.form())
+}
+
+func TestCustomCommandMultiSelectResponseInCommand(t *testing.T) {
+	responses := newCustomCommandResponses([]config.CustomCommandPrompt{
+		{Type: "input", Key: "Remote"},
+		{Type: "multiSelect", Key: "Branches"},
+	})
+	responses.set(0, "origin")
+	responses.setList(1, []string{"staging", "my branch"}, " ")
+
+	objects := CustomCommandObjects{Form: responses.form()}
+	quote := func(str string) string { return "'" + str + "'" }
+
+	// the example from docs/Custom_Command_Keybindings.md
+	result, err := utils.ResolveShellTemplate("git push --delete {{.Form.Remote}} {{.Form.Branches}}", objects, nil, quote)
+	assert.NoError(t, err)
+	assert.Equal(t, "git push --delete 'origin' 'staging' 'my branch'", result)
+
+	result, err = utils.ResolveTemplateWithFuncs("Deleting {{.Form.Branches}}", objects, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Deleting staging my branch", result)
+}
+
+func TestGetCustomCommandSuggestionsFunc(t *testing.T) {
+	gui := NewDummyGui()
+
+	findSuggestions, err := gui.getCustomCommandSuggestionsFunc("")
+	assert.NoError(t, err)
+	assert.Nil(t, findSuggestions)
+
+	findSuggestions, err = gui.getCustomCommandSuggestionsFunc("remotes")
+	assert.NoError(t, err)
+	assert.NotNil(t, findSuggestions)
+
+	_, err = gui.getCustomCommandSuggestionsFunc("colours")
+	assert.Error(t, err)
+}
+
+func TestGetCustomCommandOutputMode(t *testing.T) {
+	type scenario struct {
+		testName      string
+		customCommand config.CustomCommand
+		expected      string
+		expectErr     bool
+	}
+
+	scenarios := []scenario{
+		{"defaults to none", config.CustomCommand{}, "none", false},
+		{"defaults to log when streaming", config.CustomCommand{Stream: true}, "log", false},
+		{"explicit mode", config.CustomCommand{Output: "mainPanel", Stream: true}, "mainPanel", false},
+		{"unknown mode", config.CustomCommand{Output: "terminal"}, "", true},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			mode, err := getCustomCommandOutputMode(s.customCommand)
+			if s.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.EqualValues(t, s.expected, mode)
+		})
+	}
+}
+
+func TestValidateCustomCommandNeedsOutputForAfterHooks(t *testing.T) {
+	type scenario struct {
+		testName      string
+		customCommand config.CustomCommand
+		expectErr     bool
+	}
+
+	scenarios := []scenario{
+		{"copying output from a popup", config.CustomCommand{Command: "echo", Output: "popup", After: config.CustomCommandAfterHook{CopyOutput: true}}, false},
+		{"copying streamed output", config.CustomCommand{Command: "echo", Stream: true, After: config.CustomCommandAfterHook{CopyOutput: true}}, true},
+		{"selecting a ref from the command log", config.CustomCommand{Command: "echo", Output: "log", After: config.CustomCommandAfterHook{SelectRef: "abc"}}, true},
+		{"selecting a ref from a subprocess", config.CustomCommand{Command: "echo", Subprocess: true, After: config.CustomCommandAfterHook{SelectRef: "abc"}}, true},
+		{"streaming without after hooks", config.CustomCommand{Command: "echo", Output: "log"}, false},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			err := NewDummyGui().validateCustomCommand(s.customCommand, "", true)
+			if s.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFindRefInOutput(t *testing.T) {
+	type scenario struct {
+		testName string
+		pattern  string
+		output   string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{"whole match", `feature/\S+`, "created feature/login from master\n", "feature/login"},
+		{"named group", `Switched to a new branch '(?P<ref>[^']+)'`, "Switched to a new branch 'hotfix'\n", "hotfix"},
+		{"no match", `[0-9a-f]{40}`, "nothing to see here\n", ""},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			ref, err := findRefInOutput(s.pattern, s.output)
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, ref)
+		})
+	}
+
+	_, err := findRefInOutput("(", "")
+	assert.Error(t, err)
+}
+
+func TestGetScopesFromNames(t *testing.T) {
+	scopes, err := getScopesFromNames([]string{"files", "branches"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []RefreshableView{FILES, BRANCHES}, scopes)
+	assert.EqualValues(t, []string{"files", "branches"}, getScopeNames(scopes))
+
+	_, err = getScopesFromNames([]string{"files", "everything"})
+	assert.Error(t, err)
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	type scenario struct {
+		testName  string
+		condition string
+		objects   CustomCommandObjects
+		expected  bool
+		expectErr bool
+	}
+
+	trackingBranch := &models.Branch{Name: "feature", UpstreamRemote: "origin", UpstreamBranch: "feature"}
+	localBranch := &models.Branch{Name: "scratch"}
+	sqlFile := &models.File{Name: "migrations/001_init.sql"}
+
+	scenarios := []scenario{
+		{
+			testName:  "field is set",
+			condition: ".CheckedOutBranch.UpstreamBranch",
+			objects:   CustomCommandObjects{CheckedOutBranch: trackingBranch},
+			expected:  true,
+		},
+		{
+			testName:  "field is empty",
+			condition: ".CheckedOutBranch.UpstreamBranch",
+			objects:   CustomCommandObjects{CheckedOutBranch: localBranch},
+			expected:  false,
+		},
+		{
+			testName:  "wrapped in braces",
+			condition: "{{ .CheckedOutBranch.UpstreamBranch }}",
+			objects:   CustomCommandObjects{CheckedOutBranch: trackingBranch},
+			expected:  true,
+		},
+		{
+			testName:  "glob",
+			condition: `glob "migrations/*.sql" .SelectedFile.Name`,
+			objects:   CustomCommandObjects{SelectedFile: sqlFile},
+			expected:  true,
+		},
+		{
+			testName:  "matches",
+			condition: `matches "\\.go$" .SelectedFile.Name`,
+			objects:   CustomCommandObjects{SelectedFile: sqlFile},
+			expected:  false,
+		},
+		{
+			testName:  "nothing selected",
+			condition: `glob "*.sql" .SelectedFile.Name`,
+			objects:   CustomCommandObjects{},
+			expected:  false,
+		},
+		{
+			testName:  "syntax error",
+			condition: `glob "*.sql" (.SelectedFile.Name`,
+			objects:   CustomCommandObjects{SelectedFile: sqlFile},
+			expectErr: true,
+		},
+		{
+			testName:  "unknown field",
+			condition: ".SelectedFile.Colour",
+			objects:   CustomCommandObjects{SelectedFile: sqlFile},
+			expectErr: true,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			applies, err := evaluateCondition(s.condition, s.objects)
+			if s.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, s.expected, applies)
+		})
+	}
+}
+
+func TestGetCustomCommandScopes(t *testing.T) {
+	scenarios := []struct {
+		testName  string
+		context   string
+		expected  []customCommandScope
+		expectErr bool
+	}{
+		{
+			testName: "global",
+			context:  "global",
+			expected: []customCommandScope{{viewName: ""}},
+		},
+		{
+			testName: "single context",
+			context:  "files",
+			expected: []customCommandScope{{viewName: "files", contexts: []string{"files"}}},
+		},
+		{
+			testName: "contexts in different views",
+			context:  "files, commits",
+			expected: []customCommandScope{
+				{viewName: "files", contexts: []string{"files"}},
+				{viewName: "commits", contexts: []string{"commits"}},
+			},
+		},
+		{
+			testName: "contexts sharing a view",
+			context:  "localBranches,remotes,commits",
+			expected: []customCommandScope{
+				{viewName: "branches", contexts: []string{"localBranches", "remotes"}},
+				{viewName: "commits", contexts: []string{"commits"}},
+			},
+		},
+		{
+			testName:  "no context",
+			context:   " , ",
+			expectErr: true,
+		},
+		{
+			testName:  "global with another context",
+			context:   "global, files",
+			expectErr: true,
+		},
+		{
+			testName:  "unknown context",
+			context:   "files, nope",
+			expectErr: true,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			scopes, err := NewDummyGui().getCustomCommandScopes(config.CustomCommand{Key: "X", Context: config.CustomCommandContext(s.context)})
+			if s.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, scopes)
+		})
+	}
+}
+
+func TestCustomCommandBindings(t *testing.T) {
+	gui := NewDummyGui()
+	customCommands := []config.CustomCommand{
+		{Key: "X", Context: "files, commits", Command: "echo x"},
+		{Context: "files", Command: "echo keyless", Description: "keyless"},
+		{Key: "M", Context: "global", Description: "my menu", CommandMenu: []config.CustomCommand{{Command: "echo y"}}},
+	}
+
+	keyed := gui.getCustomCommandKeybindings(customCommands)
+	assert.Len(t, keyed, 3)
+	for _, binding := range keyed {
+		assert.NotNil(t, binding.Key)
+	}
+	assert.Equal(t, "echo x", keyed[0].Description)
+	assert.Equal(t, "files", keyed[0].ViewName)
+	assert.Equal(t, "commits", keyed[1].ViewName)
+	assert.True(t, keyed[2].OpensMenu)
+
+	all := gui.customCommandBindings(customCommands, true)
+	assert.Len(t, all, 4)
+	assert.Nil(t, all[2].Key)
+	assert.Equal(t, "keyless", all[2].Description)
+	assert.NotNil(t, all[2].Handler)
+}
+
+func TestCustomCommandTemplateFuncs(t *testing.T) {
+	objects := CustomCommandObjects{
+		RepoRoot:          "/home/me/repo",
+		SelectedFile:      &models.File{Name: "dir/my file.go"},
+		SelectedLineRange: &LineRange{Start: 3, End: 7},
+		StagedFiles:       []string{"a.go", "b.go"},
+	}
+
+	scenarios := []struct {
+		testName string
+		template string
+		expected string
+	}{
+		{
+			testName: "quote",
+			template: "git add -- {{quote .SelectedFile.Name}}",
+			expected: `git add -- "dir/my file.go"`,
+		},
+		{
+			testName: "quote something dangerous",
+			template: "echo {{quote `$(rm -rf ~)`}}",
+			expected: `echo "\$(rm -rf ~)"`,
+		},
+		{
+			testName: "path functions",
+			template: "{{base .SelectedFile.Name}} {{dir .SelectedFile.Name}} {{ext .SelectedFile.Name}} {{joinPath .RepoRoot .SelectedFile.Name}}",
+			expected: "my file.go dir .go /home/me/repo/dir/my file.go",
+		},
+		{
+			testName: "line range",
+			template: "vim +{{.SelectedLineRange.Start}} {{.SelectedLineRange.End}}",
+			expected: "vim +3 7",
+		},
+		{
+			testName: "staged files",
+			template: "{{range .StagedFiles}}{{quote .}} {{end}}",
+			expected: `"a.go" "b.go" `,
+		},
+	}
+
+	funcs := NewDummyGui().customCommandTemplateFuncs()
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			result, err := utils.ResolveTemplateWithFuncs(s.template, objects, funcs)
+			assert.NoError(t, err)
+			assert.Equal(t, s.expected, result)
+		})
+	}
+}
+
+func TestValidateCustomCommandTemplates(t *testing.T) {
+	scenarios := []struct {
+		testName      string
+		customCommand config.CustomCommand
+		expectErr     bool
+	}{
+		{
+			testName:      "valid",
+			customCommand: config.CustomCommand{Command: `git commit -m "{{.Form.Message | default "wip"}}"`, When: ".SelectedFile"},
+		},
+		{
+			testName:      "syntax error in the command",
+			customCommand: config.CustomCommand{Command: "git checkout {{.SelectedLocalBranch.Name"},
+			expectErr:     true,
+		},
+		{
+			testName:      "unknown function",
+			customCommand: config.CustomCommand{Command: "git checkout {{nope .SelectedLocalBranch.Name}}"},
+			expectErr:     true,
+		},
+		{
+			testName: "syntax error in a prompt",
+			customCommand: config.CustomCommand{Command: "echo", Prompts: []config.CustomCommandPrompt{
+				{Type: "menu", Options: []config.CustomCommandMenuOption{{Value: "{{if}}"}}},
+			}},
+			expectErr: true,
+		},
+		{
+			testName:      "syntax error in the condition",
+			customCommand: config.CustomCommand{Command: "echo", When: "(.SelectedFile"},
+			expectErr:     true,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			err := NewDummyGui().validateCustomCommandTemplates(s.customCommand)
+			if s.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package gui
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/jesseduffield/lazygit/pkg/commands/patch"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// the staging and patch building contexts render their own diffs so they
+// aren't included here
+var CONTEXT_KEYS_SHOWING_SPLITTABLE_DIFFS = []ContextKey{
+	FILES_CONTEXT_KEY,
+	COMMIT_FILES_CONTEXT_KEY,
+	STASH_CONTEXT_KEY,
+	BRANCH_COMMITS_CONTEXT_KEY,
+	SUB_COMMITS_CONTEXT_KEY,
+	REFLOG_COMMITS_CONTEXT_KEY,
+	MAIN_NORMAL_CONTEXT_KEY,
+}
+
+// newDiffTask returns the task for showing the diff produced by the given
+// command. We only render the diff ourselves if the user has asked for it to be
+// highlighted and hasn't got a pager for that, or if they've asked for a
+// side-by-side diff. Otherwise we stream it, so that big diffs show up straight
+// away.
+func (gui *Gui) newDiffTask(cmdObj oscommands.ICmdObj) updateTask {
+	if gui.State.SplitDiffView {
+		return NewRunDiffTask(cmdObj.GetCmd(), true)
+	}
+
+	pagingConfig := gui.UserConfig.Git.Paging
+	if pagingConfig.Pager == "" && !pagingConfig.UseConfig && gui.highlightingDiffs() {
+		return NewRunDiffTask(cmdObj.GetCmd(), false)
+	}
+
+	return NewRunPtyTask(cmdObj.GetCmd())
+}
+
+// newUntrackedFileTask is like newDiffTask, but seeing as the diff of an
+// untracked file is just the file's content, we'll syntax highlight it
+// ourselves rather than leave it to a pager
+func (gui *Gui) newUntrackedFileTask(cmdObj oscommands.ICmdObj) updateTask {
+	if gui.UserConfig.Gui.SyntaxHighlighting && !gui.State.SplitDiffView {
+		return NewRunDiffTask(cmdObj.GetCmd(), false)
+	}
+
+	return gui.newDiffTask(cmdObj)
+}
+
+func (gui *Gui) highlightingDiffs() bool {
+	return gui.UserConfig.Git.DiffHighlight != patch.INTRA_LINE_HIGHLIGHT_NONE || gui.UserConfig.Gui.SyntaxHighlighting
+}
+
+// patchParserOpts returns the options for parsing a diff which is to be shown
+// in the given view
+func (gui *Gui) patchParserOpts(viewName string) patch.PatchParserOpts {
+	return patch.PatchParserOpts{
+		IntraLineHighlight:   gui.UserConfig.Git.DiffHighlight,
+		SyntaxHighlighting:   gui.UserConfig.Gui.SyntaxHighlighting,
+		SyntaxHighlightCache: gui.syntaxHighlightCaches[viewName],
+		Linkifier:            gui.getLinkifier(),
+	}
+}
+
+func (gui *Gui) toggleSplitDiffView() error {
+	key := gui.currentStaticContext().GetKey()
+	showingSplittableDiff := false
+	for _, contextKey := range CONTEXT_KEYS_SHOWING_SPLITTABLE_DIFFS {
+		if key == contextKey {
+			showingSplittableDiff = true
+		}
+	}
+	if !showingSplittableDiff {
+		return nil
+	}
+
+	gui.State.SplitDiffView = !gui.State.SplitDiffView
+
+	toastMessage := gui.Tr.ShowingUnifiedDiffView
+	if gui.State.SplitDiffView {
+		toastMessage = gui.Tr.ShowingSplitDiffView
+	}
+	gui.raiseToast(toastMessage)
+
+	// if the main view is focused, its content comes from the side panel
+	return gui.currentSideContext().HandleRenderToMain()
+}
+
+// newRunDiffTask runs the diff command to completion (we can't compare the
+// lines of a hunk until we've seen all of it) and then renders the result
+// either unified or side by side.
+func (gui *Gui) newRunDiffTask(view *gocui.View, cmd *exec.Cmd, sideBySide bool) error {
+	cmdStr := strings.Join(cmd.Args, " ")
+	gui.Log.WithField(
+		"command",
+		cmdStr,
+	).Debug("RunCommand")
+
+	width, _ := view.Size()
+	manager := gui.getManager(view)
+	patchParserOpts := gui.patchParserOpts(view.Name())
+
+	f := func(stop chan struct{}) error {
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Start(); err != nil {
+			gui.setViewContent(view, err.Error())
+			gui.render()
+			return nil
+		}
+
+		// like the other command tasks, we kill the command if the task is
+		// stopped, e.g. because the user has moved on to another file
+		done := make(chan struct{})
+		go utils.Safe(func() {
+			select {
+			case <-stop:
+				if err := oscommands.Kill(cmd); err != nil && !strings.Contains(err.Error(), "process already finished") {
+					gui.Log.Error(err)
+				}
+			case <-done:
+			}
+		})
+		err := cmd.Wait()
+		close(done)
+
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		// `git diff --no-index` exits with 1 when there's a difference, so
+		// we only treat the exit code as a failure if something went wrong,
+		// e.g. the file has been deleted, and show what git had to say
+		if err != nil && (stderr.Len() > 0 || stdout.Len() == 0) {
+			message := strings.TrimSpace(stderr.String())
+			if message == "" {
+				message = err.Error()
+			}
+			gui.setViewContent(view, style.FgRed.Sprint(message))
+			gui.render()
+			return nil
+		}
+
+		parser := patch.NewPatchParser(gui.Log, utils.Decolorise(stdout.String()), patchParserOpts)
+		if sideBySide {
+			gui.setViewContent(view, parser.RenderSideBySide(width))
+		} else {
+			gui.setViewContent(view, parser.Render(-1, -1, nil))
+		}
+		gui.render()
+		return nil
+	}
+
+	// using the command as the key so that we only reset the origin when we
+	// switch to a different diff
+	return manager.NewTask(f, cmdStr)
+}
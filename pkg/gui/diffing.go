@@ -16,7 +16,7 @@ func (gui *Gui) renderDiff() error {
 	cmdObj := gui.OSCommand.Cmd.New(
 		fmt.Sprintf("git diff --submodule --no-ext-diff --color %s", gui.diffStr()),
 	)
-	task := NewRunPtyTask(cmdObj.GetCmd())
+	task := gui.newDiffTask(cmdObj)
 
 	return gui.refreshMainViews(refreshMainOpts{
 		main: &viewUpdateOpts{
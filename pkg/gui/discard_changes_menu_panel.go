@@ -13,7 +13,10 @@ func (gui *Gui) handleCreateDiscardMenu() error {
 				displayString: gui.Tr.LcDiscardAllChanges,
 				onPress: func() error {
 					gui.logAction(gui.Tr.Actions.DiscardAllChangesInDirectory)
-					if err := gui.Git.WorkingTree.DiscardAllDirChanges(node); err != nil {
+					err := gui.withWorkingTreeJournal(journalDescription(gui.Tr.Actions.DiscardAllChangesInDirectory, node.GetPath()), []string{node.GetPath()}, func() error {
+						return gui.Git.WorkingTree.DiscardAllDirChanges(node)
+					})
+					if err != nil {
 						return gui.surfaceError(err)
 					}
 					return gui.refreshSidePanels(refreshOptions{mode: ASYNC, scope: []RefreshableView{FILES}})
@@ -26,7 +29,10 @@ func (gui *Gui) handleCreateDiscardMenu() error {
 				displayString: gui.Tr.LcDiscardUnstagedChanges,
 				onPress: func() error {
 					gui.logAction(gui.Tr.Actions.DiscardUnstagedChangesInDirectory)
-					if err := gui.Git.WorkingTree.DiscardUnstagedDirChanges(node); err != nil {
+					err := gui.withWorkingTreeJournal(journalDescription(gui.Tr.Actions.DiscardUnstagedChangesInDirectory, node.GetPath()), []string{node.GetPath()}, func() error {
+						return gui.Git.WorkingTree.DiscardUnstagedDirChanges(node)
+					})
+					if err != nil {
 						return gui.surfaceError(err)
 					}
 
@@ -55,7 +61,10 @@ func (gui *Gui) handleCreateDiscardMenu() error {
 					displayString: gui.Tr.LcDiscardAllChanges,
 					onPress: func() error {
 						gui.logAction(gui.Tr.Actions.DiscardAllChangesInFile)
-						if err := gui.Git.WorkingTree.DiscardAllFileChanges(file); err != nil {
+						err := gui.withWorkingTreeJournal(journalDescription(gui.Tr.Actions.DiscardAllChangesInFile, file.Name), file.Names(), func() error {
+							return gui.Git.WorkingTree.DiscardAllFileChanges(file)
+						})
+						if err != nil {
 							return gui.surfaceError(err)
 						}
 						return gui.refreshSidePanels(refreshOptions{mode: ASYNC, scope: []RefreshableView{FILES}})
@@ -68,7 +77,10 @@ func (gui *Gui) handleCreateDiscardMenu() error {
 					displayString: gui.Tr.LcDiscardUnstagedChanges,
 					onPress: func() error {
 						gui.logAction(gui.Tr.Actions.DiscardAllUnstagedChangesInFile)
-						if err := gui.Git.WorkingTree.DiscardUnstagedFileChanges(file); err != nil {
+						err := gui.withWorkingTreeJournal(journalDescription(gui.Tr.Actions.DiscardAllUnstagedChangesInFile, file.Name), file.Names(), func() error {
+							return gui.Git.WorkingTree.DiscardUnstagedFileChanges(file)
+						})
+						if err != nil {
 							return gui.surfaceError(err)
 						}
 
@@ -85,3 +85,11 @@ func (gui *Gui) defaultEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.M
 
 	return matched
 }
+
+func (gui *Gui) mergeResultEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) bool {
+	matched := gui.handleEditorKeypress(v.TextArea, key, ch, mod, true)
+
+	v.RenderTextArea()
+
+	return matched
+}
@@ -69,9 +69,14 @@ func (gui *Gui) filesRenderToMain() error {
 
 	cmdObj := gui.Git.WorkingTree.WorktreeFileDiffCmdObj(node, false, !node.GetHasUnstagedChanges() && node.GetHasStagedChanges(), gui.State.IgnoreWhitespaceInDiffView)
 
+	task := gui.newDiffTask(cmdObj)
+	if node.File != nil && !node.File.Tracked {
+		task = gui.newUntrackedFileTask(cmdObj)
+	}
+
 	refreshOpts := refreshMainOpts{main: &viewUpdateOpts{
 		title: gui.Tr.UnstagedChanges,
-		task:  NewRunPtyTask(cmdObj.GetCmd()),
+		task:  task,
 	}}
 
 	if node.GetHasUnstagedChanges() {
@@ -80,7 +85,7 @@ func (gui *Gui) filesRenderToMain() error {
 
 			refreshOpts.secondary = &viewUpdateOpts{
 				title: gui.Tr.StagedChanges,
-				task:  NewRunPtyTask(cmdObj.GetCmd()),
+				task:  gui.newDiffTask(cmdObj),
 			}
 		}
 	} else {
@@ -181,19 +186,24 @@ func (gui *Gui) enterFile(opts OnFocusOpts) error {
 
 	file := node.File
 
+	if file.HasMergeConflicts {
+		// conflicts without markers (e.g. in a binary file or a submodule, or
+		// where one side deleted the file) are resolved from a menu instead
+		if file.HasInlineMergeConflicts {
+			// if we can't read the file it certainly has no markers
+			if hasMarkers, err := mergeconflicts.FileHasConflictMarkers(file.Name); err == nil && hasMarkers {
+				return gui.switchToMerge()
+			}
+		}
+		return gui.handleCreateConflictMenu(file)
+	}
+
 	submoduleConfigs := gui.State.Submodules
 	if file.IsSubmodule(submoduleConfigs) {
 		submoduleConfig := file.SubmoduleConfig(submoduleConfigs)
 		return gui.enterSubmodule(submoduleConfig)
 	}
 
-	if file.HasInlineMergeConflicts {
-		return gui.switchToMerge()
-	}
-	if file.HasMergeConflicts {
-		return gui.createErrorPanel(gui.Tr.FileStagingRequirements)
-	}
-
 	return gui.pushContext(gui.State.Contexts.Staging, opts)
 }
 
@@ -212,12 +222,18 @@ func (gui *Gui) handleFilePress() error {
 
 		if file.HasUnstagedChanges {
 			gui.logAction(gui.Tr.Actions.StageFile)
-			if err := gui.Git.WorkingTree.StageFile(file.Name); err != nil {
+			err := gui.withIndexJournal(journalDescription(gui.Tr.Actions.StageFile, file.Name), func() error {
+				return gui.Git.WorkingTree.StageFile(file.Name)
+			})
+			if err != nil {
 				return gui.surfaceError(err)
 			}
 		} else {
 			gui.logAction(gui.Tr.Actions.UnstageFile)
-			if err := gui.Git.WorkingTree.UnStageFile(file.Names(), file.Tracked); err != nil {
+			err := gui.withIndexJournal(journalDescription(gui.Tr.Actions.UnstageFile, file.Name), func() error {
+				return gui.Git.WorkingTree.UnStageFile(file.Names(), file.Tracked)
+			})
+			if err != nil {
 				return gui.surfaceError(err)
 			}
 		}
@@ -230,13 +246,19 @@ func (gui *Gui) handleFilePress() error {
 
 		if node.GetHasUnstagedChanges() {
 			gui.logAction(gui.Tr.Actions.StageFile)
-			if err := gui.Git.WorkingTree.StageFile(node.Path); err != nil {
+			err := gui.withIndexJournal(journalDescription(gui.Tr.Actions.StageFile, node.Path), func() error {
+				return gui.Git.WorkingTree.StageFile(node.Path)
+			})
+			if err != nil {
 				return gui.surfaceError(err)
 			}
 		} else {
 			// pretty sure it doesn't matter that we're always passing true here
 			gui.logAction(gui.Tr.Actions.UnstageFile)
-			if err := gui.Git.WorkingTree.UnStageFile([]string{node.Path}, true); err != nil {
+			err := gui.withIndexJournal(journalDescription(gui.Tr.Actions.UnstageFile, node.Path), func() error {
+				return gui.Git.WorkingTree.UnStageFile([]string{node.Path}, true)
+			})
+			if err != nil {
 				return gui.surfaceError(err)
 			}
 		}
@@ -267,10 +289,10 @@ func (gui *Gui) handleStageAll() error {
 	var err error
 	if gui.allFilesStaged() {
 		gui.logAction(gui.Tr.Actions.UnstageAllFiles)
-		err = gui.Git.WorkingTree.UnstageAll()
+		err = gui.withIndexJournal(gui.Tr.Actions.UnstageAllFiles, gui.Git.WorkingTree.UnstageAll)
 	} else {
 		gui.logAction(gui.Tr.Actions.StageAllFiles)
-		err = gui.Git.WorkingTree.StageAll()
+		err = gui.withIndexJournal(gui.Tr.Actions.StageAllFiles, gui.Git.WorkingTree.StageAll)
 	}
 	if err != nil {
 		_ = gui.surfaceError(err)
@@ -604,8 +626,23 @@ func (gui *Gui) refreshStateFiles() error {
 		}
 	}
 
-	if gui.Git.Status.WorkingTreeState() != enums.REBASE_MODE_NONE && conflictFileCount == 0 && prevConflictFileCount > 0 {
-		gui.OnUIThread(func() error { return gui.promptToContinueRebase() })
+	workingTreeState := gui.Git.Status.WorkingTreeState()
+	if workingTreeState == enums.REBASE_MODE_NONE {
+		state.RerereResolvedPaths = map[string]bool{}
+		// steps within a rebase mean nothing once it's over
+		state.Journal.dropRebaseSteps()
+	}
+	for _, file := range files {
+		file.ResolvedByRerere = state.RerereResolvedPaths[file.Name]
+	}
+
+	// this is the only place we ask the user to continue, whether they resolved
+	// the last conflict themselves or rerere resolved them all for them
+	resolvedAllConflicts := prevConflictFileCount > 0 || state.RerereResolvedAllConflicts
+	state.RerereResolvedAllConflicts = false
+	if workingTreeState != enums.REBASE_MODE_NONE && conflictFileCount == 0 && resolvedAllConflicts {
+		resolvedByRerere := len(state.RerereResolvedPaths) > 0
+		gui.OnUIThread(func() error { return gui.promptToContinueRebase(resolvedByRerere) })
 	}
 
 	// for when you stage the old file of a rename and the new file is in a collapsed dir
@@ -663,12 +700,17 @@ func (gui *Gui) refreshStateFiles() error {
 }
 
 // promptToContinueRebase asks the user if they want to continue the rebase/merge that's in progress
-func (gui *Gui) promptToContinueRebase() error {
+func (gui *Gui) promptToContinueRebase(resolvedByRerere bool) error {
 	gui.takeOverMergeConflictScrolling()
 
+	prompt := gui.Tr.ConflictsResolved
+	if resolvedByRerere {
+		prompt = gui.Tr.ConflictsResolvedByRerere
+	}
+
 	return gui.ask(askOpts{
 		title:  "continue",
-		prompt: gui.Tr.ConflictsResolved,
+		prompt: prompt,
 		handleConfirm: func() error {
 			return gui.genericMergeCommand(REBASE_OPTION_CONTINUE)
 		},
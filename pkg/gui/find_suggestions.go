@@ -158,6 +158,26 @@ func (gui *Gui) getTagNames() []string {
 	return result
 }
 
+func (gui *Gui) getTagsSuggestionsFunc() func(string) []*types.Suggestion {
+	return fuzzySearchFunc(gui.getTagNames())
+}
+
+// getAuthorNames returns the authors of the commits we've loaded, most recent
+// first
+func (gui *Gui) getAuthorNames() []string {
+	result := []string{}
+	for _, commit := range gui.State.Commits {
+		if commit.Author != "" && !utils.IncludesString(result, commit.Author) {
+			result = append(result, commit.Author)
+		}
+	}
+	return result
+}
+
+func (gui *Gui) getAuthorsSuggestionsFunc() func(string) []*types.Suggestion {
+	return fuzzySearchFunc(gui.getAuthorNames())
+}
+
 func (gui *Gui) getRefsSuggestionsFunc() func(string) []*types.Suggestion {
 	remoteBranchNames := gui.getRemoteBranchNames("/")
 	localBranchNames := gui.getBranchNames()
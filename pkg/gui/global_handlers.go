@@ -192,6 +192,9 @@ func (gui *Gui) handleMouseDownMain() error {
 		return gui.enterFile(OnFocusOpts{ClickedViewName: "main", ClickedViewLineIdx: gui.Views.Main.SelectedLineIdx()})
 	case gui.State.Contexts.CommitFiles:
 		return gui.enterCommitFile(OnFocusOpts{ClickedViewName: "main", ClickedViewLineIdx: gui.Views.Main.SelectedLineIdx()})
+	case gui.State.Contexts.BranchCommits, gui.State.Contexts.SubCommits, gui.State.Contexts.ReflogCommits:
+		_, err := gui.openLinkUnderCursor(gui.Views.Main)
+		return err
 	}
 
 	return nil
@@ -10,12 +10,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
 	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
 	"github.com/jesseduffield/lazygit/pkg/commands/git_config"
+	"github.com/jesseduffield/lazygit/pkg/commands/hosting_service"
 	"github.com/jesseduffield/lazygit/pkg/commands/models"
 	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
+	"github.com/jesseduffield/lazygit/pkg/commands/patch"
 	"github.com/jesseduffield/lazygit/pkg/common"
 	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/gui/filetree"
@@ -29,6 +32,7 @@ import (
 	"github.com/jesseduffield/lazygit/pkg/gui/presentation/graph"
 	"github.com/jesseduffield/lazygit/pkg/gui/style"
 	"github.com/jesseduffield/lazygit/pkg/gui/types"
+	"github.com/jesseduffield/lazygit/pkg/links"
 	"github.com/jesseduffield/lazygit/pkg/tasks"
 	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/updates"
@@ -89,6 +93,31 @@ type Gui struct {
 	viewBufferManagerMap map[string]*tasks.ViewBufferManager
 	stopChan             chan struct{}
 
+	// watches the user config files so that we can reload them on the fly
+	configWatcher *fsnotify.Watcher
+	// closed whenever the user config is reloaded so that our periodic refreshes
+	// can be restarted with the new intervals
+	refresherStopChan chan struct{}
+
+	// one for each of the views we render diffs to, keyed by view name
+	syntaxHighlightCaches map[string]*patch.SyntaxHighlightCache
+
+	// what git's credential helper gave us for each hosting service's web
+	// domain, so that we only ask it once. Guarded by CredentialsMutex.
+	hostingServiceCredentials map[string]hosting_service.Auth
+
+	// finds the links in commit messages. It depends on the user config and on
+	// the remote URL it was built for. Guarded by LinkifierMutex.
+	linkifier          *links.Linkifier
+	linkifierRemoteURL string
+
+	// bindings whose key is a sequence of keys rather than a single key
+	keySequenceBindings []*Binding
+	// set when the user has pressed the start of a key sequence
+	pendingKeySequence *pendingKeySequence
+	// the keys we've bound to the items of the open menu
+	menuItemKeys []interface{}
+
 	// when lazygit is opened outside a git directory we want to open to the most
 	// recent repo with the recent repos popup showing
 	showRecentRepos bool
@@ -175,6 +204,10 @@ type tagsPanelState struct {
 	listPanelState
 }
 
+type pullRequestsPanelState struct {
+	listPanelState
+}
+
 type commitPanelState struct {
 	listPanelState
 
@@ -224,6 +257,7 @@ type panelStates struct {
 	Remotes        *remotePanelState
 	RemoteBranches *remoteBranchesState
 	Tags           *tagsPanelState
+	PullRequests   *pullRequestsPanelState
 	Commits        *commitPanelState
 	ReflogCommits  *reflogCommitPanelState
 	SubCommits     *subCommitPanelState
@@ -286,6 +320,8 @@ type guiMutexes struct {
 	BranchCommitsMutex    sync.Mutex
 	LineByLinePanelMutex  sync.Mutex
 	SubprocessMutex       sync.Mutex
+	CredentialsMutex      sync.Mutex
+	LinkifierMutex        sync.Mutex
 }
 
 type guiState struct {
@@ -313,6 +349,13 @@ type guiState struct {
 	Tags           []*models.Tag
 	MenuItems      []*menuItem
 	BisectInfo     *git_commands.BisectInfo
+	// the repo's open pull requests, either from the hosting service's API or
+	// from the pull request refs we've fetched. We only set this on the UI
+	// thread, because we load it in the background.
+	PullRequests []*models.PullRequest
+	// whether we've listed the pull request refs we'd already fetched, since we
+	// opened the repo
+	LoadedPullRequests bool
 
 	Updating          bool
 	Panels            *panelStates
@@ -350,6 +393,27 @@ type guiState struct {
 	// flag as to whether or not the diff view should ignore whitespace
 	IgnoreWhitespaceInDiffView bool
 
+	// flag as to whether diffs are shown side by side rather than unified
+	SplitDiffView bool
+
+	// the files whose conflicts rerere has resolved in the current merge or
+	// rebase. We remember them because we stage them straight away, after which
+	// git no longer tells us they were conflicted.
+	RerereResolvedPaths map[string]bool
+
+	// set when rerere resolved every conflict a merge command ran into, so that
+	// the next files refresh asks the user to continue. Guarded by
+	// RefreshingFilesMutex.
+	RerereResolvedAllConflicts bool
+
+	// the working tree line numbers at which the user has split the hunks of
+	// each file they're staging, so that we can split them again when the diff
+	// is reloaded
+	HunkSplits map[string][]int
+
+	// what we need to undo the actions which don't show up in the reflog
+	Journal *Journal
+
 	// for displaying suggestions while typing in a file name
 	FilesTrie *patricia.Trie
 
@@ -408,6 +472,7 @@ func (gui *Gui) resetState(filterPath string, reuseState bool) {
 			Remotes:        &remotePanelState{listPanelState{SelectedLineIdx: 0}},
 			RemoteBranches: &remoteBranchesState{listPanelState{SelectedLineIdx: -1}},
 			Tags:           &tagsPanelState{listPanelState{SelectedLineIdx: -1}},
+			PullRequests:   &pullRequestsPanelState{listPanelState{SelectedLineIdx: -1}},
 			Commits:        &commitPanelState{listPanelState: listPanelState{SelectedLineIdx: 0}, LimitCommits: true},
 			ReflogCommits:  &reflogCommitPanelState{listPanelState{SelectedLineIdx: 0}},
 			SubCommits:     &subCommitPanelState{listPanelState: listPanelState{SelectedLineIdx: 0}, refName: ""},
@@ -433,6 +498,10 @@ func (gui *Gui) resetState(filterPath string, reuseState bool) {
 		ContextManager: NewContextManager(initialContext),
 		Contexts:       contexts,
 		FilesTrie:      patricia.NewTrie(),
+
+		RerereResolvedPaths: map[string]bool{},
+		HunkSplits:          map[string][]int{},
+		Journal:             &Journal{},
 	}
 
 	gui.RepoStateMap[Repo(currentDir)] = gui.State
@@ -464,6 +533,12 @@ func NewGui(
 		// but now we do it via state. So we need to still support the config for the
 		// sake of backwards compatibility. We're making use of short circuiting here
 		ShowExtrasWindow: cmn.UserConfig.Gui.ShowCommandLog && !config.GetAppState().HideCommandLog,
+
+		syntaxHighlightCaches: map[string]*patch.SyntaxHighlightCache{
+			"main":      patch.NewSyntaxHighlightCache(),
+			"secondary": patch.NewSyntaxHighlightCache(),
+		},
+		hostingServiceCredentials: map[string]hosting_service.Auth{},
 	}
 
 	guiIO := oscommands.NewGuiIO(
@@ -543,27 +618,25 @@ func (gui *Gui) Run() error {
 	if err := gui.Config.ReloadUserConfig(); err != nil {
 		return nil
 	}
-	userConfig := gui.UserConfig
-	g.SearchEscapeKey = gui.getKey(userConfig.Keybinding.Universal.Return)
-	g.NextSearchMatchKey = gui.getKey(userConfig.Keybinding.Universal.NextMatch)
-	g.PrevSearchMatchKey = gui.getKey(userConfig.Keybinding.Universal.PrevMatch)
-
-	g.ShowListFooter = userConfig.Gui.ShowListFooter
-
-	if userConfig.Gui.MouseEvents {
-		g.Mouse = true
+	if err := gui.validateUserConfig(gui.Config.GetUserConfig()); err != nil {
+		return err
 	}
+	gui.applyGocuiConfig()
 
 	if err := gui.setColorScheme(); err != nil {
 		return err
 	}
 
 	gui.waitForIntro.Add(1)
+	gui.refresherStopChan = make(chan struct{})
 	if gui.UserConfig.Git.AutoFetch {
 		go utils.Safe(gui.startBackgroundFetch)
 	}
 
-	gui.goEvery(time.Second*time.Duration(userConfig.Refresher.RefreshInterval), gui.stopChan, gui.refreshFilesAndSubmodules)
+	gui.startBackgroundRefresh()
+	gui.startBackgroundPullRequestRefresh()
+
+	gui.watchConfigFilesForChanges()
 
 	g.SetManager(gocui.ManagerFunc(gui.layout), gocui.ManagerFunc(gui.getFocusLayout()))
 
@@ -586,7 +659,12 @@ func (gui *Gui) RunAndHandleError() error {
 				gui.fileWatcher.Watcher.Close()
 			}
 
+			if gui.configWatcher != nil {
+				gui.configWatcher.Close()
+			}
+
 			close(gui.stopChan)
+			close(gui.refresherStopChan)
 
 			switch err {
 			case gocui.ErrQuit:
@@ -763,14 +841,35 @@ func (gui *Gui) startBackgroundFetch() {
 			prompt: gui.Tr.NoAutomaticGitFetchBody,
 		})
 	} else {
-		gui.goEvery(time.Second*time.Duration(userConfig.Refresher.FetchInterval), gui.stopChan, func() error {
-			err := gui.backgroundFetch()
-			gui.render()
-			return err
-		})
+		gui.startBackgroundFetchTicker()
 	}
 }
 
+func (gui *Gui) startBackgroundFetchTicker() {
+	gui.goEvery(time.Second*time.Duration(gui.UserConfig.Refresher.FetchInterval), gui.refresherStopChan, func() error {
+		err := gui.backgroundFetch()
+		gui.render()
+		return err
+	})
+}
+
+func (gui *Gui) startBackgroundRefresh() {
+	gui.goEvery(time.Second*time.Duration(gui.UserConfig.Refresher.RefreshInterval), gui.refresherStopChan, gui.refreshFilesAndSubmodules)
+}
+
+// applyGocuiConfig passes the parts of the user config that gocui cares about
+// through to gocui
+func (gui *Gui) applyGocuiConfig() {
+	userConfig := gui.UserConfig
+	gui.g.SearchEscapeKey = gui.getKey(userConfig.Keybinding.Universal.Return)
+	gui.g.NextSearchMatchKey = gui.getKey(userConfig.Keybinding.Universal.NextMatch)
+	gui.g.PrevSearchMatchKey = gui.getKey(userConfig.Keybinding.Universal.PrevMatch)
+
+	gui.g.ShowListFooter = userConfig.Gui.ShowListFooter
+
+	gui.g.Mouse = userConfig.Gui.MouseEvents
+}
+
 // setColorScheme sets the color scheme for the app based on the user config
 func (gui *Gui) setColorScheme() error {
 	userConfig := gui.UserConfig
@@ -0,0 +1,554 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/jesseduffield/lazygit/pkg/commands/types/enums"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// The reflog only knows about what moves HEAD, so it can't help us undo
+// deleting a branch, dropping a stash entry, or staging and discarding changes.
+// Before we do any of those, we record enough in the operation journal to put
+// things back the way they were, and enough afterwards to redo the action.
+//
+// The journal lives alongside the reflog: when undoing, we go with whichever
+// of the journal's last entry and the reflog's last user action happened most
+// recently. Each journal entry remembers how long the reflog was when it was
+// recorded, which is how we tell which came first.
+
+type JournalEntryKind int
+
+const (
+	DELETE_BRANCH JournalEntryKind = iota
+	DROP_STASH
+	STAGE
+	DISCARD
+	// a step taken in the middle of an interactive rebase, like changing the
+	// todo list or continuing
+	REBASE_STEP
+	// stands in for an action we undid through the reflog, so that we know to
+	// redo it through the reflog as well
+	REFLOG_ACTION
+)
+
+type JournalEntry struct {
+	Kind        JournalEntryKind
+	Description string
+	Time        time.Time
+	// the state before and after the action. For a branch, its tip before
+	// deletion; for a stash entry, its commit; for staging, index trees; and
+	// for a discard, working tree snapshots
+	From string
+	To   string
+	// the branch's name, or the stash entry's message
+	Name string
+	// the branch's upstream, if it had one
+	Upstream string
+	// the paths a discard touched
+	Paths []string
+	// the state of the rebase in progress before and after a rebase step. We
+	// can't always take the second one, e.g. if the step stopped with conflicts,
+	// in which case the step can't be redone.
+	RebaseFrom *git_commands.RebaseSnapshot
+	RebaseTo   *git_commands.RebaseSnapshot
+	// the working tree after a rebase step that stopped with conflicts, so that
+	// we can tell whether the user has changed it before we undo the step
+	ConflictedWorkingTree string
+	// the number of entries in the reflog we undo through when the entry was
+	// recorded
+	ReflogLength int
+}
+
+type Journal struct {
+	// the entries which haven't been undone, oldest first
+	entries []*JournalEntry
+	// the entries we've undone, with the most recently undone last
+	undone []*JournalEntry
+}
+
+func (self *Journal) record(entry *JournalEntry) {
+	self.entries = append(self.entries, entry)
+	// like any undo stack, once you do something new you can't redo what you
+	// undid before it
+	self.undone = nil
+}
+
+func (self *Journal) lastEntry() *JournalEntry {
+	if len(self.entries) == 0 {
+		return nil
+	}
+	return self.entries[len(self.entries)-1]
+}
+
+func (self *Journal) lastUndone() *JournalEntry {
+	if len(self.undone) == 0 {
+		return nil
+	}
+	return self.undone[len(self.undone)-1]
+}
+
+func (self *Journal) markUndone(entry *JournalEntry) {
+	self.entries = withoutEntry(self.entries, entry)
+	self.undone = append(self.undone, entry)
+}
+
+func (self *Journal) markRedone(entry *JournalEntry) {
+	self.undone = withoutEntry(self.undone, entry)
+	if entry.Kind != REFLOG_ACTION {
+		self.entries = append(self.entries, entry)
+	}
+}
+
+// dropRebaseSteps forgets the steps of a rebase that's no longer in progress
+func (self *Journal) dropRebaseSteps() {
+	isRebaseStep := func(entry *JournalEntry) bool { return entry.Kind == REBASE_STEP }
+	self.entries = rejectEntries(self.entries, isRebaseStep)
+	self.undone = rejectEntries(self.undone, isRebaseStep)
+}
+
+func withoutEntry(entries []*JournalEntry, entry *JournalEntry) []*JournalEntry {
+	return rejectEntries(entries, func(e *JournalEntry) bool { return e == entry })
+}
+
+func rejectEntries(entries []*JournalEntry, test func(*JournalEntry) bool) []*JournalEntry {
+	result := make([]*JournalEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !test(entry) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// journalDescription describes an action on a particular file, branch, etc.
+func journalDescription(action string, subject string) string {
+	return fmt.Sprintf("%s: %s", action, subject)
+}
+
+func (gui *Gui) recordJournalEntry(entry *JournalEntry) {
+	entry.Time = time.Now()
+	entry.ReflogLength = gui.reflogLength()
+	gui.State.Journal.record(entry)
+}
+
+// reflogLength returns the number of entries in the reflog we go through when
+// undoing, bringing it up to date first. Journal entries have to be positioned
+// against that same list, or we'd get the order of things wrong when e.g. the
+// list is filtered by path.
+func (gui *Gui) reflogLength() int {
+	if err := gui.refreshReflogCommits(); err != nil {
+		gui.Log.Error(err)
+	}
+	return len(gui.State.FilteredReflogCommits)
+}
+
+// withIndexJournal records what the index looks like before and after f, so
+// that staging and unstaging can be undone
+func (gui *Gui) withIndexJournal(description string, f func() error) error {
+	before, err := gui.Git.Snapshot.IndexTree()
+	if err != nil {
+		// e.g. there are merge conflicts. It's no reason not to go ahead.
+		gui.Log.Error(err)
+		return f()
+	}
+
+	if err := f(); err != nil {
+		return err
+	}
+
+	after, err := gui.Git.Snapshot.IndexTree()
+	if err != nil {
+		gui.Log.Error(err)
+		return nil
+	}
+	if after == before {
+		return nil
+	}
+
+	gui.recordJournalEntry(&JournalEntry{
+		Kind:        STAGE,
+		Description: description,
+		From:        before,
+		To:          after,
+	})
+	return nil
+}
+
+// withWorkingTreeJournal snapshots the given paths in the working tree before
+// and after f, so that discarding the changes to them can be undone
+func (gui *Gui) withWorkingTreeJournal(description string, paths []string, f func() error) error {
+	before, err := gui.Git.Snapshot.WorkingTree(paths)
+	if err != nil {
+		gui.Log.Error(err)
+		if err := f(); err != nil {
+			return err
+		}
+		return gui.surfaceSnapshotError(description, err)
+	}
+
+	if err := f(); err != nil {
+		return err
+	}
+
+	after, err := gui.Git.Snapshot.WorkingTree(paths)
+	if err != nil {
+		gui.Log.Error(err)
+		return gui.surfaceSnapshotError(description, err)
+	}
+
+	gui.recordJournalEntry(&JournalEntry{
+		Kind:        DISCARD,
+		Description: description,
+		From:        before,
+		To:          after,
+		Paths:       paths,
+	})
+	return nil
+}
+
+// surfaceSnapshotError tells the user that an action they took can't be undone
+// because we couldn't snapshot the working tree
+func (gui *Gui) surfaceSnapshotError(description string, err error) error {
+	return gui.createErrorPanel(fmt.Sprintf(gui.Tr.CantSnapshotWorkingTree, description, err.Error()))
+}
+
+// withRebaseJournal snapshots the interactive rebase in progress before and
+// after f, so that a step of the rebase can be undone without aborting the
+// whole thing. We only need the working tree if f can change it.
+func (gui *Gui) withRebaseJournal(description string, includeWorkingTree bool, f func() error) error {
+	before, err := gui.Git.Snapshot.Rebase(includeWorkingTree)
+	if err != nil {
+		gui.Log.Error(err)
+		return f()
+	}
+
+	// continuing can fail because of conflicts even though the rebase has moved
+	// on, so we record the step either way
+	result := f()
+
+	if gui.Git.Status.WorkingTreeState() != enums.REBASE_MODE_REBASING {
+		// the rebase is over, and it's up to the reflog to undo it now
+		return result
+	}
+
+	after, err := gui.Git.Snapshot.Rebase(includeWorkingTree)
+	if err != nil {
+		// e.g. the step stopped with conflicts, which we can't snapshot
+		gui.Log.Error(err)
+		after = nil
+	} else if after.SameRebaseState(before) {
+		return result
+	}
+
+	entry := &JournalEntry{
+		Kind:        REBASE_STEP,
+		Description: description,
+		From:        before.Head,
+		RebaseFrom:  before,
+		RebaseTo:    after,
+	}
+	if after != nil {
+		entry.To = after.Head
+	} else if includeWorkingTree {
+		if entry.ConflictedWorkingTree, err = gui.Git.Snapshot.WorkingTreeTree([]string{"."}); err != nil {
+			gui.Log.Error(err)
+		}
+	}
+	gui.recordJournalEntry(entry)
+	return result
+}
+
+// journalDeleteBranch remembers where a branch pointed before we delete it
+func (gui *Gui) journalDeleteBranch(branchName string, f func() error) error {
+	sha, upstream, err := gui.Git.Snapshot.BranchTip(branchName)
+	if err != nil {
+		gui.Log.Error(err)
+		return f()
+	}
+
+	if err := f(); err != nil {
+		return err
+	}
+
+	gui.recordJournalEntry(&JournalEntry{
+		Kind:        DELETE_BRANCH,
+		Description: journalDescription(gui.Tr.Actions.DeleteBranch, branchName),
+		From:        sha,
+		Name:        branchName,
+		Upstream:    upstream,
+	})
+	return nil
+}
+
+// journalDropStash remembers the commit of a stash entry before we drop it
+func (gui *Gui) journalDropStash(index int, message string, f func() error) error {
+	shas, err := gui.Git.Stash.Shas()
+	if err != nil {
+		gui.Log.Error(err)
+		return f()
+	}
+	if index >= len(shas) {
+		return f()
+	}
+
+	if err := f(); err != nil {
+		return err
+	}
+
+	gui.recordJournalEntry(&JournalEntry{
+		Kind:        DROP_STASH,
+		Description: journalDescription(gui.Tr.Actions.DropStash, message),
+		From:        shas[index],
+		Name:        message,
+	})
+	return nil
+}
+
+// journalEntryIsMoreRecent tells us whether the journal entry happened after
+// the user action that undoing through the reflog would undo
+func (gui *Gui) journalEntryIsMoreRecent(entry *JournalEntry) bool {
+	moreRecent := true
+	_ = gui.parseReflogForActions(func(counter int, action reflogAction) (bool, error) {
+		if counter != 0 {
+			return false, nil
+		}
+		moreRecent = gui.journalEntryIsMoreRecentThan(entry, action)
+		return true, nil
+	})
+	return moreRecent
+}
+
+func (gui *Gui) journalEntryIsMoreRecentThan(entry *JournalEntry, action reflogAction) bool {
+	// the action's position counting from the start of the reflog
+	position := len(gui.State.FilteredReflogCommits) - action.reflogIdx
+	return position <= entry.ReflogLength
+}
+
+func (gui *Gui) handleUndo() error {
+	entry := gui.State.Journal.lastEntry()
+	if entry == nil || !gui.journalEntryIsMoreRecent(entry) {
+		return gui.reflogUndo()
+	}
+
+	gui.logAction(gui.Tr.Actions.Undo)
+	return gui.WithWaitingStatus(gui.Tr.UndoingStatus, func() error {
+		if err := gui.undoJournalEntry(entry); err != nil {
+			return err
+		}
+		gui.State.Journal.markUndone(entry)
+
+		gui.raiseToast(fmt.Sprintf(gui.Tr.UndidAction, entry.Description))
+		return gui.refreshSidePanels(refreshOptions{mode: BLOCK_UI})
+	})
+}
+
+func (gui *Gui) handleRedo() error {
+	entry := gui.State.Journal.lastUndone()
+	if entry == nil || entry.Kind == REFLOG_ACTION {
+		return gui.reflogRedo()
+	}
+
+	gui.logAction(gui.Tr.Actions.Redo)
+	return gui.WithWaitingStatus(gui.Tr.RedoingStatus, func() error {
+		if err := gui.redoJournalEntry(entry); err != nil {
+			return err
+		}
+		gui.State.Journal.markRedone(entry)
+		// the entry now comes after anything that's happened in the meantime
+		entry.ReflogLength = gui.reflogLength()
+
+		gui.raiseToast(fmt.Sprintf(gui.Tr.RedidAction, entry.Description))
+		return gui.refreshSidePanels(refreshOptions{mode: BLOCK_UI})
+	})
+}
+
+// journalReflogUndo notes that we've undone an action through the reflog
+func (gui *Gui) journalReflogUndo(action reflogAction) {
+	gui.State.Journal.markUndone(&JournalEntry{
+		Kind: REFLOG_ACTION,
+		Time: time.Now(),
+		From: action.from,
+		To:   action.to,
+	})
+}
+
+// journalReflogRedo notes that we've redone an action through the reflog
+func (gui *Gui) journalReflogRedo() {
+	if entry := gui.State.Journal.lastUndone(); entry != nil && entry.Kind == REFLOG_ACTION {
+		gui.State.Journal.markRedone(entry)
+	}
+}
+
+// expectedWorkingTree returns the working tree we expect to find when undoing
+// or redoing an entry, which is the one the entry left behind, along with the
+// paths that undoing or redoing it will overwrite. The snapshot is empty if we
+// don't know what to expect, and the paths are empty if the working tree won't
+// be touched.
+func (entry *JournalEntry) expectedWorkingTree(undo bool) (string, []string) {
+	switch entry.Kind {
+	case DISCARD:
+		if undo {
+			return entry.To, entry.Paths
+		}
+		return entry.From, entry.Paths
+	case REBASE_STEP:
+		// restoring a rebase snapshot only touches the working tree if the
+		// snapshot includes it
+		if entry.RebaseFrom.WorkingTree == "" {
+			return "", nil
+		}
+		if !undo {
+			return entry.RebaseFrom.WorkingTree, []string{"."}
+		}
+		if entry.RebaseTo != nil {
+			return entry.RebaseTo.WorkingTree, []string{"."}
+		}
+		return entry.ConflictedWorkingTree, []string{"."}
+	}
+	return "", nil
+}
+
+// checkWorkingTreeUnchanged refuses to undo or redo an entry if that would
+// overwrite changes made to the working tree since, because we'd have no way
+// of getting them back
+func (gui *Gui) checkWorkingTreeUnchanged(entry *JournalEntry, undo bool) error {
+	expected, paths := entry.expectedWorkingTree(undo)
+	return gui.checkPathsUnchanged(entry, expected, paths)
+}
+
+// checkPathsUnchanged refuses to go back over an entry if any of the given
+// paths differ from the working tree snapshot we expect to find
+func (gui *Gui) checkPathsUnchanged(entry *JournalEntry, expected string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	if expected == "" {
+		return errors.New(gui.Tr.CantTellIfWorkingTreeChanged)
+	}
+
+	changedFiles, err := gui.Git.Snapshot.WorkingTreeChanges(expected, paths)
+	if err != nil {
+		return err
+	}
+	if len(changedFiles) > 0 {
+		return fmt.Errorf(gui.Tr.WorkingTreeChangedSinceAction, entry.Description, strings.Join(changedFiles, "\n"))
+	}
+	return nil
+}
+
+// expectedIndex returns the index tree we expect to find when undoing or
+// redoing an entry which replaces the index, or an empty string if the entry
+// doesn't replace it
+func (entry *JournalEntry) expectedIndex(undo bool) string {
+	if entry.Kind != STAGE {
+		return ""
+	}
+	if undo {
+		return entry.To
+	}
+	return entry.From
+}
+
+// checkIndexUnchanged refuses to undo or redo an entry which replaces the index
+// if anything has been staged or unstaged since, because replacing the index
+// would lose that
+func (gui *Gui) checkIndexUnchanged(entry *JournalEntry, undo bool) error {
+	expected := entry.expectedIndex(undo)
+	if expected == "" {
+		return nil
+	}
+
+	current, err := gui.Git.Snapshot.IndexTree()
+	if err != nil {
+		return err
+	}
+	if current != expected {
+		return fmt.Errorf(gui.Tr.IndexChangedSinceAction, entry.Description)
+	}
+	return nil
+}
+
+func (gui *Gui) undoJournalEntry(entry *JournalEntry) error {
+	if err := gui.checkWorkingTreeUnchanged(entry, true); err != nil {
+		return err
+	}
+	if err := gui.checkIndexUnchanged(entry, true); err != nil {
+		return err
+	}
+	return gui.undoJournalEntryUnchecked(entry)
+}
+
+func (gui *Gui) redoJournalEntry(entry *JournalEntry) error {
+	if err := gui.checkCanRedo(entry); err != nil {
+		return err
+	}
+	if err := gui.checkWorkingTreeUnchanged(entry, false); err != nil {
+		return err
+	}
+	if err := gui.checkIndexUnchanged(entry, false); err != nil {
+		return err
+	}
+	return gui.redoJournalEntryUnchecked(entry)
+}
+
+func (gui *Gui) checkCanRedo(entry *JournalEntry) error {
+	if entry.Kind == REBASE_STEP && entry.RebaseTo == nil {
+		return errors.New(gui.Tr.CantRedoRebaseStep)
+	}
+	return nil
+}
+
+// undoJournalEntryUnchecked puts things back the way they were before the
+// entry's action, without checking whether that would lose any changes
+func (gui *Gui) undoJournalEntryUnchecked(entry *JournalEntry) error {
+	switch entry.Kind {
+	case DELETE_BRANCH:
+		return gui.Git.Snapshot.RestoreBranch(entry.Name, entry.From, entry.Upstream)
+	case DROP_STASH:
+		return gui.Git.Stash.Store(entry.From, entry.Name)
+	case STAGE:
+		return gui.Git.Snapshot.RestoreIndex(entry.From)
+	case DISCARD:
+		return gui.Git.Snapshot.RestoreWorkingTree(entry.From, entry.Paths)
+	case REBASE_STEP:
+		return gui.Git.Snapshot.RestoreRebase(entry.RebaseFrom)
+	}
+	return nil
+}
+
+func (gui *Gui) redoJournalEntryUnchecked(entry *JournalEntry) error {
+	switch entry.Kind {
+	case DELETE_BRANCH:
+		return gui.Git.Branch.Delete(entry.Name, true)
+	case DROP_STASH:
+		return gui.dropStashWithSha(entry.From)
+	case STAGE:
+		return gui.Git.Snapshot.RestoreIndex(entry.To)
+	case DISCARD:
+		return gui.Git.Snapshot.RestoreWorkingTree(entry.To, entry.Paths)
+	case REBASE_STEP:
+		return gui.Git.Snapshot.RestoreRebase(entry.RebaseTo)
+	}
+	return nil
+}
+
+// dropStashWithSha drops a stash entry we restored, wherever it's got to in
+// the stash list since
+func (gui *Gui) dropStashWithSha(sha string) error {
+	shas, err := gui.Git.Stash.Shas()
+	if err != nil {
+		return err
+	}
+
+	for index, stashSha := range shas {
+		if stashSha == sha {
+			return gui.Git.Stash.Drop(index)
+		}
+	}
+	return fmt.Errorf(gui.Tr.StashEntryNotFound, utils.ShortSha(sha))
+}
@@ -0,0 +1,156 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+	journal := &Journal{}
+	assert.Nil(t, journal.lastEntry())
+	assert.Nil(t, journal.lastUndone())
+
+	first := &JournalEntry{Kind: DELETE_BRANCH, Name: "first"}
+	second := &JournalEntry{Kind: STAGE}
+	journal.record(first)
+	journal.record(second)
+	assert.Equal(t, second, journal.lastEntry())
+
+	journal.markUndone(second)
+	assert.Equal(t, first, journal.lastEntry())
+	assert.Equal(t, second, journal.lastUndone())
+
+	// undoing through the reflog doesn't touch the journal's own entries
+	reflogAction := &JournalEntry{Kind: REFLOG_ACTION}
+	journal.markUndone(reflogAction)
+	assert.Equal(t, first, journal.lastEntry())
+	assert.Equal(t, reflogAction, journal.lastUndone())
+
+	journal.markRedone(reflogAction)
+	assert.Equal(t, first, journal.lastEntry())
+	assert.Equal(t, second, journal.lastUndone())
+
+	journal.markRedone(second)
+	assert.Equal(t, second, journal.lastEntry())
+	assert.Nil(t, journal.lastUndone())
+
+	// doing something new means we can't redo what we undid before
+	journal.markUndone(second)
+	third := &JournalEntry{Kind: DISCARD}
+	journal.record(third)
+	assert.Equal(t, third, journal.lastEntry())
+	assert.Nil(t, journal.lastUndone())
+}
+
+func TestJournalDropRebaseSteps(t *testing.T) {
+	journal := &Journal{}
+	branch := &JournalEntry{Kind: DELETE_BRANCH, Name: "branch"}
+	firstStep := &JournalEntry{Kind: REBASE_STEP}
+	secondStep := &JournalEntry{Kind: REBASE_STEP}
+	journal.record(branch)
+	journal.record(firstStep)
+	journal.record(secondStep)
+	journal.markUndone(secondStep)
+
+	journal.dropRebaseSteps()
+	assert.Equal(t, branch, journal.lastEntry())
+	assert.Nil(t, journal.lastUndone())
+}
+
+func TestJournalEntryExpectedWorkingTree(t *testing.T) {
+	type scenario struct {
+		testName         string
+		entry            *JournalEntry
+		undo             bool
+		expectedSnapshot string
+		expectedPaths    []string
+	}
+
+	withWorkingTree := func(workingTree string) *git_commands.RebaseSnapshot {
+		return &git_commands.RebaseSnapshot{Head: "head", WorkingTree: workingTree}
+	}
+
+	scenarios := []scenario{
+		{
+			testName:         "undoing a discard expects what the discard left",
+			entry:            &JournalEntry{Kind: DISCARD, From: "before", To: "after", Paths: []string{"a.txt"}},
+			undo:             true,
+			expectedSnapshot: "after",
+			expectedPaths:    []string{"a.txt"},
+		},
+		{
+			testName:         "redoing a discard expects what undoing it left",
+			entry:            &JournalEntry{Kind: DISCARD, From: "before", To: "after", Paths: []string{"a.txt"}},
+			undo:             false,
+			expectedSnapshot: "before",
+			expectedPaths:    []string{"a.txt"},
+		},
+		{
+			testName:         "rebase step that leaves the working tree alone",
+			entry:            &JournalEntry{Kind: REBASE_STEP, RebaseFrom: withWorkingTree(""), RebaseTo: withWorkingTree("")},
+			undo:             true,
+			expectedSnapshot: "",
+			expectedPaths:    nil,
+		},
+		{
+			testName:         "undoing a rebase step",
+			entry:            &JournalEntry{Kind: REBASE_STEP, RebaseFrom: withWorkingTree("before"), RebaseTo: withWorkingTree("after")},
+			undo:             true,
+			expectedSnapshot: "after",
+			expectedPaths:    []string{"."},
+		},
+		{
+			testName:         "undoing a rebase step that stopped with conflicts",
+			entry:            &JournalEntry{Kind: REBASE_STEP, RebaseFrom: withWorkingTree("before"), ConflictedWorkingTree: "conflicted"},
+			undo:             true,
+			expectedSnapshot: "conflicted",
+			expectedPaths:    []string{"."},
+		},
+		{
+			testName:         "redoing a rebase step",
+			entry:            &JournalEntry{Kind: REBASE_STEP, RebaseFrom: withWorkingTree("before"), RebaseTo: withWorkingTree("after")},
+			undo:             false,
+			expectedSnapshot: "before",
+			expectedPaths:    []string{"."},
+		},
+		{
+			testName:         "staging doesn't touch the working tree",
+			entry:            &JournalEntry{Kind: STAGE, From: "before", To: "after"},
+			undo:             true,
+			expectedSnapshot: "",
+			expectedPaths:    nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			snapshot, paths := s.entry.expectedWorkingTree(s.undo)
+			assert.Equal(t, s.expectedSnapshot, snapshot)
+			assert.EqualValues(t, s.expectedPaths, paths)
+		})
+	}
+}
+
+func TestJournalEntryExpectedIndex(t *testing.T) {
+	scenarios := []struct {
+		testName string
+		entry    *JournalEntry
+		undo     bool
+		expected string
+	}{
+		{"undoing staging expects what the staging left", &JournalEntry{Kind: STAGE, From: "before", To: "after"}, true, "after"},
+		{"redoing staging expects what undoing it left", &JournalEntry{Kind: STAGE, From: "before", To: "after"}, false, "before"},
+		{"a discard doesn't replace the index", &JournalEntry{Kind: DISCARD, From: "before", To: "after", Paths: []string{"a.txt"}}, true, ""},
+		{"deleting a branch doesn't touch the index", &JournalEntry{Kind: DELETE_BRANCH, Name: "feature", From: "abc"}, true, ""},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			assert.Equal(t, s.expected, s.entry.expectedIndex(s.undo))
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// LEADER_KEY is expanded to the configured leader key when it appears in a
+// key sequence
+const LEADER_KEY = "<leader>"
+
+// KEY_SEQUENCE_TIMEOUT is how long we wait for the next key of a sequence
+// before giving up on it
+const KEY_SEQUENCE_TIMEOUT = time.Second
+
+// keySequence is a binding's key when the user has configured several keys
+// to be pressed one after the other, e.g. 'g g' or '<leader> b'. gocui only
+// knows about single keys so we bind the first key of the sequence and then
+// track the rest of the sequence ourselves.
+type keySequence []interface{}
+
+func (self keySequence) display() string {
+	keyDisplays := make([]string, len(self))
+	for i, key := range self {
+		keyDisplays[i] = GetKeyDisplay(key)
+	}
+	return strings.Join(keyDisplays, " ")
+}
+
+// hasPrefix tells us if the sequence starts with the given keys
+func (self keySequence) hasPrefix(keys keySequence) bool {
+	if len(keys) > len(self) {
+		return false
+	}
+	for i, key := range keys {
+		if !reflect.DeepEqual(self[i], key) {
+			return false
+		}
+	}
+	return true
+}
+
+// pendingKeySequence is the state we hold onto after the user has pressed
+// the first key(s) of one or more key sequences
+type pendingKeySequence struct {
+	keys       keySequence
+	candidates []*Binding
+}
+
+func keySequenceFromStrings(keys []string, leader string) (interface{}, error) {
+	sequence := keySequence{}
+	for _, key := range keys {
+		if strings.ToLower(key) == LEADER_KEY {
+			if leader == "" {
+				return nil, errors.New("Keybinding uses <leader> but no leader key is configured (keybinding.universal.leader)")
+			}
+			key = leader
+		}
+		if strings.ToLower(key) == LEADER_KEY || len(strings.Fields(key)) > 1 {
+			return nil, fmt.Errorf("Leader key must be a single key, got: %s", leader)
+		}
+
+		parsedKey, err := keyFromString(key, "")
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, parsedKey)
+	}
+
+	if len(sequence) == 1 {
+		return sequence[0], nil
+	}
+
+	return sequence, nil
+}
+
+// asKeySequence lets us treat single keys and key sequences alike
+func asKeySequence(key interface{}) keySequence {
+	if sequence, ok := key.(keySequence); ok {
+		return sequence
+	}
+	return keySequence{key}
+}
+
+func getKeySequenceBindings(bindings []*Binding) []*Binding {
+	result := []*Binding{}
+	for _, binding := range bindings {
+		if _, ok := binding.Key.(keySequence); ok {
+			result = append(result, binding)
+		}
+	}
+	return result
+}
+
+// toGocuiBindings converts our bindings into ones that gocui can handle. For
+// a key sequence, we bind its first key to start the sequence, and bind the
+// remaining keys globally so that we hear about them even when the focused
+// view has no binding for them. Those global bindings go last so that they
+// never take precedence over a real binding.
+func (gui *Gui) toGocuiBindings(bindings []*Binding) []*Binding {
+	result := []*Binding{}
+	followUpBindings := []*Binding{}
+
+	for _, binding := range bindings {
+		sequence, ok := binding.Key.(keySequence)
+		if !ok {
+			result = append(result, binding)
+			continue
+		}
+
+		viewName := binding.ViewName
+		firstKey := sequence[0]
+		result = append(result, &Binding{
+			ViewName: viewName,
+			Contexts: binding.Contexts,
+			Key:      firstKey,
+			Modifier: gocui.ModNone,
+			Handler:  func() error { return gui.startKeySequence(viewName, firstKey) },
+		})
+
+	outer:
+		for _, key := range sequence[1:] {
+			for _, followUpBinding := range followUpBindings {
+				if reflect.DeepEqual(followUpBinding.Key, key) {
+					continue outer
+				}
+			}
+			followUpBindings = append(followUpBindings, &Binding{
+				ViewName: "",
+				Key:      key,
+				Modifier: gocui.ModNone,
+				// this is only reached mid-sequence, at which point keybindingHandler
+				// intercepts the keypress before calling us
+				Handler: func() error { return nil },
+			})
+		}
+	}
+
+	return append(result, followUpBindings...)
+}
+
+// keybindingHandler wraps the handler of every binding we give to gocui, so
+// that when a key sequence is underway, the keypress continues the sequence
+// instead of doing what it would normally do.
+func (gui *Gui) keybindingHandler(key interface{}, handler func() error) func(g *gocui.Gui, v *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if gui.pendingKeySequence != nil {
+			return gui.continueKeySequence(key, handler)
+		}
+
+		return handler()
+	}
+}
+
+func (gui *Gui) startKeySequence(viewName string, firstKey interface{}) error {
+	currentContext := ""
+	if viewName != "" {
+		view, err := gui.g.View(viewName)
+		if err != nil {
+			return nil
+		}
+		currentContext = view.Context
+	}
+
+	candidates := []*Binding{}
+	for _, binding := range gui.keySequenceBindings {
+		if binding.ViewName != viewName {
+			continue
+		}
+		if len(binding.Contexts) > 0 && !utils.IncludesString(binding.Contexts, currentContext) {
+			continue
+		}
+		if !asKeySequence(binding.Key).hasPrefix(keySequence{firstKey}) {
+			continue
+		}
+		candidates = append(candidates, binding)
+	}
+
+	return gui.setPendingKeySequence(keySequence{firstKey}, candidates)
+}
+
+// continueKeySequence handles a key pressed mid-sequence. If the key doesn't
+// continue any of the sequences, it cancels them and then does what it would
+// have done anyway, by way of the handler gocui found for it.
+func (gui *Gui) continueKeySequence(key interface{}, handler func() error) error {
+	keys := append(keySequence{}, gui.pendingKeySequence.keys...)
+	keys = append(keys, key)
+
+	candidates := []*Binding{}
+	for _, binding := range gui.pendingKeySequence.candidates {
+		sequence := asKeySequence(binding.Key)
+		if !sequence.hasPrefix(keys) {
+			continue
+		}
+		if len(sequence) == len(keys) {
+			// we don't allow one sequence to be the prefix of another (see
+			// getKeybindingConflicts) so we know this is the only match
+			gui.pendingKeySequence = nil
+			return binding.Handler()
+		}
+		candidates = append(candidates, binding)
+	}
+
+	if len(candidates) == 0 {
+		gui.cancelKeySequence()
+		return handler()
+	}
+
+	return gui.setPendingKeySequence(keys, candidates)
+}
+
+// cancelKeySequence forgets about any sequence the user is partway through,
+// e.g. because the focus has moved to another context
+func (gui *Gui) cancelKeySequence() {
+	gui.pendingKeySequence = nil
+}
+
+func (gui *Gui) setPendingKeySequence(keys keySequence, candidates []*Binding) error {
+	if len(candidates) == 0 {
+		gui.cancelKeySequence()
+		return nil
+	}
+
+	pending := &pendingKeySequence{keys: keys, candidates: candidates}
+	gui.pendingKeySequence = pending
+	time.AfterFunc(KEY_SEQUENCE_TIMEOUT, func() {
+		gui.OnUIThread(func() error {
+			if gui.pendingKeySequence == pending {
+				gui.cancelKeySequence()
+			}
+			return nil
+		})
+	})
+
+	nextSteps := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		nextSteps[i] = fmt.Sprintf("%s: %s", candidate.Key.(keySequence)[len(keys):].display(), candidate.Description)
+	}
+	gui.raiseToast(fmt.Sprintf("%s ... (%s)", keys.display(), strings.Join(nextSteps, ", ")))
+
+	return nil
+}
@@ -0,0 +1,345 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyFromString(t *testing.T) {
+	scenarios := []struct {
+		testName      string
+		key           string
+		leader        string
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			testName: "single key",
+			key:      "a",
+			leader:   "\\",
+			expected: 'a',
+		},
+		{
+			testName: "special key",
+			key:      "<c-b>",
+			leader:   "\\",
+			expected: gocui.KeyCtrlB,
+		},
+		{
+			testName: "space",
+			key:      " ",
+			leader:   "\\",
+			expected: ' ',
+		},
+		{
+			testName: "sequence",
+			key:      "g g",
+			leader:   "\\",
+			expected: keySequence{'g', 'g'},
+		},
+		{
+			testName: "sequence with leader",
+			key:      "<leader> b",
+			leader:   "<space>",
+			expected: keySequence{gocui.KeySpace, 'b'},
+		},
+		{
+			testName: "lone leader",
+			key:      "<leader>",
+			leader:   "<c-x>",
+			expected: gocui.KeyCtrlX,
+		},
+		{
+			testName:      "leader without a leader configured",
+			key:           "<leader> b",
+			leader:        "",
+			expectedError: "Keybinding uses <leader> but no leader key is configured (keybinding.universal.leader)",
+		},
+		{
+			testName:      "unknown key in sequence",
+			key:           "g <nope>",
+			leader:        "\\",
+			expectedError: "Unrecognized key <nope> for keybinding",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			key, err := keyFromString(s.key, s.leader)
+			if s.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), s.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, key)
+		})
+	}
+}
+
+func TestGetKeybindingConflicts(t *testing.T) {
+	scenarios := []struct {
+		testName string
+		bindings []*Binding
+		expected []string
+	}{
+		{
+			testName: "different keys",
+			bindings: []*Binding{
+				{ViewName: "files", Key: 'a', Description: "one"},
+				{ViewName: "files", Key: 'b', Description: "two"},
+			},
+			expected: []string{},
+		},
+		{
+			testName: "same key in different views",
+			bindings: []*Binding{
+				{ViewName: "files", Key: 'a', Description: "one"},
+				{ViewName: "branches", Key: 'a', Description: "two"},
+			},
+			expected: []string{},
+		},
+		{
+			testName: "same key in different contexts of a view",
+			bindings: []*Binding{
+				{ViewName: "main", Contexts: []string{"staging"}, Key: 'a', Description: "one"},
+				{ViewName: "main", Contexts: []string{"merging"}, Key: 'a', Description: "two"},
+			},
+			expected: []string{},
+		},
+		{
+			testName: "same key in the same context",
+			bindings: []*Binding{
+				{ViewName: "main", Contexts: []string{"staging", "patchBuilding"}, Key: 'a', Description: "one"},
+				{ViewName: "main", Contexts: []string{"staging"}, Key: 'a', Description: "two"},
+			},
+			expected: []string{"'a' (one) and 'a' (two) in main (staging)"},
+		},
+		{
+			testName: "same action bound twice",
+			bindings: []*Binding{
+				{ViewName: "main", Key: 'o', Description: "open file"},
+				{ViewName: "main", Contexts: []string{"staging"}, Key: 'o', Description: "open file"},
+			},
+			expected: []string{},
+		},
+		{
+			testName: "sequence starting with another binding's key",
+			bindings: []*Binding{
+				{ViewName: "", Key: 'g', Description: "one"},
+				{ViewName: "", Key: keySequence{'g', 'g'}, Description: "two"},
+			},
+			expected: []string{"'g' (one) and 'g g' (two) in global"},
+		},
+		{
+			testName: "sequences sharing a prefix",
+			bindings: []*Binding{
+				{ViewName: "files", Key: keySequence{'g', 'a'}, Description: "one"},
+				{ViewName: "files", Key: keySequence{'g', 'b'}, Description: "two"},
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, getKeybindingConflicts(s.bindings))
+		})
+	}
+}
+
+func TestValidateUserConfigKeybindings(t *testing.T) {
+	scenarios := []struct {
+		testName      string
+		setup         func(userConfig *config.UserConfig)
+		expectedError string
+	}{
+		{
+			testName:      "default config",
+			setup:         func(userConfig *config.UserConfig) {},
+			expectedError: "",
+		},
+		{
+			testName: "valid sequence",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.Keybinding.Branches.RebaseBranch = "<leader> r"
+			},
+			expectedError: "",
+		},
+		{
+			testName: "leader clashes with an existing binding",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.Keybinding.Universal.Leader = "<space>"
+				userConfig.Keybinding.Branches.RebaseBranch = "<leader> r"
+			},
+			expectedError: "Conflicting keybindings",
+		},
+		{
+			testName: "invalid key",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.Keybinding.Files.CommitChanges = "<nope>"
+			},
+			expectedError: "Unrecognized key <nope>",
+		},
+		{
+			testName: "sequence for a key that must be a single key",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.Keybinding.Universal.Return = "q q"
+			},
+			expectedError: "keybinding.universal.return must be a single key",
+		},
+		{
+			testName: "context override",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.Keybinding.Contexts = map[string]config.KeybindingConfig{
+					"merging": {Main: config.KeybindingMainConfig{PickBothHunks: "B"}},
+				}
+			},
+			expectedError: "",
+		},
+		{
+			testName: "unknown context override",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.Keybinding.Contexts = map[string]config.KeybindingConfig{
+					"nope": {Main: config.KeybindingMainConfig{PickBothHunks: "B"}},
+				}
+			},
+			expectedError: "Unknown context in keybinding.contexts: nope",
+		},
+		{
+			testName: "context override introducing a conflict",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.Keybinding.Contexts = map[string]config.KeybindingConfig{
+					"merging": {Main: config.KeybindingMainConfig{PickBothHunks: "<space>"}},
+				}
+			},
+			expectedError: "Conflicting keybindings",
+		},
+		{
+			testName: "custom commands without keys and in command menus",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.CustomCommands = []config.CustomCommand{
+					{Context: "files, commits", Command: "echo keyless"},
+					{Key: "<leader> m", Context: "localBranches, remoteBranches", CommandMenu: []config.CustomCommand{
+						{Key: "a", Command: "echo a"},
+						{Command: "echo b"},
+					}},
+				}
+			},
+			expectedError: "",
+		},
+		{
+			testName: "invalid key in a command menu",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.CustomCommands = []config.CustomCommand{
+					{Key: "<leader> m", Context: "global", CommandMenu: []config.CustomCommand{
+						{Key: "<nope>", Command: "echo a"},
+					}},
+				}
+			},
+			expectedError: "Unrecognized key <nope>",
+		},
+		{
+			testName: "global custom command context combined with another",
+			setup: func(userConfig *config.UserConfig) {
+				userConfig.CustomCommands = []config.CustomCommand{
+					{Key: "X", Context: "global, files", Command: "echo x"},
+				}
+			},
+			expectedError: "the global context can't be combined with other contexts",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			gui := NewDummyGui()
+			userConfig := config.GetDefaultConfig()
+			s.setup(userConfig)
+			gui.Config.SetUserConfig(userConfig)
+
+			err := gui.validateUserConfig(userConfig)
+			if s.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), s.expectedError)
+		})
+	}
+}
+
+func TestApplyContextKeybindingOverrides(t *testing.T) {
+	gui := NewDummyGui()
+	keybindingConfig := config.GetDefaultConfig().Keybinding
+	keybindingConfig.Contexts = map[string]config.KeybindingConfig{
+		"merging": {Universal: config.KeybindingUniversalConfig{PrevBlock: "K"}},
+	}
+
+	bindings := gui.getInitialKeybindingsForConfig(keybindingConfig)
+
+	prevHunkKeysByContext := map[string]interface{}{}
+	for _, binding := range bindings {
+		if binding.ViewName != "main" || binding.Description == "" {
+			continue
+		}
+		for _, context := range binding.Contexts {
+			expectedDescription := gui.Tr.PrevHunk
+			if context == "merging" {
+				expectedDescription = gui.Tr.PrevConflict
+			}
+			if binding.Description == expectedDescription {
+				prevHunkKeysByContext[context] = binding.Key
+			}
+		}
+	}
+
+	assert.EqualValues(t, map[string]interface{}{
+		"merging":       'K',
+		"staging":       gocui.KeyArrowLeft,
+		"patchBuilding": gocui.KeyArrowLeft,
+	}, prevHunkKeysByContext)
+
+	// the field names we tag the keys with don't make it into the bindings or
+	// back into the config
+	for _, binding := range bindings {
+		_, tagged := binding.Key.(configKey)
+		assert.False(t, tagged)
+	}
+	assert.EqualValues(t, config.GetDefaultConfig().Keybinding.Universal.JumpToBlock, keybindingConfig.Universal.JumpToBlock)
+}
+
+func TestContinueKeySequence(t *testing.T) {
+	gui := NewDummyGui()
+
+	completed := false
+	pending := &pendingKeySequence{
+		keys: keySequence{'g'},
+		candidates: []*Binding{
+			{Key: keySequence{'g', 'g'}, Handler: func() error { completed = true; return nil }},
+		},
+	}
+
+	// a key which doesn't continue the sequence cancels it and does what it
+	// normally does
+	pressed := false
+	gui.pendingKeySequence = pending
+	assert.NoError(t, gui.continueKeySequence('x', func() error { pressed = true; return nil }))
+	assert.True(t, pressed)
+	assert.False(t, completed)
+	assert.Nil(t, gui.pendingKeySequence)
+
+	pressed = false
+	gui.pendingKeySequence = pending
+	assert.NoError(t, gui.continueKeySequence('g', func() error { pressed = true; return nil }))
+	assert.False(t, pressed)
+	assert.True(t, completed)
+	assert.Nil(t, gui.pendingKeySequence)
+}
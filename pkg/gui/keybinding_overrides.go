@@ -0,0 +1,239 @@
+package gui
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// CONFIG_FIELD_SEPARATOR separates a key from the name of the config field
+// it's the value of, when we tag a config's values with their field names (see
+// tagKeybindingConfig)
+const CONFIG_FIELD_SEPARATOR = "\x00"
+
+// configKey is what getKey returns for a value tagged with its field name
+type configKey struct {
+	key   interface{}
+	field string
+}
+
+// applyContextKeybindingOverrides returns our bindings for the given config
+// with the 'contexts' section of the config applied. We obtain the bindings
+// from a config whose values are tagged with their field names, so that we know
+// which field each binding's key comes from. Where a context overrides that
+// field, the binding for that context gets the new key and the original
+// binding keeps applying to its other contexts.
+func (gui *Gui) applyContextKeybindingOverrides(keybindingConfig config.KeybindingConfig) []*Binding {
+	taggedBindings := gui.getKeybindingsForConfig(tagKeybindingConfig(keybindingConfig))
+
+	contextKeys := make([]string, 0, len(keybindingConfig.Contexts))
+	for contextKey := range keybindingConfig.Contexts {
+		contextKeys = append(contextKeys, contextKey)
+	}
+	sort.Strings(contextKeys)
+
+	values := keybindingConfigValues(keybindingConfig)
+	overriddenValuesByContext := map[string]map[string]string{}
+	for _, contextKey := range contextKeys {
+		overriddenConfig := config.MergeKeybindingConfigs(keybindingConfig, keybindingConfig.Contexts[contextKey])
+		overriddenValuesByContext[contextKey] = keybindingConfigValues(overriddenConfig)
+	}
+
+	result := []*Binding{}
+	for _, taggedBinding := range taggedBindings {
+		binding := *taggedBinding
+		taggedKey, ok := binding.Key.(configKey)
+		if !ok {
+			result = append(result, &binding)
+			continue
+		}
+		binding.Key = taggedKey.key
+
+		remainingContexts := gui.contextsForBinding(&binding)
+		overridden := false
+
+		for _, contextKey := range contextKeys {
+			overriddenValue, ok := overriddenValuesByContext[contextKey][taggedKey.field]
+			if !ok || overriddenValue == values[taggedKey.field] || !utils.IncludesString(remainingContexts, contextKey) {
+				continue
+			}
+
+			newBinding := binding
+			newBinding.Key = gui.getKey(overriddenValue)
+			newBinding.Contexts = []string{contextKey}
+			result = append(result, &newBinding)
+
+			remainingContexts = excludeString(remainingContexts, contextKey)
+			overridden = true
+		}
+
+		if !overridden {
+			result = append(result, &binding)
+			continue
+		}
+
+		if len(remainingContexts) > 0 {
+			newBinding := binding
+			newBinding.Contexts = remainingContexts
+			result = append(result, &newBinding)
+		}
+	}
+
+	return result
+}
+
+// tagKeybindingConfig appends the name of each field of the config to its value
+func tagKeybindingConfig(keybindingConfig config.KeybindingConfig) config.KeybindingConfig {
+	result := keybindingConfig
+	result.Contexts = nil
+	forEachKeybindingConfigValue(reflect.ValueOf(&result).Elem(), "", func(field string, value reflect.Value) {
+		value.SetString(value.String() + CONFIG_FIELD_SEPARATOR + field)
+	})
+	return result
+}
+
+// keybindingConfigValues returns the values of the config by field name
+func keybindingConfigValues(keybindingConfig config.KeybindingConfig) map[string]string {
+	result := map[string]string{}
+	forEachKeybindingConfigValue(reflect.ValueOf(&keybindingConfig).Elem(), "", func(field string, value reflect.Value) {
+		result[field] = value.String()
+	})
+	return result
+}
+
+// forEachKeybindingConfigValue calls f with each of the keys in the config,
+// named like 'Universal.Quit' or 'Universal.JumpToBlock[0]'. The slices are
+// copied first, so that f can change the keys without touching the config they
+// came from.
+func forEachKeybindingConfigValue(value reflect.Value, field string, f func(string, reflect.Value)) {
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			name := value.Type().Field(i).Name
+			if name == "Contexts" {
+				continue
+			}
+			if field != "" {
+				name = field + "." + name
+			}
+			forEachKeybindingConfigValue(value.Field(i), name, f)
+		}
+	case reflect.String:
+		f(field, value)
+	case reflect.Slice:
+		elems := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(elems, value)
+		value.Set(elems)
+		for i := 0; i < elems.Len(); i++ {
+			forEachKeybindingConfigValue(elems.Index(i), fmt.Sprintf("%s[%d]", field, i), f)
+		}
+	}
+}
+
+// contextsForBinding returns the contexts in which a binding applies. A view
+// binding without contexts applies to all contexts of the view.
+func (gui *Gui) contextsForBinding(binding *Binding) []string {
+	if binding.ViewName == "" {
+		return nil
+	}
+
+	if len(binding.Contexts) > 0 {
+		return binding.Contexts
+	}
+
+	contexts := []string{}
+	for _, context := range gui.allContexts() {
+		if context.GetViewName() == binding.ViewName {
+			contexts = append(contexts, string(context.GetKey()))
+		}
+	}
+	return contexts
+}
+
+// getKeybindingConflicts returns a description of each pair of bindings where
+// one of the two could never be reached, because they share a view and a
+// context and either have the same key, or one's key sequence starts with the
+// other's.
+func getKeybindingConflicts(bindings []*Binding) []string {
+	conflicts := []string{}
+
+	for i, binding := range bindings {
+		for _, other := range bindings[i+1:] {
+			if !bindingsConflict(binding, other) {
+				continue
+			}
+
+			conflicts = append(conflicts, fmt.Sprintf(
+				"'%s' (%s) and '%s' (%s) in %s",
+				GetKeyDisplay(binding.Key), binding.Description,
+				GetKeyDisplay(other.Key), other.Description,
+				bindingScopeDisplay(binding, other),
+			))
+		}
+	}
+
+	return conflicts
+}
+
+func bindingsConflict(a *Binding, b *Binding) bool {
+	if a.ViewName != b.ViewName || a.Modifier != b.Modifier {
+		return false
+	}
+
+	// bindings without a description (e.g. navigation aliases) are set up by us,
+	// not by the user
+	if a.Description == "" || b.Description == "" {
+		return false
+	}
+
+	if len(a.Contexts) > 0 && len(b.Contexts) > 0 && !utils.StringArraysOverlap(a.Contexts, b.Contexts) {
+		return false
+	}
+
+	// the same action bound twice is harmless
+	if a.Description == b.Description {
+		return false
+	}
+
+	aSequence := asKeySequence(a.Key)
+	bSequence := asKeySequence(b.Key)
+
+	return aSequence.hasPrefix(bSequence) || bSequence.hasPrefix(aSequence)
+}
+
+func bindingScopeDisplay(a *Binding, b *Binding) string {
+	viewName := a.ViewName
+	if viewName == "" {
+		viewName = "global"
+	}
+
+	contexts := []string{}
+	for _, context := range a.Contexts {
+		if len(b.Contexts) == 0 || utils.IncludesString(b.Contexts, context) {
+			contexts = append(contexts, context)
+		}
+	}
+	if len(a.Contexts) == 0 {
+		contexts = b.Contexts
+	}
+
+	if len(contexts) == 0 {
+		return viewName
+	}
+
+	return fmt.Sprintf("%s (%s)", viewName, strings.Join(contexts, ", "))
+}
+
+func excludeString(list []string, str string) []string {
+	result := []string{}
+	for _, item := range list {
+		if item != str {
+			result = append(result, item)
+		}
+	}
+	return result
+}
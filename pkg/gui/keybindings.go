@@ -1,13 +1,16 @@
 package gui
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"unicode/utf8"
 
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/constants"
 )
 
@@ -173,6 +176,8 @@ func GetKeyDisplay(key interface{}) string {
 	keyInt := 0
 
 	switch key := key.(type) {
+	case keySequence:
+		return key.display()
 	case rune:
 		keyInt = int(key)
 	case gocui.Key:
@@ -187,25 +192,58 @@ func GetKeyDisplay(key interface{}) string {
 }
 
 func (gui *Gui) getKey(key string) interface{} {
+	if i := strings.Index(key, CONFIG_FIELD_SEPARATOR); i != -1 {
+		return configKey{key: gui.getKey(key[:i]), field: key[i+len(CONFIG_FIELD_SEPARATOR):]}
+	}
+
+	// we go via gui.Config rather than gui.UserConfig so that we use the leader
+	// of a config which we're in the middle of validating
+	binding, err := keyFromString(key, gui.Config.GetUserConfig().Keybinding.Universal.Leader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return binding
+}
+
+// keyFromString parses a key as written in the user config. Unlike getKey, it
+// returns an error rather than exiting, so that we can validate a config
+// before we start using it. Several space-separated keys (e.g. 'g g' or
+// '<leader> b') are returned as a keySequence.
+func keyFromString(key string, leader string) (interface{}, error) {
+	keys := strings.Fields(key)
+	if len(keys) > 1 || (len(keys) == 1 && strings.ToLower(keys[0]) == LEADER_KEY) {
+		return keySequenceFromStrings(keys, leader)
+	}
+
 	runeCount := utf8.RuneCountInString(key)
 	if runeCount > 1 {
 		binding := keymap[strings.ToLower(key)]
 		if binding == nil {
-			log.Fatalf("Unrecognized key %s for keybinding. For permitted values see %s", strings.ToLower(key), constants.Links.Docs.CustomKeybindings)
-		} else {
-			return binding
+			return nil, fmt.Errorf("Unrecognized key %s for keybinding. For permitted values see %s", strings.ToLower(key), constants.Links.Docs.CustomKeybindings)
 		}
+		return binding, nil
 	} else if runeCount == 1 {
-		return []rune(key)[0]
+		return []rune(key)[0], nil
 	}
-	log.Fatal("Key empty for keybinding: " + strings.ToLower(key))
-	return nil
+	return nil, errors.New("Key empty for keybinding: " + strings.ToLower(key))
 }
 
 // GetInitialKeybindings is a function.
 func (gui *Gui) GetInitialKeybindings() []*Binding {
-	config := gui.UserConfig.Keybinding
+	return gui.getInitialKeybindingsForConfig(gui.UserConfig.Keybinding)
+}
+
+// getInitialKeybindingsForConfig returns our bindings for the given config,
+// including any per-context overrides
+func (gui *Gui) getInitialKeybindingsForConfig(keybindingConfig config.KeybindingConfig) []*Binding {
+	if len(keybindingConfig.Contexts) == 0 {
+		return gui.getKeybindingsForConfig(keybindingConfig)
+	}
+
+	return gui.applyContextKeybindingOverrides(keybindingConfig)
+}
 
+func (gui *Gui) getKeybindingsForConfig(config config.KeybindingConfig) []*Binding {
 	bindings := []*Binding{
 		{
 			ViewName: "",
@@ -330,15 +368,21 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		{
 			ViewName:    "",
 			Key:         gui.getKey(config.Universal.Undo),
-			Handler:     gui.reflogUndo,
+			Handler:     gui.handleUndo,
 			Description: gui.Tr.LcUndoReflog,
 		},
 		{
 			ViewName:    "",
 			Key:         gui.getKey(config.Universal.Redo),
-			Handler:     gui.reflogRedo,
+			Handler:     gui.handleRedo,
 			Description: gui.Tr.LcRedoReflog,
 		},
+		{
+			ViewName:    "",
+			Key:         gui.getKey(config.Universal.UndoHistory),
+			Handler:     gui.handleCreateUndoHistoryMenu,
+			Description: gui.Tr.LcViewUndoHistory,
+		},
 		{
 			ViewName:    "status",
 			Key:         gui.getKey(config.Universal.Edit),
@@ -383,7 +427,7 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		},
 		{
 			ViewName:    "files",
-			Key:         gui.getKey("<c-b>"),
+			Key:         gui.getKey(config.Files.OpenStatusFilter),
 			Handler:     gui.handleStatusFilterPressed,
 			Description: gui.Tr.LcCommitFileFilter,
 		},
@@ -537,6 +581,13 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:     gui.handleOpenMergeTool,
 			Description: gui.Tr.LcOpenMergeTool,
 		},
+		{
+			ViewName:    "files",
+			Contexts:    []string{string(FILES_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Files.AutoResolveConflicts),
+			Handler:     gui.handleAutoResolveAllTrivialConflicts,
+			Description: gui.Tr.LcAutoResolveAllTrivialConflicts,
+		},
 		{
 			ViewName:    "branches",
 			Contexts:    []string{string(LOCAL_BRANCHES_CONTEXT_KEY)},
@@ -652,6 +703,34 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:     gui.handleSwitchToSubCommits,
 			Description: gui.Tr.LcViewCommits,
 		},
+		{
+			ViewName:    "branches",
+			Contexts:    []string{string(PULL_REQUESTS_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.Select),
+			Handler:     gui.withSelectedPullRequest(gui.handleCheckoutPullRequest),
+			Description: gui.Tr.LcCheckoutPullRequest,
+		},
+		{
+			ViewName:    "branches",
+			Contexts:    []string{string(PULL_REQUESTS_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Branches.DiffPullRequest),
+			Handler:     gui.withSelectedPullRequest(gui.handleDiffPullRequest),
+			Description: gui.Tr.LcDiffPullRequest,
+		},
+		{
+			ViewName:    "branches",
+			Contexts:    []string{string(PULL_REQUESTS_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Branches.FetchRemote),
+			Handler:     gui.handleFetchPullRequests,
+			Description: gui.Tr.LcFetchPullRequests,
+		},
+		{
+			ViewName:    "branches",
+			Contexts:    []string{string(PULL_REQUESTS_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.GoInto),
+			Handler:     gui.handleSwitchToSubCommits,
+			Description: gui.Tr.LcViewCommits,
+		},
 		{
 			ViewName:    "branches",
 			Contexts:    []string{string(TAGS_CONTEXT_KEY)},
@@ -908,6 +987,45 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:     gui.handleOpenCommitInBrowser,
 			Description: gui.Tr.LcOpenCommitInBrowser,
 		},
+		{
+			ViewName:    "commits",
+			Contexts:    []string{string(BRANCH_COMMITS_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Commits.OpenLinkedIssue),
+			Handler:     gui.handleOpenLinkedIssue,
+			Description: gui.Tr.LcOpenLinkedIssue,
+			OpensMenu:   true,
+		},
+		{
+			ViewName:    "files",
+			Contexts:    []string{string(FILES_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.OpenInBrowserMenu),
+			Handler:     gui.handleCreateOpenInBrowserMenu,
+			Description: gui.Tr.LcOpenInBrowserMenu,
+			OpensMenu:   true,
+		},
+		{
+			ViewName:    "commitFiles",
+			Key:         gui.getKey(config.Universal.OpenInBrowserMenu),
+			Handler:     gui.handleCreateOpenInBrowserMenu,
+			Description: gui.Tr.LcOpenInBrowserMenu,
+			OpensMenu:   true,
+		},
+		{
+			ViewName:    "branches",
+			Contexts:    []string{string(LOCAL_BRANCHES_CONTEXT_KEY), string(TAGS_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.OpenInBrowserMenu),
+			Handler:     gui.handleCreateOpenInBrowserMenu,
+			Description: gui.Tr.LcOpenInBrowserMenu,
+			OpensMenu:   true,
+		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_PATCH_BUILDING_CONTEXT_KEY), string(MAIN_STAGING_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.OpenInBrowserMenu),
+			Handler:     gui.handleCreateOpenInBrowserMenu,
+			Description: gui.Tr.LcOpenInBrowserMenu,
+			OpensMenu:   true,
+		},
 		{
 			ViewName:    "commits",
 			Contexts:    []string{string(BRANCH_COMMITS_CONTEXT_KEY)},
@@ -1341,6 +1459,20 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:     gui.handleLineByLineEdit,
 			Description: gui.Tr.LcEditFile,
 		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_STAGING_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Main.EditHunk),
+			Handler:     gui.handleEditHunk,
+			Description: gui.Tr.LcEditHunk,
+		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_STAGING_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Main.SplitHunk),
+			Handler:     gui.handleSplitHunk,
+			Description: gui.Tr.LcSplitHunk,
+		},
 		{
 			ViewName:    "main",
 			Contexts:    []string{string(MAIN_STAGING_CONTEXT_KEY)},
@@ -1512,6 +1644,55 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:     gui.handlePickAllHunks,
 			Description: gui.Tr.PickAllHunks,
 		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_MERGING_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Main.ToggleThreeWayView),
+			Handler:     gui.handleToggleThreeWayMergeView,
+			Description: gui.Tr.ToggleThreeWayMergeView,
+		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_MERGING_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Main.AutoResolveConflicts),
+			Handler:     gui.handleAutoResolveTrivialConflicts,
+			Description: gui.Tr.LcAutoResolveTrivialConflicts,
+		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_MERGING_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.Confirm),
+			Handler:     gui.handleResolveWithPickedLines,
+			Description: gui.Tr.LcResolveWithPickedLines,
+		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_MERGING_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.Edit),
+			Handler:     gui.handleMergeConflictEdit,
+			Description: gui.Tr.LcEditFile,
+		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_MERGING_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Main.EditHunk),
+			Handler:     gui.handleEditMergeResult,
+			Description: gui.Tr.LcEditMergeResult,
+		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_MERGE_RESULT_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.SubmitEditorText),
+			Handler:     gui.handleSaveMergeResult,
+			Description: gui.Tr.LcSaveMergeResult,
+		},
+		{
+			ViewName:    "main",
+			Contexts:    []string{string(MAIN_MERGE_RESULT_CONTEXT_KEY)},
+			Key:         gui.getKey(config.Universal.Return),
+			Handler:     gui.handleCancelMergeResult,
+			Description: gui.Tr.LcCancel,
+		},
 		{
 			ViewName:    "main",
 			Contexts:    []string{string(MAIN_MERGING_CONTEXT_KEY)},
@@ -1771,6 +1952,12 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:     gui.toggleWhitespaceInDiffView,
 			Description: gui.Tr.ToggleWhitespaceInDiffView,
 		},
+		{
+			ViewName:    "",
+			Key:         gui.getKey(config.Universal.ToggleSplitDiffView),
+			Handler:     gui.toggleSplitDiffView,
+			Description: gui.Tr.ToggleSplitDiffView,
+		},
 		{
 			ViewName:    "",
 			Key:         gui.getKey(config.Universal.IncreaseContextInDiffView),
@@ -1867,7 +2054,7 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		}
 	}
 
-	for viewName := range gui.State.Contexts.initialViewTabContextMap() {
+	for _, viewName := range gui.viewNamesWithTabs() {
 		bindings = append(bindings, []*Binding{
 			{
 				ViewName:    viewName,
@@ -1886,22 +2073,62 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		}...)
 	}
 
-	bindings = append(bindings, gui.getListContextKeyBindings()...)
+	bindings = append(bindings, gui.getListContextKeyBindings(config)...)
 
 	return bindings
 }
 
-func (gui *Gui) keybindings() error {
-	bindings := gui.GetCustomCommandKeybindings()
+func (gui *Gui) getAllKeybindings() []*Binding {
+	return gui.getAllKeybindingsForUserConfig(gui.UserConfig)
+}
+
+func (gui *Gui) getAllKeybindingsForUserConfig(userConfig *config.UserConfig) []*Binding {
+	bindings := gui.getCustomCommandKeybindings(userConfig.CustomCommands)
+
+	return append(bindings, gui.getInitialKeybindingsForConfig(userConfig.Keybinding)...)
+}
 
-	bindings = append(bindings, gui.GetInitialKeybindings()...)
+func (gui *Gui) setKeybindings(bindings []*Binding) error {
+	gui.keySequenceBindings = getKeySequenceBindings(bindings)
 
-	for _, binding := range bindings {
-		if err := gui.g.SetKeybinding(binding.ViewName, binding.Contexts, binding.Key, binding.Modifier, gui.wrappedHandler(binding.Handler)); err != nil {
+	for _, binding := range gui.toGocuiBindings(bindings) {
+		if err := gui.g.SetKeybinding(binding.ViewName, binding.Contexts, binding.Key, binding.Modifier, gui.keybindingHandler(binding.Key, binding.Handler)); err != nil {
 			return err
 		}
 	}
 
+	return nil
+}
+
+// resetKeybindings swaps out the given bindings (typically obtained before the
+// user config was reloaded) for the bindings of the current user config.
+func (gui *Gui) resetKeybindings(oldBindings []*Binding) error {
+	// the open menu's item keys may clash with the new bindings
+	gui.clearMenuItemKeybindings()
+
+	for _, binding := range gui.toGocuiBindings(oldBindings) {
+		// swallowing the error because it only tells us the binding wasn't there
+		_ = gui.g.DeleteKeybinding(binding.ViewName, binding.Key, binding.Modifier)
+	}
+
+	return gui.setKeybindings(gui.getAllKeybindings())
+}
+
+// viewNamesWithTabs is sorted so that we always obtain our bindings in the same order
+func (gui *Gui) viewNamesWithTabs() []string {
+	viewNames := []string{}
+	for viewName := range gui.State.Contexts.initialViewTabContextMap() {
+		viewNames = append(viewNames, viewName)
+	}
+	sort.Strings(viewNames)
+	return viewNames
+}
+
+func (gui *Gui) keybindings() error {
+	if err := gui.setKeybindings(gui.getAllKeybindings()); err != nil {
+		return err
+	}
+
 	for viewName := range gui.State.Contexts.initialViewTabContextMap() {
 		viewName := viewName
 		tabClickCallback := func(tabIndex int) error { return gui.onViewTabClick(viewName, tabIndex) }
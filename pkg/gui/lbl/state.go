@@ -22,8 +22,8 @@ const (
 	HUNK
 )
 
-func NewState(diff string, selectedLineIdx int, oldState *State, log *logrus.Entry) *State {
-	patchParser := patch.NewPatchParser(log, diff)
+func NewState(diff string, selectedLineIdx int, oldState *State, log *logrus.Entry, patchParserOpts patch.PatchParserOpts) *State {
+	patchParser := patch.NewPatchParser(log, diff, patchParserOpts)
 
 	if len(patchParser.StageableLines) == 0 {
 		return nil
@@ -167,6 +167,23 @@ func (s *State) SelectedRange() (int, int) {
 	}
 }
 
+// SelectedLineNumbers returns the line numbers in the new version of the file
+// of the first and last selected lines
+func (s *State) SelectedLineNumbers() (int, int) {
+	first, last := s.SelectedRange()
+	return s.lineNumberOfLine(first), s.lineNumberOfLine(last)
+}
+
+func (s *State) lineNumberOfLine(idx int) int {
+	// the last hunk ends with the empty line that follows the diff's final
+	// newline, which isn't a line of the file
+	for idx > 0 && idx < len(s.patchParser.PatchLines) && s.patchParser.PatchLines[idx].Content == "" {
+		idx--
+	}
+
+	return s.patchParser.GetHunkContainingLine(idx, 0).LineNumberOfLine(idx)
+}
+
 func (s *State) CurrentLineNumber() int {
 	return s.CurrentHunk().LineNumberOfLine(s.selectedLineIdx)
 }
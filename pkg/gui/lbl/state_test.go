@@ -0,0 +1,74 @@
+package lbl
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/patch"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const selectedLineNumbersDiff = `diff --git a/file b/file
+index 1111111..2222222 100644
+--- a/file
++++ b/file
+@@ -10,4 +10,5 @@
+ a
+-b
++c
++d
+ e
+`
+
+func TestSelectedLineNumbers(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		selectRange   func(state *State)
+		expectedStart int
+		expectedEnd   int
+	}{
+		{
+			name:          "deleted line",
+			selectRange:   func(state *State) {},
+			expectedStart: 11,
+			expectedEnd:   11,
+		},
+		{
+			name: "added line",
+			selectRange: func(state *State) {
+				state.SelectLine(8)
+			},
+			expectedStart: 12,
+			expectedEnd:   12,
+		},
+		{
+			name: "range",
+			selectRange: func(state *State) {
+				state.SelectNewLineForRange(6)
+				state.SelectLine(8)
+			},
+			expectedStart: 11,
+			expectedEnd:   12,
+		},
+		{
+			name: "hunk",
+			selectRange: func(state *State) {
+				state.ToggleSelectHunk()
+			},
+			expectedStart: 10,
+			expectedEnd:   13,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			state := NewState(selectedLineNumbersDiff, -1, nil, utils.NewDummyLog(), patch.PatchParserOpts{})
+			s.selectRange(state)
+
+			start, end := state.SelectedLineNumbers()
+			assert.Equal(t, s.expectedStart, start)
+			assert.Equal(t, s.expectedEnd, end)
+		})
+	}
+}
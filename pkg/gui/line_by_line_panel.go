@@ -25,7 +25,7 @@ func (gui *Gui) refreshLineByLinePanel(diff string, secondaryDiff string, second
 		oldState = gui.State.Panels.LineByLine.State
 	}
 
-	state := lbl.NewState(diff, selectedLineIdx, oldState, gui.Log)
+	state := lbl.NewState(diff, selectedLineIdx, oldState, gui.Log, gui.patchParserOpts("main"))
 	if state == nil {
 		return true, nil
 	}
@@ -46,7 +46,7 @@ func (gui *Gui) refreshLineByLinePanel(diff string, secondaryDiff string, second
 	gui.Views.Secondary.Highlight = true
 	gui.Views.Secondary.Wrap = false
 
-	secondaryPatchParser := patch.NewPatchParser(gui.Log, secondaryDiff)
+	secondaryPatchParser := patch.NewPatchParser(gui.Log, secondaryDiff, gui.patchParserOpts("secondary"))
 
 	gui.setViewContent(gui.Views.Secondary, secondaryPatchParser.Render(-1, -1, nil))
 
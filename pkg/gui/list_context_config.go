@@ -5,6 +5,7 @@ import (
 
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/gui/presentation"
 	"github.com/jesseduffield/lazygit/pkg/gui/style"
 )
@@ -69,7 +70,7 @@ func (gui *Gui) branchesListContext() IListContext {
 		OnRenderToMain:  OnFocusWrapper(gui.branchesRenderToMain),
 		Gui:             gui,
 		GetDisplayStrings: func(startIdx int, length int) [][]string {
-			return presentation.GetBranchListDisplayStrings(gui.State.Branches, gui.State.ScreenMode != SCREEN_NORMAL, gui.State.Modes.Diffing.Ref)
+			return presentation.GetBranchListDisplayStrings(gui.State.Branches, gui.State.ScreenMode != SCREEN_NORMAL, gui.State.Modes.Diffing.Ref, pullRequestsByBranch(gui.State.Branches, gui.State.PullRequests))
 		},
 		SelectedItem: func() (ListItem, bool) {
 			item := gui.getSelectedBranch()
@@ -145,6 +146,29 @@ func (gui *Gui) tagsListContext() IListContext {
 	}
 }
 
+func (gui *Gui) pullRequestsListContext() IListContext {
+	return &ListContext{
+		BasicContext: &BasicContext{
+			ViewName:   "branches",
+			WindowName: "branches",
+			Key:        PULL_REQUESTS_CONTEXT_KEY,
+			Kind:       SIDE_CONTEXT,
+		},
+		GetItemsLength:  func() int { return len(gui.State.PullRequests) },
+		OnGetPanelState: func() IListPanelState { return gui.State.Panels.PullRequests },
+		OnFocus:         OnFocusWrapper(gui.onPullRequestsFocus),
+		OnRenderToMain:  OnFocusWrapper(gui.pullRequestsRenderToMain),
+		Gui:             gui,
+		GetDisplayStrings: func(startIdx int, length int) [][]string {
+			return presentation.GetPullRequestListDisplayStrings(gui.State.PullRequests, gui.State.Modes.Diffing.Ref)
+		},
+		SelectedItem: func() (ListItem, bool) {
+			item := gui.getSelectedPullRequest()
+			return item, item != nil
+		},
+	}
+}
+
 func (gui *Gui) branchCommitsListContext() IListContext {
 	parseEmoji := gui.UserConfig.Git.ParseEmoji
 	return &ListContext{
@@ -158,7 +182,7 @@ func (gui *Gui) branchCommitsListContext() IListContext {
 		OnGetPanelState:     func() IListPanelState { return gui.State.Panels.Commits },
 		OnFocus:             OnFocusWrapper(gui.onCommitFocus),
 		OnRenderToMain:      OnFocusWrapper(gui.branchCommitsRenderToMain),
-		OnClickSelectedItem: gui.handleViewCommitFiles,
+		OnClickSelectedItem: gui.handleClickSelectedCommit,
 		Gui:                 gui,
 		GetDisplayStrings: func(startIdx int, length int) [][]string {
 			selectedCommitSha := ""
@@ -179,6 +203,7 @@ func (gui *Gui) branchCommitsListContext() IListContext {
 				length,
 				gui.shouldShowGraph(),
 				gui.State.BisectInfo,
+				gui.getLinkifier(),
 			)
 		},
 		SelectedItem: func() (ListItem, bool) {
@@ -221,6 +246,7 @@ func (gui *Gui) subCommitsListContext() IListContext {
 				length,
 				gui.shouldShowGraph(),
 				git_commands.NewNullBisectInfo(),
+				gui.getLinkifier(),
 			)
 		},
 		SelectedItem: func() (ListItem, bool) {
@@ -381,6 +407,7 @@ func (gui *Gui) getListContexts() []IListContext {
 		gui.State.Contexts.Remotes,
 		gui.State.Contexts.RemoteBranches,
 		gui.State.Contexts.Tags,
+		gui.State.Contexts.PullRequests,
 		gui.State.Contexts.BranchCommits,
 		gui.State.Contexts.ReflogCommits,
 		gui.State.Contexts.SubCommits,
@@ -391,11 +418,9 @@ func (gui *Gui) getListContexts() []IListContext {
 	}
 }
 
-func (gui *Gui) getListContextKeyBindings() []*Binding {
+func (gui *Gui) getListContextKeyBindings(keybindingConfig config.KeybindingConfig) []*Binding {
 	bindings := make([]*Binding, 0)
 
-	keybindingConfig := gui.UserConfig.Keybinding
-
 	for _, listContext := range gui.getListContexts() {
 		listContext := listContext
 
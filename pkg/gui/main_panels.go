@@ -31,6 +31,7 @@ const (
 	RENDER_STRING_WITHOUT_SCROLL
 	RUN_COMMAND
 	RUN_PTY
+	RUN_DIFF
 )
 
 type updateTask interface {
@@ -39,6 +40,9 @@ type updateTask interface {
 
 type renderStringTask struct {
 	str string
+	// rendering again with the same key keeps the view's scroll position. The
+	// key defaults to the string itself.
+	key string
 }
 
 func (t *renderStringTask) GetKind() TaskKind {
@@ -49,6 +53,10 @@ func NewRenderStringTask(str string) *renderStringTask {
 	return &renderStringTask{str: str}
 }
 
+func NewRenderStringTaskWithKey(str string, key string) *renderStringTask {
+	return &renderStringTask{str: str, key: key}
+}
+
 type renderStringWithoutScrollTask struct {
 	str string
 }
@@ -91,6 +99,19 @@ func NewRunPtyTask(cmd *exec.Cmd) *runPtyTask {
 	return &runPtyTask{cmd: cmd}
 }
 
+type runDiffTask struct {
+	cmd        *exec.Cmd
+	sideBySide bool
+}
+
+func (t *runDiffTask) GetKind() TaskKind {
+	return RUN_DIFF
+}
+
+func NewRunDiffTask(cmd *exec.Cmd, sideBySide bool) *runDiffTask {
+	return &runDiffTask{cmd: cmd, sideBySide: sideBySide}
+}
+
 // currently unused
 // func (gui *Gui) createRunPtyTaskWithPrefix(cmd *exec.Cmd, prefix string) *runPtyTask {
 // 	return &runPtyTask{cmd: cmd, prefix: prefix}
@@ -100,6 +121,9 @@ func (gui *Gui) runTaskForView(view *gocui.View, task updateTask) error {
 	switch task.GetKind() {
 	case RENDER_STRING:
 		specificTask := task.(*renderStringTask)
+		if specificTask.key != "" {
+			return gui.newStringTaskWithKey(view, specificTask.str, specificTask.key)
+		}
 		return gui.newStringTask(view, specificTask.str)
 
 	case RENDER_STRING_WITHOUT_SCROLL:
@@ -113,6 +137,10 @@ func (gui *Gui) runTaskForView(view *gocui.View, task updateTask) error {
 	case RUN_PTY:
 		specificTask := task.(*runPtyTask)
 		return gui.newPtyTask(view, specificTask.cmd, specificTask.prefix)
+
+	case RUN_DIFF:
+		specificTask := task.(*runDiffTask)
+		return gui.newRunDiffTask(view, specificTask.cmd, specificTask.sideBySide)
 	}
 
 	return nil
@@ -3,8 +3,10 @@ package gui
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
+	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
@@ -15,6 +17,8 @@ type menuItem struct {
 	onPress        func() error
 	// only applies when displayString is used
 	opensMenu bool
+	// if set, pressing this key while the menu is open presses the item
+	key interface{}
 }
 
 // every item in a list context needs an ID
@@ -88,18 +92,70 @@ func (gui *Gui) createMenu(title string, items []*menuItem, createMenuOptions cr
 	menuView.SetContent(list)
 	gui.State.Panels.Menu.SelectedLineIdx = 0
 
+	if err := gui.setMenuItemKeybindings(items); err != nil {
+		return err
+	}
+
 	return gui.pushContext(gui.State.Contexts.Menu)
 }
 
 func (gui *Gui) onMenuPress() error {
-	selectedLine := gui.State.Panels.Menu.SelectedLineIdx
+	return gui.pressMenuItem(gui.State.Panels.Menu.SelectedLineIdx)
+}
+
+func (gui *Gui) pressMenuItem(idx int) error {
+	item := gui.State.MenuItems[idx]
 	if err := gui.returnFromContext(); err != nil {
 		return err
 	}
 
-	if err := gui.State.MenuItems[selectedLine].onPress(); err != nil {
+	if err := item.onPress(); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// setMenuItemKeybindings binds the keys of the menu's items, in place of those
+// of the previous menu. An item's key can't take over one of the menu's own
+// keys, like the one for closing it.
+func (gui *Gui) setMenuItemKeybindings(items []*menuItem) error {
+	gui.clearMenuItemKeybindings()
+
+	menuKeys := []interface{}{}
+	for _, binding := range gui.getAllKeybindings() {
+		if binding.ViewName == "menu" {
+			menuKeys = append(menuKeys, binding.Key)
+		}
+	}
+
+outer:
+	for i, item := range items {
+		if item.key == nil {
+			continue
+		}
+		for _, key := range append(menuKeys, gui.menuItemKeys...) {
+			if reflect.DeepEqual(key, item.key) {
+				continue outer
+			}
+		}
+
+		idx := i
+		if err := gui.g.SetKeybinding("menu", nil, item.key, gocui.ModNone, gui.wrappedHandler(func() error {
+			return gui.pressMenuItem(idx)
+		})); err != nil {
+			return err
+		}
+		gui.menuItemKeys = append(gui.menuItemKeys, item.key)
+	}
+
+	return nil
+}
+
+func (gui *Gui) clearMenuItemKeybindings() {
+	for _, key := range gui.menuItemKeys {
+		// swallowing the error because it only tells us the binding wasn't there
+		_ = gui.g.DeleteKeybinding("menu", key, gocui.ModNone)
+	}
+	gui.menuItemKeys = nil
+}
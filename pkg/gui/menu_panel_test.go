@@ -0,0 +1,31 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMenuItemKeybindings(t *testing.T) {
+	gui := NewDummyGui()
+	gui.g = &gocui.Gui{}
+
+	noop := func() error { return nil }
+	items := []*menuItem{
+		{displayString: "push", key: 'p', onPress: noop},
+		{displayString: "pull", key: 'p', onPress: noop},
+		// taken by the menu itself
+		{displayString: "confirm", key: gocui.KeyEnter, onPress: noop},
+		{displayString: "no key", onPress: noop},
+	}
+	assert.NoError(t, gui.setMenuItemKeybindings(items))
+	assert.EqualValues(t, []interface{}{'p'}, gui.menuItemKeys)
+
+	// the next menu's keys replace this one's
+	assert.NoError(t, gui.setMenuItemKeybindings([]*menuItem{{displayString: "fetch", key: 'f', onPress: noop}}))
+	assert.EqualValues(t, []interface{}{'f'}, gui.menuItemKeys)
+	// deleting a keybinding only fails if it isn't there
+	assert.Error(t, gui.g.DeleteKeybinding("menu", 'p', gocui.ModNone))
+	assert.NoError(t, gui.g.DeleteKeybinding("menu", 'f', gocui.ModNone))
+}
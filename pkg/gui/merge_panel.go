@@ -14,7 +14,11 @@ import (
 func (gui *Gui) handleSelectPrevConflictHunk() error {
 	return gui.withMergeConflictLock(func() error {
 		gui.takeOverMergeConflictScrolling()
-		gui.State.Panels.Merging.SelectPrevConflictHunk()
+		if gui.State.Panels.Merging.ThreeWay() {
+			gui.State.Panels.Merging.SelectPrevLine()
+		} else {
+			gui.State.Panels.Merging.SelectPrevConflictHunk()
+		}
 		return gui.renderConflictsWithFocus()
 	})
 }
@@ -22,7 +26,11 @@ func (gui *Gui) handleSelectPrevConflictHunk() error {
 func (gui *Gui) handleSelectNextConflictHunk() error {
 	return gui.withMergeConflictLock(func() error {
 		gui.takeOverMergeConflictScrolling()
-		gui.State.Panels.Merging.SelectNextConflictHunk()
+		if gui.State.Panels.Merging.ThreeWay() {
+			gui.State.Panels.Merging.SelectNextLine()
+		} else {
+			gui.State.Panels.Merging.SelectNextConflictHunk()
+		}
 		return gui.renderConflictsWithFocus()
 	})
 }
@@ -62,6 +70,12 @@ func (gui *Gui) handleMergeConflictUndo() error {
 
 func (gui *Gui) handlePickHunk() error {
 	return gui.withMergeConflictLock(func() error {
+		if gui.State.Panels.Merging.ThreeWay() {
+			gui.State.Panels.Merging.TogglePickedLine()
+			gui.State.Panels.Merging.SelectNextLine()
+			return gui.renderConflictsWithFocus()
+		}
+
 		ok, err := gui.resolveConflict(gui.State.Panels.Merging.Selection())
 		if err != nil {
 			return err
@@ -98,6 +112,258 @@ func (gui *Gui) handlePickAllHunks() error {
 	})
 }
 
+func (gui *Gui) handleResolveWithPickedLines() error {
+	return gui.withMergeConflictLock(func() error {
+		state := gui.State.Panels.Merging
+		if !state.ThreeWay() {
+			return nil
+		}
+
+		// with nothing picked we'd drop both sides of the conflict, which is
+		// more likely to be a slip than what the user wants
+		if state.PickedLineCount() == 0 {
+			return gui.ask(askOpts{
+				title:  gui.Tr.NoLinesPickedTitle,
+				prompt: gui.Tr.NoLinesPickedPrompt,
+				handleConfirm: func() error {
+					return gui.withMergeConflictLock(gui.resolveWithPickedLines)
+				},
+			})
+		}
+
+		return gui.resolveWithPickedLines()
+	})
+}
+
+func (gui *Gui) resolveWithPickedLines() error {
+	state := gui.State.Panels.Merging
+
+	gui.takeOverMergeConflictScrolling()
+
+	ok, content, err := state.ContentAfterPickingLines()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	gui.logAction("Resolve merge conflict")
+	gui.logCommand(fmt.Sprintf("Picking %d lines", state.PickedLineCount()), false)
+	return gui.writeMergeResult(content)
+}
+
+// writeMergeResult saves the file with some of its conflicts resolved, keeping
+// the previous content so that the resolution can be undone
+func (gui *Gui) writeMergeResult(content string) error {
+	state := gui.State.Panels.Merging
+
+	state.PushContent(content)
+	if err := ioutil.WriteFile(state.GetPath(), []byte(content), 0644); err != nil {
+		return err
+	}
+
+	if state.AllConflictsResolved() {
+		return gui.onLastConflictResolved()
+	}
+
+	return gui.renderConflictsWithFocus()
+}
+
+// handleEditMergeResult lets the user edit the file being resolved right there
+// in the main view, starting at the selected conflict
+func (gui *Gui) handleEditMergeResult() error {
+	return gui.withMergeConflictLock(func() error {
+		state := gui.State.Panels.Merging
+		if !state.ThreeWay() {
+			return nil
+		}
+
+		view := gui.Views.Main
+		view.Title = gui.Tr.MergeResultTitle
+		view.Editable = true
+		view.Editor = gocui.EditorFunc(gui.mergeResultEditor)
+		view.TextArea.Clear()
+		view.TextArea.TypeString(state.GetContent())
+		view.TextArea.SetCursor2D(0, state.GetConflictMiddle())
+		view.RenderTextArea()
+
+		return gui.pushContext(gui.State.Contexts.MergeResult)
+	})
+}
+
+func (gui *Gui) handleSaveMergeResult() error {
+	content := gui.Views.Main.TextArea.GetContent()
+
+	// returning to the merging context renders the file as it was, so we do
+	// that before taking the lock and then render the result on top
+	if err := gui.returnFromContext(); err != nil {
+		return err
+	}
+
+	return gui.withMergeConflictLock(func() error {
+		gui.takeOverMergeConflictScrolling()
+
+		gui.logAction("Resolve merge conflict")
+		gui.logCommand("Editing merge result", false)
+		return gui.writeMergeResult(content)
+	})
+}
+
+func (gui *Gui) handleCancelMergeResult() error {
+	return gui.returnFromContext()
+}
+
+func (gui *Gui) onMergeResultFocusLost() error {
+	view := gui.Views.Main
+	view.Editable = false
+	view.Editor = nil
+	view.TextArea.Clear()
+	return nil
+}
+
+func (gui *Gui) getMergeResultOptions() map[string]string {
+	keybindingConfig := gui.UserConfig.Keybinding
+
+	return map[string]string{
+		gui.getKeyDisplay(keybindingConfig.Universal.SubmitEditorText): gui.Tr.LcSaveMergeResult,
+		gui.getKeyDisplay(keybindingConfig.Universal.AppendNewline):    gui.Tr.LcNewLine,
+		gui.getKeyDisplay(keybindingConfig.Universal.Return):           gui.Tr.LcCancel,
+	}
+}
+
+func (gui *Gui) handleAutoResolveTrivialConflicts() error {
+	return gui.withMergeConflictLock(func() error {
+		gui.takeOverMergeConflictScrolling()
+
+		state := gui.State.Panels.Merging
+		resolved, remaining := state.AutoResolveTrivialConflicts(gui.UserConfig.Git.Merging.AutoResolveIgnoringWhitespace)
+		gui.raiseToast(fmt.Sprintf(gui.Tr.AutoResolvedConflicts, resolved, remaining))
+		if resolved == 0 {
+			return nil
+		}
+
+		gui.logAction(gui.Tr.Actions.ResolveTrivialConflicts)
+		gui.logCommand(fmt.Sprintf("Resolving %d trivial conflicts in %s", resolved, state.GetPath()), false)
+		if err := ioutil.WriteFile(state.GetPath(), []byte(state.GetContent()), 0644); err != nil {
+			return err
+		}
+
+		if state.AllConflictsResolved() {
+			return gui.onLastConflictResolved()
+		}
+
+		return gui.renderConflictsWithFocus()
+	})
+}
+
+// handleAutoResolveAllTrivialConflicts resolves the trivial conflicts of every
+// conflicted file. Each file's resolution can be undone from the merging panel,
+// whether or not the file is loaded into it at the time.
+func (gui *Gui) handleAutoResolveAllTrivialConflicts() error {
+	ignoreWhitespace := gui.UserConfig.Git.Merging.AutoResolveIgnoringWhitespace
+	prompt := gui.Tr.AutoResolveTrivialConflictsPrompt
+	if ignoreWhitespace {
+		prompt = gui.Tr.AutoResolveIgnoringWhitespacePrompt
+	}
+
+	return gui.ask(askOpts{
+		title:  gui.Tr.AutoResolveTrivialConflicts,
+		prompt: prompt,
+		handleConfirm: func() error {
+			gui.logAction(gui.Tr.Actions.ResolveTrivialConflicts)
+
+			totalResolved, totalRemaining := 0, 0
+			err := gui.withMergeConflictLock(func() error {
+				state := gui.State.Panels.Merging
+				for _, file := range gui.State.FileTreeViewModel.GetAllFiles() {
+					if !file.HasInlineMergeConflicts {
+						continue
+					}
+
+					var content string
+					var resolved, remaining int
+					if state.GetPath() == file.Name {
+						resolved, remaining = state.AutoResolveTrivialConflicts(ignoreWhitespace)
+						content = state.GetContent()
+					} else {
+						original, err := gui.Git.File.Cat(file.Name)
+						if err != nil {
+							return err
+						}
+						content, resolved, remaining = mergeconflicts.AutoResolveTrivialConflicts(original, ignoreWhitespace)
+						if resolved > 0 {
+							state.PushContentForFile(file.Name, original, content)
+						}
+					}
+
+					totalResolved += resolved
+					totalRemaining += remaining
+					if resolved == 0 {
+						continue
+					}
+
+					gui.logCommand(fmt.Sprintf("Resolving %d trivial conflicts in %s", resolved, file.Name), false)
+					if err := ioutil.WriteFile(file.Name, []byte(content), 0644); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return gui.surfaceError(err)
+			}
+
+			gui.raiseToast(fmt.Sprintf(gui.Tr.AutoResolvedConflicts, totalResolved, totalRemaining))
+			return gui.onLastConflictResolved()
+		},
+	})
+}
+
+func (gui *Gui) handleToggleThreeWayMergeView() error {
+	return gui.withMergeConflictLock(func() error {
+		state := gui.State.Panels.Merging
+		state.SetThreeWay(!state.ThreeWay())
+		if state.ThreeWay() && !state.HasStages() {
+			gui.loadMergeStages()
+		}
+
+		return gui.renderConflictsWithFocus()
+	})
+}
+
+// loadMergeStages gets the base, ours and theirs versions of the conflicted
+// file from the index for the three-way view. If the file didn't exist on one
+// side, git has no stage for it, so we treat that version as empty.
+func (gui *Gui) loadMergeStages() {
+	state := gui.State.Panels.Merging
+	path := state.GetPath()
+
+	versions := make([]string, 3)
+	for i := range versions {
+		content, err := gui.Git.WorkingTree.ShowFileAtStage(i+1, path)
+		if err != nil {
+			gui.Log.Error(err)
+			continue
+		}
+		versions[i] = content
+	}
+
+	state.SetStages(mergeconflicts.Stages{Base: versions[0], Ours: versions[1], Theirs: versions[2]})
+}
+
+func (gui *Gui) handleMergeConflictEdit() error {
+	state := gui.State.Panels.Merging
+
+	state.Lock()
+	path := state.GetPath()
+	lineNumber := state.GetConflictMiddle() + 1
+	state.Unlock()
+
+	return gui.editFileAtLine(path, lineNumber)
+}
+
 func (gui *Gui) resolveConflict(selection mergeconflicts.Selection) (bool, error) {
 	gui.takeOverMergeConflictScrolling()
 
@@ -150,12 +416,25 @@ func (gui *Gui) renderConflicts(hasFocus bool) error {
 		})
 	}
 
+	var secondary *viewUpdateOpts
+	if state.ThreeWay() && state.HasStages() {
+		// the versions go above the file, so they get the same width
+		width, _ := gui.Views.Main.Size()
+		headers := [3]string{gui.Tr.MergeBaseHeader, gui.Tr.MergeOursHeader, gui.Tr.MergeTheirsHeader}
+		secondary = &viewUpdateOpts{
+			title:  gui.Tr.ThreeWayMergeTitle,
+			task:   NewRenderStringTask(mergeconflicts.ColoredThreeWayStages(state, width, headers)),
+			noWrap: true,
+		}
+	}
+
 	return gui.refreshMainViews(refreshMainOpts{
 		main: &viewUpdateOpts{
 			title:  gui.Tr.MergeConflictsTitle,
 			task:   NewRenderStringWithoutScrollTask(content),
 			noWrap: true,
 		},
+		secondary: secondary,
 	})
 }
 
@@ -179,12 +458,26 @@ func (gui *Gui) centerYPos(view *gocui.View, y int) {
 func (gui *Gui) getMergingOptions() map[string]string {
 	keybindingConfig := gui.UserConfig.Keybinding
 
+	if gui.State.Panels.Merging.ThreeWay() {
+		return map[string]string{
+			fmt.Sprintf("%s %s", gui.getKeyDisplay(keybindingConfig.Universal.PrevItem), gui.getKeyDisplay(keybindingConfig.Universal.NextItem)):   gui.Tr.LcSelectLine,
+			fmt.Sprintf("%s %s", gui.getKeyDisplay(keybindingConfig.Universal.PrevBlock), gui.getKeyDisplay(keybindingConfig.Universal.NextBlock)): gui.Tr.LcNavigateConflicts,
+			gui.getKeyDisplay(keybindingConfig.Universal.Select):          gui.Tr.LcPickLine,
+			gui.getKeyDisplay(keybindingConfig.Universal.Confirm):         gui.Tr.LcResolveWithPickedLines,
+			gui.getKeyDisplay(keybindingConfig.Universal.Edit):            gui.Tr.LcEditFile,
+			gui.getKeyDisplay(keybindingConfig.Main.EditHunk):             gui.Tr.LcEditMergeResult,
+			gui.getKeyDisplay(keybindingConfig.Main.AutoResolveConflicts): gui.Tr.LcAutoResolveTrivialConflicts,
+			gui.getKeyDisplay(keybindingConfig.Universal.Undo):            gui.Tr.LcUndo,
+		}
+	}
+
 	return map[string]string{
 		fmt.Sprintf("%s %s", gui.getKeyDisplay(keybindingConfig.Universal.PrevItem), gui.getKeyDisplay(keybindingConfig.Universal.NextItem)):   gui.Tr.LcSelectHunk,
 		fmt.Sprintf("%s %s", gui.getKeyDisplay(keybindingConfig.Universal.PrevBlock), gui.getKeyDisplay(keybindingConfig.Universal.NextBlock)): gui.Tr.LcNavigateConflicts,
-		gui.getKeyDisplay(keybindingConfig.Universal.Select):   gui.Tr.LcPickHunk,
-		gui.getKeyDisplay(keybindingConfig.Main.PickBothHunks): gui.Tr.LcPickAllHunks,
-		gui.getKeyDisplay(keybindingConfig.Universal.Undo):     gui.Tr.LcUndo,
+		gui.getKeyDisplay(keybindingConfig.Universal.Select):          gui.Tr.LcPickHunk,
+		gui.getKeyDisplay(keybindingConfig.Main.PickBothHunks):        gui.Tr.LcPickAllHunks,
+		gui.getKeyDisplay(keybindingConfig.Main.AutoResolveConflicts): gui.Tr.LcAutoResolveTrivialConflicts,
+		gui.getKeyDisplay(keybindingConfig.Universal.Undo):            gui.Tr.LcUndo,
 	}
 }
 
@@ -214,6 +507,9 @@ func (gui *Gui) setMergeState(path string) (bool, error) {
 	}
 
 	gui.State.Panels.Merging.SetContent(content, path)
+	if gui.State.Panels.Merging.ThreeWay() && !gui.State.Panels.Merging.HasStages() {
+		gui.loadMergeStages()
+	}
 
 	return !gui.State.Panels.Merging.NoConflicts(), nil
 }
@@ -251,6 +547,10 @@ func (gui *Gui) renderingConflicts() bool {
 	return gui.State.Panels.Merging.Active()
 }
 
+func (gui *Gui) showingThreeWayMerge() bool {
+	return gui.isMainPanelSplit() && gui.State.Panels.Merging.Active() && gui.State.Panels.Merging.ThreeWay()
+}
+
 func (gui *Gui) withMergeConflictLock(f func() error) error {
 	gui.State.Panels.Merging.Lock()
 	defer gui.State.Panels.Merging.Unlock()
@@ -0,0 +1,114 @@
+package mergeconflicts
+
+import (
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// A conflict is trivial if we can resolve it without losing anybody's work.
+// That's the case when both sides made the same change, or when only one side
+// changed anything relative to the base. We can only tell whether a side
+// changed anything if the file has a base section, i.e. if merge.conflictStyle
+// is diff3 or zdiff3. If the user asks us to, we also count differences in
+// whitespace as no difference at all, which loses the whitespace changes of the
+// side we don't pick.
+
+// trivialResolution returns the selection which resolves the conflict, if the
+// conflict is trivial
+func trivialResolution(lines []string, conflict *mergeConflict, ignoreWhitespace bool) (Selection, bool) {
+	ours := sectionLines(lines, conflict.oursBounds)
+	theirs := sectionLines(lines, conflict.theirsBounds)
+	var base []string
+	if conflict.hasAncestor() {
+		base = sectionLines(lines, conflict.ancestorBounds)
+	}
+
+	// we try an exact comparison first and then, failing that, we may try
+	// again ignoring whitespace
+	comparisons := []func([]string, []string) bool{equalLines}
+	if ignoreWhitespace {
+		comparisons = append(comparisons, equalIgnoringWhitespace)
+	}
+
+	for _, equal := range comparisons {
+		if equal(ours, theirs) {
+			return TOP, true
+		}
+
+		if conflict.hasAncestor() {
+			if equal(ours, base) {
+				return BOTTOM, true
+			}
+			if equal(theirs, base) {
+				return TOP, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func sectionLines(lines []string, bounds func() (int, int)) []string {
+	start, end := bounds()
+	return lines[start:end]
+}
+
+func equalLines(a []string, b []string) bool {
+	return strings.Join(a, "\n") == strings.Join(b, "\n")
+}
+
+func equalIgnoringWhitespace(a []string, b []string) bool {
+	return strings.Join(strings.Fields(strings.Join(a, "\n")), " ") ==
+		strings.Join(strings.Fields(strings.Join(b, "\n")), " ")
+}
+
+// AutoResolveTrivialConflicts resolves each trivial conflict in the content,
+// returning the new content along with the number of conflicts it resolved and
+// the number that remain
+func AutoResolveTrivialConflicts(content string, ignoreWhitespace bool) (string, int, int) {
+	conflicts := findConflicts(content)
+	lines := utils.SplitLines(content)
+
+	resolutions := map[*mergeConflict]Selection{}
+	for _, conflict := range conflicts {
+		if selection, ok := trivialResolution(lines, conflict, ignoreWhitespace); ok {
+			resolutions[conflict] = selection
+		}
+	}
+
+	if len(resolutions) == 0 {
+		return content, 0, len(conflicts)
+	}
+
+	// we split after the newlines so that we keep the file's line endings
+	var result strings.Builder
+	conflictIdx := 0
+	for i, line := range strings.SplitAfter(content, "\n") {
+		for conflictIdx < len(conflicts) && conflicts[conflictIdx].end < i {
+			conflictIdx++
+		}
+
+		if conflictIdx < len(conflicts) {
+			conflict := conflicts[conflictIdx]
+			if selection, ok := resolutions[conflict]; ok && !selection.isIndexToKeep(conflict, i) {
+				continue
+			}
+		}
+
+		result.WriteString(line)
+	}
+
+	return result.String(), len(resolutions), len(conflicts) - len(resolutions)
+}
+
+// AutoResolveTrivialConflicts resolves the trivial conflicts in the file as a
+// single change, so that one undo brings them all back
+func (s *State) AutoResolveTrivialConflicts(ignoreWhitespace bool) (int, int) {
+	content, resolved, remaining := AutoResolveTrivialConflicts(s.GetContent(), ignoreWhitespace)
+	if resolved > 0 {
+		s.PushContent(content)
+	}
+
+	return resolved, remaining
+}
@@ -0,0 +1,155 @@
+package mergeconflicts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoResolveTrivialConflicts(t *testing.T) {
+	type scenario struct {
+		name              string
+		content           string
+		ignoreWhitespace  bool
+		expectedContent   string
+		expectedResolved  int
+		expectedRemaining int
+	}
+
+	scenarios := []scenario{
+		{
+			name:              "no conflicts",
+			content:           "foo\nbar\n",
+			expectedContent:   "foo\nbar\n",
+			expectedResolved:  0,
+			expectedRemaining: 0,
+		},
+		{
+			name: "both sides made the same change",
+			content: `before
+<<<<<<< HEAD
+same
+=======
+same
+>>>>>>> branch
+after
+`,
+			expectedContent:   "before\nsame\nafter\n",
+			expectedResolved:  1,
+			expectedRemaining: 0,
+		},
+		{
+			name: "only one side changed anything",
+			content: `<<<<<<< HEAD
+base
+||||||| fffffff
+base
+=======
+theirs
+>>>>>>> branch
+middle
+<<<<<<< HEAD
+ours
+||||||| fffffff
+base
+=======
+base
+>>>>>>> branch
+`,
+			expectedContent:   "theirs\nmiddle\nours\n",
+			expectedResolved:  2,
+			expectedRemaining: 0,
+		},
+		{
+			name:              "sides that differ only in whitespace are left alone by default",
+			content:           "<<<<<<< HEAD\n\tfoo(a, b)\n=======\n    foo(a,  b)\n\n>>>>>>> branch\n",
+			expectedContent:   "<<<<<<< HEAD\n\tfoo(a, b)\n=======\n    foo(a,  b)\n\n>>>>>>> branch\n",
+			expectedResolved:  0,
+			expectedRemaining: 1,
+		},
+		{
+			name:              "sides differ only in whitespace and we're ignoring it",
+			content:           "<<<<<<< HEAD\n\tfoo(a, b)\n=======\n    foo(a,  b)\n\n>>>>>>> branch\n",
+			ignoreWhitespace:  true,
+			expectedContent:   "\tfoo(a, b)\n",
+			expectedResolved:  1,
+			expectedRemaining: 0,
+		},
+		{
+			name: "a real conflict is left alone",
+			content: `<<<<<<< HEAD
+ours
+||||||| fffffff
+base
+=======
+theirs
+>>>>>>> branch
+<<<<<<< HEAD
+same
+=======
+same
+>>>>>>> branch
+`,
+			expectedContent: `<<<<<<< HEAD
+ours
+||||||| fffffff
+base
+=======
+theirs
+>>>>>>> branch
+same
+`,
+			expectedResolved:  1,
+			expectedRemaining: 1,
+		},
+		{
+			name: "without a base section we can't tell which side changed",
+			content: `<<<<<<< HEAD
+ours
+=======
+theirs
+>>>>>>> branch
+`,
+			expectedContent: `<<<<<<< HEAD
+ours
+=======
+theirs
+>>>>>>> branch
+`,
+			expectedResolved:  0,
+			expectedRemaining: 1,
+		},
+		{
+			name:              "windows line endings are kept",
+			content:           "<<<<<<< HEAD\r\nsame\r\n=======\r\nsame\r\n>>>>>>> branch\r\nafter\r\n",
+			expectedContent:   "same\r\nafter\r\n",
+			expectedResolved:  1,
+			expectedRemaining: 0,
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			content, resolved, remaining := AutoResolveTrivialConflicts(s.content, s.ignoreWhitespace)
+			assert.Equal(t, s.expectedContent, content)
+			assert.Equal(t, s.expectedResolved, resolved)
+			assert.Equal(t, s.expectedRemaining, remaining)
+		})
+	}
+}
+
+func TestStateAutoResolveTrivialConflictsCanBeUndone(t *testing.T) {
+	content := "<<<<<<< HEAD\nsame\n=======\nsame\n>>>>>>> branch\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> branch\n"
+
+	state := NewState()
+	state.SetContent(content, "file.txt")
+
+	resolved, remaining := state.AutoResolveTrivialConflicts(false)
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, "same\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> branch\n", state.GetContent())
+
+	assert.True(t, state.Undo())
+	assert.Equal(t, content, state.GetContent())
+}
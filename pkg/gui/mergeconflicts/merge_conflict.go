@@ -22,6 +22,38 @@ func (c *mergeConflict) isMarkerLine(i int) bool {
 		i == c.end
 }
 
+// contentLines returns the indices of the lines between the conflict's start
+// and end markers which aren't markers themselves
+func (c *mergeConflict) contentLines() []int {
+	lines := []int{}
+	for i := c.start + 1; i < c.end; i++ {
+		if !c.isMarkerLine(i) {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
+
+// oursBounds, ancestorBounds and theirsBounds return the range of lines of
+// each side of the conflict, excluding the markers
+func (c *mergeConflict) oursBounds() (int, int) {
+	if c.hasAncestor() {
+		return c.start + 1, c.ancestor
+	}
+	return c.start + 1, c.target
+}
+
+func (c *mergeConflict) ancestorBounds() (int, int) {
+	if !c.hasAncestor() {
+		return -1, -1
+	}
+	return c.ancestor + 1, c.target
+}
+
+func (c *mergeConflict) theirsBounds() (int, int) {
+	return c.target + 1, c.end
+}
+
 type Selection int
 
 const (
@@ -2,6 +2,7 @@ package mergeconflicts
 
 import (
 	"bytes"
+	"unicode/utf8"
 
 	"github.com/jesseduffield/lazygit/pkg/gui/style"
 	"github.com/jesseduffield/lazygit/pkg/theme"
@@ -21,12 +22,17 @@ func ColoredConflictFile(state *State, hasFocus bool) string {
 			textStyle = style.FgRed
 		}
 
-		if hasFocus && state.conflictIndex < len(state.conflicts) && *state.conflicts[state.conflictIndex] == *conflict && shouldHighlightLine(i, conflict, state.Selection()) {
+		isSelectedConflict := state.conflictIndex < len(state.conflicts) && *state.conflicts[state.conflictIndex] == *conflict
+		if hasFocus && isSelectedConflict && shouldHighlightLine(i, conflict, state) {
 			textStyle = textStyle.MergeStyle(theme.SelectedRangeBgColor).SetBold()
 		}
 		if i == conflict.end && len(remainingConflicts) > 0 {
 			conflict, remainingConflicts = shiftConflict(remainingConflicts)
 		}
+		if state.pickedLines[i] {
+			outputBuffer.WriteString(pickedLine(line, textStyle) + "\n")
+			continue
+		}
 		outputBuffer.WriteString(textStyle.Sprint(line) + "\n")
 	}
 	return outputBuffer.String()
@@ -36,7 +42,21 @@ func shiftConflict(conflicts []*mergeConflict) (*mergeConflict, []*mergeConflict
 	return conflicts[0], conflicts[1:]
 }
 
-func shouldHighlightLine(index int, conflict *mergeConflict, selection Selection) bool {
-	selectionStart, selectionEnd := selection.bounds(conflict)
+func shouldHighlightLine(index int, conflict *mergeConflict, state *State) bool {
+	if state.threeWay {
+		return index == state.SelectedLineIdx()
+	}
+
+	selectionStart, selectionEnd := state.Selection().bounds(conflict)
 	return index >= selectionStart && index <= selectionEnd
 }
+
+// pickedLine marks a line that has been picked in the three-way view in the
+// same way we mark a line that's been added to a custom patch
+func pickedLine(line string, textStyle style.TextStyle) string {
+	if line == "" {
+		line = " "
+	}
+	_, size := utf8.DecodeRuneInString(line)
+	return textStyle.MergeStyle(style.BgGreen).Sprint(line[:size]) + textStyle.Sprint(line[size:])
+}
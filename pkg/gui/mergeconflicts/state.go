@@ -16,6 +16,10 @@ type State struct {
 	// The last item is the current file content.
 	contents []string
 
+	// stacks of content for files we've changed without loading them, keyed by
+	// path, so that the changes can be undone once they're loaded
+	otherContents map[string][]string
+
 	conflicts []*mergeConflict
 	// this is the index of the above `conflicts` field which is currently selected
 	conflictIndex int
@@ -23,6 +27,16 @@ type State struct {
 	// this is the index of the selected conflict's available selections slice e.g. [TOP, MIDDLE, BOTTOM]
 	// We use this to know which hunk of the conflict is selected.
 	selectionIndex int
+
+	// when true we show the base, ours and theirs versions of the file above
+	// the file itself, and rather than picking whole hunks you pick lines
+	threeWay bool
+	// the versions of the file from the index, loaded when we need them
+	stages *Stages
+	// this is the index of the selected line amongst the selected conflict's content lines
+	lineIndex int
+	// the lines of the file (by index) that have been picked to resolve the selected conflict
+	pickedLines map[int]bool
 }
 
 func NewState() *State {
@@ -32,6 +46,8 @@ func NewState() *State {
 		selectionIndex: 0,
 		conflicts:      []*mergeConflict{},
 		contents:       []string{},
+		otherContents:  map[string][]string{},
+		pickedLines:    map[int]bool{},
 	}
 }
 
@@ -42,6 +58,10 @@ func (s *State) setConflictIndex(index int) {
 		s.conflictIndex = clamp(index, 0, len(s.conflicts)-1)
 	}
 	s.setSelectionIndex(s.selectionIndex)
+
+	// picked lines only make sense for the conflict they were picked in
+	s.lineIndex = 0
+	s.pickedLines = map[int]bool{}
 }
 
 func (s *State) setSelectionIndex(index int) {
@@ -80,11 +100,36 @@ func (s *State) SetContent(content string, path string) {
 		return
 	}
 
+	if path != s.path {
+		s.stages = nil
+	}
+
 	s.path = path
 	s.contents = []string{}
+
+	// if we changed the file while it wasn't loaded, and nothing else has
+	// changed it since, we pick up where we left off so that we can undo
+	if contents, ok := s.otherContents[path]; ok {
+		delete(s.otherContents, path)
+		if contents[len(contents)-1] == content {
+			s.contents = contents[:len(contents)-1]
+		}
+	}
+
 	s.PushContent(content)
 }
 
+// PushContentForFile records a change to a file other than the one loaded, so
+// that it can be undone if the file is loaded later
+func (s *State) PushContentForFile(path string, before string, after string) {
+	contents, ok := s.otherContents[path]
+	if !ok || contents[len(contents)-1] != before {
+		contents = []string{before}
+	}
+
+	s.otherContents[path] = append(contents, after)
+}
+
 // this is for when you've resolved a conflict. This allows you to undo to a previous
 // state
 func (s *State) PushContent(content string) {
@@ -148,6 +193,7 @@ func (s *State) AllConflictsResolved() bool {
 func (s *State) Reset() {
 	s.contents = []string{}
 	s.path = ""
+	s.stages = nil
 }
 
 func (s *State) Active() bool {
@@ -161,6 +207,10 @@ func (s *State) GetConflictMiddle() int {
 		return 0
 	}
 
+	if s.threeWay {
+		return s.SelectedLineIdx()
+	}
+
 	return currentConflict.target
 }
 
@@ -121,3 +121,24 @@ baz
 		})
 	}
 }
+
+func TestStatePushContentForFile(t *testing.T) {
+	original := "<<<<<<< HEAD\nsame\n=======\nsame\n>>>>>>> branch\n"
+	resolved := "same\n"
+
+	state := NewState()
+	state.SetContent(original, "loaded.txt")
+	state.PushContentForFile("other.txt", original, resolved)
+	state.PushContentForFile("changed.txt", original, resolved)
+
+	// the change can be undone once the file is loaded
+	state.SetContent(resolved, "other.txt")
+	assert.True(t, state.Undo())
+	assert.Equal(t, original, state.GetContent())
+	assert.False(t, state.Undo())
+
+	// but not if something else has changed the file since
+	state.SetContent("something else\n", "changed.txt")
+	assert.False(t, state.Undo())
+	assert.Equal(t, "something else\n", state.GetContent())
+}
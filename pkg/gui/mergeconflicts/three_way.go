@@ -0,0 +1,271 @@
+package mergeconflicts
+
+import (
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/mattn/go-runewidth"
+)
+
+// In the three-way view we show the base, ours and theirs versions of the
+// selected conflict next to each other, above the file itself. We take those
+// versions from the index rather than from between the conflict markers,
+// because with zdiff3 the lines which both sides have in common are moved out
+// of the markers, and without diff3 there's no base section at all.
+
+const (
+	THREE_WAY_SEPARATOR = "│"
+	// how many lines we show either side of the conflict in each version
+	THREE_WAY_CONTEXT_LINES    = 3
+	THREE_WAY_TAB_WIDTH        = 4
+	MIN_THREE_WAY_COLUMN_WIDTH = 8
+)
+
+// Stages holds the versions of a conflicted file at stages 1, 2 and 3 of the
+// index. A version is empty if the file didn't exist on that side.
+type Stages struct {
+	Base   string
+	Ours   string
+	Theirs string
+}
+
+// the range of lines [start, end) of a version of the file which correspond to
+// a conflict
+type stageRegion struct {
+	start int
+	end   int
+}
+
+var stageStyles = [3]style.TextStyle{style.FgYellow, style.FgGreen, style.FgBlue}
+
+func (s *State) ThreeWay() bool {
+	return s.threeWay
+}
+
+func (s *State) SetThreeWay(value bool) {
+	s.threeWay = value
+	s.lineIndex = 0
+	s.pickedLines = map[int]bool{}
+}
+
+func (s *State) HasStages() bool {
+	return s.stages != nil
+}
+
+func (s *State) SetStages(stages Stages) {
+	s.stages = &stages
+}
+
+func (s *State) currentContentLines() []int {
+	if conflict := s.currentConflict(); conflict != nil {
+		return conflict.contentLines()
+	}
+	return nil
+}
+
+func (s *State) setLineIndex(index int) {
+	if lines := s.currentContentLines(); len(lines) != 0 {
+		s.lineIndex = clamp(index, 0, len(lines)-1)
+	}
+}
+
+func (s *State) SelectNextLine() {
+	s.setLineIndex(s.lineIndex + 1)
+}
+
+func (s *State) SelectPrevLine() {
+	s.setLineIndex(s.lineIndex - 1)
+}
+
+// SelectedLineIdx returns the index within the file of the selected line of the
+// selected conflict
+func (s *State) SelectedLineIdx() int {
+	conflict := s.currentConflict()
+	if conflict == nil {
+		return 0
+	}
+
+	lines := conflict.contentLines()
+	if len(lines) == 0 {
+		return conflict.start
+	}
+	return lines[clamp(s.lineIndex, 0, len(lines)-1)]
+}
+
+// TogglePickedLine adds the selected line to, or removes it from, the lines
+// that the selected conflict will be resolved with
+func (s *State) TogglePickedLine() {
+	if len(s.currentContentLines()) == 0 {
+		return
+	}
+
+	index := s.SelectedLineIdx()
+	if s.pickedLines[index] {
+		delete(s.pickedLines, index)
+	} else {
+		s.pickedLines[index] = true
+	}
+}
+
+func (s *State) PickedLineCount() int {
+	return len(s.pickedLines)
+}
+
+// ContentAfterPickingLines returns the content of the file with the selected
+// conflict replaced by the lines that have been picked from it, in the order
+// they appear in the file
+func (s *State) ContentAfterPickingLines() (bool, string, error) {
+	conflict := s.currentConflict()
+	if conflict == nil {
+		return false, "", nil
+	}
+
+	content := ""
+	err := utils.ForEachLineInFile(s.path, func(line string, i int) {
+		if i < conflict.start || conflict.end < i || s.pickedLines[i] {
+			content += line
+		}
+	})
+
+	if err != nil {
+		return false, "", err
+	}
+
+	return true, content, nil
+}
+
+// stageRegions returns, for each of the base, ours and theirs versions of the
+// file, the lines which correspond to the selected conflict
+func (s *State) stageRegions(fileLines []string, stageLines [3][]string) [3]stageRegion {
+	sections := [3]func(*mergeConflict) (int, int){
+		(*mergeConflict).ancestorBounds,
+		(*mergeConflict).oursBounds,
+		(*mergeConflict).theirsBounds,
+	}
+
+	regions := [3]stageRegion{}
+	for k := range regions {
+		// we go through the conflicts in order so that if a conflict's lines
+		// appear several times in a version we find the right occurrence
+		cursor := 0
+		for i := 0; i <= s.conflictIndex; i++ {
+			regions[k] = findStageRegion(stageLines[k], fileLines, s.conflicts[i], sections[k], cursor)
+			cursor = regions[k].end
+		}
+	}
+
+	return regions
+}
+
+func findStageRegion(stageLines []string, fileLines []string, conflict *mergeConflict, section func(*mergeConflict) (int, int), cursor int) stageRegion {
+	if start, end := section(conflict); start >= 0 && end > start {
+		if index := indexOfLines(stageLines, fileLines[start:end], cursor); index >= 0 {
+			return stageRegion{start: index, end: index + end - start}
+		}
+	}
+
+	// If the section is empty, or missing, or has had lines moved out of it by
+	// zdiff3, we look for the lines either side of the conflict instead,
+	// given that those are the same in each version
+	start := cursor
+	if conflict.start > 0 {
+		if index := indexOfLines(stageLines, fileLines[conflict.start-1:conflict.start], cursor); index >= 0 {
+			start = index + 1
+		}
+	}
+	end := start
+	if conflict.end+1 < len(fileLines) {
+		if index := indexOfLines(stageLines, fileLines[conflict.end+1:conflict.end+2], start); index >= 0 {
+			end = index
+		}
+	} else {
+		// the conflict runs to the end of the file
+		end = utils.Max(start, len(stageLines))
+	}
+	return stageRegion{start: start, end: end}
+}
+
+// indexOfLines returns the index of the first occurrence of needle in
+// haystack at or after the given index, or -1 if there isn't one
+func indexOfLines(haystack []string, needle []string, from int) int {
+outer:
+	for i := from; i+len(needle) <= len(haystack); i++ {
+		for j, line := range needle {
+			if haystack[i+j] != line {
+				continue outer
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// ColoredThreeWayStages lays out the base, ours and theirs versions of the
+// selected conflict in columns, given the number of columns we have to work
+// with and the header of each column
+func ColoredThreeWayStages(state *State, width int, headers [3]string) string {
+	if state.currentConflict() == nil || state.stages == nil {
+		return ""
+	}
+
+	fileLines := utils.SplitLines(state.GetContent())
+	stageLines := [3][]string{
+		utils.SplitLines(state.stages.Base),
+		utils.SplitLines(state.stages.Ours),
+		utils.SplitLines(state.stages.Theirs),
+	}
+	regions := state.stageRegions(fileLines, stageLines)
+
+	separatorWidth := runewidth.StringWidth(THREE_WAY_SEPARATOR)
+	columnWidth := utils.Max((width-2*separatorWidth)/3, MIN_THREE_WAY_COLUMN_WIDTH)
+
+	maxRegionLength := 0
+	for _, region := range regions {
+		maxRegionLength = utils.Max(maxRegionLength, region.end-region.start)
+	}
+	rowCount := THREE_WAY_CONTEXT_LINES + maxRegionLength + THREE_WAY_CONTEXT_LINES
+
+	renderedRows := []string{}
+	cells := make([]string, 3)
+	for k, header := range headers {
+		cells[k] = threeWayCell(header, columnWidth, style.New().SetBold())
+	}
+	renderedRows = append(renderedRows, strings.Join(cells, THREE_WAY_SEPARATOR))
+
+	for row := 0; row < rowCount; row++ {
+		for k, region := range regions {
+			// each version's region starts on the same row, so that the
+			// conflicting lines of each version sit next to each other
+			index := -1
+			textStyle := theme.DefaultTextColor
+			switch offset := row - THREE_WAY_CONTEXT_LINES; {
+			case offset < 0:
+				index = region.start + offset
+			case offset < maxRegionLength:
+				if offset < region.end-region.start {
+					index = region.start + offset
+					textStyle = stageStyles[k]
+				}
+			default:
+				index = region.end + offset - maxRegionLength
+			}
+
+			line := ""
+			if index >= 0 && index < len(stageLines[k]) {
+				line = stageLines[k][index]
+			}
+			cells[k] = threeWayCell(line, columnWidth, textStyle)
+		}
+		renderedRows = append(renderedRows, strings.Join(cells, THREE_WAY_SEPARATOR))
+	}
+
+	return strings.Join(renderedRows, "\n")
+}
+
+func threeWayCell(content string, width int, textStyle style.TextStyle) string {
+	content = strings.ReplaceAll(content, "\t", strings.Repeat(" ", THREE_WAY_TAB_WIDTH))
+	content = runewidth.Truncate(content, width, "")
+	return textStyle.Sprint(content) + strings.Repeat(" ", width-runewidth.StringWidth(content))
+}
@@ -0,0 +1,149 @@
+package mergeconflicts
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStageRegions(t *testing.T) {
+	type scenario struct {
+		name          string
+		content       string
+		stages        Stages
+		conflictIndex int
+		expected      [3]stageRegion
+	}
+
+	scenarios := []scenario{
+		{
+			name: "zdiff3, where the common lines have been moved out of the markers",
+			content: `one
+two
+<<<<<<< HEAD
+ours
+||||||| base
+common
+old
+=======
+theirs
+>>>>>>> branch
+three
+`,
+			stages: Stages{
+				Base:   "one\ntwo\ncommon\nold\nthree\n",
+				Ours:   "one\ntwo\ncommon\nours\nthree\n",
+				Theirs: "one\ntwo\ncommon\ntheirs\nthree\n",
+			},
+			expected: [3]stageRegion{{2, 4}, {3, 4}, {3, 4}},
+		},
+		{
+			name: "no base section and an empty side",
+			content: `one
+<<<<<<< HEAD
+ours
+=======
+>>>>>>> branch
+two
+`,
+			stages: Stages{
+				Base:   "one\nbase\ntwo\n",
+				Ours:   "one\nours\ntwo\n",
+				Theirs: "one\ntwo\n",
+			},
+			expected: [3]stageRegion{{1, 2}, {1, 2}, {1, 1}},
+		},
+		{
+			name: "the same lines in two conflicts",
+			content: `<<<<<<< HEAD
+same
+=======
+first
+>>>>>>> branch
+middle
+<<<<<<< HEAD
+same
+=======
+second
+>>>>>>> branch
+`,
+			stages: Stages{
+				Base:   "base\nmiddle\nbase\n",
+				Ours:   "same\nmiddle\nsame\n",
+				Theirs: "first\nmiddle\nsecond\n",
+			},
+			conflictIndex: 1,
+			expected:      [3]stageRegion{{2, 3}, {2, 3}, {2, 3}},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			state := NewState()
+			state.SetContent(s.content, "file.txt")
+			state.SetStages(s.stages)
+			state.setConflictIndex(s.conflictIndex)
+
+			stageLines := [3][]string{
+				utils.SplitLines(s.stages.Base),
+				utils.SplitLines(s.stages.Ours),
+				utils.SplitLines(s.stages.Theirs),
+			}
+			assert.EqualValues(t, s.expected, state.stageRegions(utils.SplitLines(s.content), stageLines))
+		})
+	}
+}
+
+func TestContentAfterPickingLines(t *testing.T) {
+	content := `before
+<<<<<<< HEAD
+ours 1
+ours 2
+||||||| base
+base
+=======
+theirs 1
+>>>>>>> branch
+after
+`
+
+	dir, err := ioutil.TempDir("", "lazygit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "file.txt")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+
+	state := NewState()
+	state.SetContent(content, path)
+	state.SetThreeWay(true)
+
+	assert.Equal(t, 2, state.SelectedLineIdx())
+
+	// pick 'ours 2' and 'theirs 1', skipping over the markers
+	state.SelectNextLine()
+	state.TogglePickedLine()
+	state.SelectNextLine()
+	state.SelectNextLine()
+	assert.Equal(t, 7, state.SelectedLineIdx())
+	state.TogglePickedLine()
+
+	// picking a line twice unpicks it
+	state.SelectPrevLine()
+	state.TogglePickedLine()
+	state.TogglePickedLine()
+	assert.Equal(t, 2, state.PickedLineCount())
+
+	ok, result, err := state.ContentAfterPickingLines()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "before\nours 2\ntheirs 1\nafter\n", result)
+
+	// resolving the conflict leaves nothing picked
+	state.PushContent(result)
+	assert.Equal(t, 0, state.PickedLineCount())
+}
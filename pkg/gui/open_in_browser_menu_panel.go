@@ -0,0 +1,193 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/hosting_service"
+	"github.com/jesseduffield/lazygit/pkg/commands/patch"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// The open in browser menu lists the pages on the hosting service's website
+// that show whatever is selected. The website only knows about what we've
+// pushed, so we link files to commits rather than to the working tree.
+
+type browserLink struct {
+	label  string
+	getURL func(mgr *hosting_service.HostingServiceMgr) (string, error)
+}
+
+func (gui *Gui) handleCreateOpenInBrowserMenu() error {
+	links, err := gui.browserLinks()
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	hostingServiceMgr := gui.getHostingServiceMgr()
+	menuItems := make([]*menuItem, 0, len(links))
+	for _, link := range links {
+		url, err := link.getURL(hostingServiceMgr)
+		if err != nil {
+			return gui.surfaceError(err)
+		}
+
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: []string{link.label, style.FgBlue.Sprint(url)},
+			onPress: func() error {
+				gui.logAction(gui.Tr.Actions.OpenInBrowser)
+				return gui.OSCommand.OpenLink(url)
+			},
+		})
+	}
+
+	return gui.createMenu(gui.Tr.OpenInBrowserTitle, menuItems, createMenuOptions{showCancel: true})
+}
+
+// browserLinks returns the links for the current context's selection
+func (gui *Gui) browserLinks() ([]browserLink, error) {
+	switch gui.currentContext().GetKey() {
+	case FILES_CONTEXT_KEY:
+		path := gui.getSelectedPath()
+		if path == "" {
+			return nil, nil
+		}
+		headSha, err := gui.Git.Commit.GetSha("HEAD")
+		if err != nil {
+			return nil, err
+		}
+		return []browserLink{gui.browserFileLink(headSha, path)}, nil
+
+	case COMMIT_FILES_CONTEXT_KEY:
+		path := gui.getSelectedCommitFilePath()
+		if path == "" {
+			return nil, nil
+		}
+		return []browserLink{gui.browserFileLink(gui.State.Panels.CommitFiles.refName, path)}, nil
+
+	case MAIN_STAGING_CONTEXT_KEY:
+		file := gui.getSelectedFile()
+		if file == nil {
+			return nil, nil
+		}
+		headSha, err := gui.Git.Commit.GetSha("HEAD")
+		if err != nil {
+			return nil, err
+		}
+		// a file that isn't at HEAD has no lines to link to
+		if file.Added || !file.Tracked {
+			return []browserLink{gui.browserFileLink(headSha, file.Name)}, nil
+		}
+		// we're showing the working tree or the index, so we link to the lines
+		// at HEAD that the selected lines came from
+		diff, err := gui.Git.WorkingTree.DiffAgainstHead(file.Name, gui.State.Panels.LineByLine.SecondaryFocused)
+		if err != nil {
+			return nil, err
+		}
+		return gui.browserLineLinks(headSha, file.Name, func(lineNumber int) int {
+			return utils.Max(patch.OldLineNumber(diff, lineNumber), 1)
+		}), nil
+
+	case MAIN_PATCH_BUILDING_CONTEXT_KEY:
+		file := gui.getSelectedCommitFile()
+		if file == nil {
+			return nil, nil
+		}
+		return gui.browserLineLinks(gui.State.Panels.CommitFiles.refName, file.Name, nil), nil
+
+	case LOCAL_BRANCHES_CONTEXT_KEY:
+		branch := gui.getSelectedBranch()
+		if branch == nil {
+			return nil, nil
+		}
+		// the remote knows the branch by the name of its upstream, if it has one
+		branchName := pullRequestHeadBranch(branch)
+		links := []browserLink{{
+			label: fmt.Sprintf(gui.Tr.BrowseBranch, branchName),
+			getURL: func(mgr *hosting_service.HostingServiceMgr) (string, error) {
+				return mgr.GetBranchURL(branchName)
+			},
+		}}
+		if checkedOutBranch := gui.currentBranch(); checkedOutBranch != nil && checkedOutBranch.Name != branch.Name {
+			links = append(links, gui.browserCompareLink(pullRequestHeadBranch(checkedOutBranch), branchName))
+		}
+		return append(links, gui.browserDiffingLinks(branchName)...), nil
+
+	case TAGS_CONTEXT_KEY:
+		tag := gui.getSelectedTag()
+		if tag == nil {
+			return nil, nil
+		}
+		links := []browserLink{{
+			label: fmt.Sprintf(gui.Tr.BrowseTag, tag.Name),
+			getURL: func(mgr *hosting_service.HostingServiceMgr) (string, error) {
+				return mgr.GetTagURL(tag.Name)
+			},
+		}}
+		return append(links, gui.browserDiffingLinks(tag.Name)...), nil
+	}
+
+	return nil, nil
+}
+
+func (gui *Gui) browserFileLink(ref string, path string) browserLink {
+	return browserLink{
+		label: fmt.Sprintf(gui.Tr.BrowseFile, shortRef(ref)),
+		getURL: func(mgr *hosting_service.HostingServiceMgr) (string, error) {
+			return mgr.GetFileURL(ref, path)
+		},
+	}
+}
+
+// browserLineLinks returns links to the lines selected in the line-by-line
+// panel and to the whole file. If the panel's line numbers aren't those of the
+// file at the ref, refLineNumber maps them to it.
+func (gui *Gui) browserLineLinks(ref string, path string, refLineNumber func(int) int) []browserLink {
+	links := []browserLink{}
+	_ = gui.withLBLActiveCheck(func(state *LblPanelState) error {
+		startLine, endLine := state.SelectedLineNumbers()
+		if refLineNumber != nil {
+			startLine, endLine = refLineNumber(startLine), refLineNumber(endLine)
+		}
+		label := fmt.Sprintf(gui.Tr.BrowseLines, startLine, endLine, shortRef(ref))
+		if startLine == endLine {
+			label = fmt.Sprintf(gui.Tr.BrowseLine, startLine, shortRef(ref))
+		}
+
+		links = append(links, browserLink{
+			label: label,
+			getURL: func(mgr *hosting_service.HostingServiceMgr) (string, error) {
+				return mgr.GetLinesURL(ref, path, startLine, endLine)
+			},
+		})
+		return nil
+	})
+
+	return append(links, gui.browserFileLink(ref, path))
+}
+
+func (gui *Gui) browserCompareLink(from string, to string) browserLink {
+	return browserLink{
+		label: fmt.Sprintf(gui.Tr.BrowseCompare, shortRef(from), shortRef(to)),
+		getURL: func(mgr *hosting_service.HostingServiceMgr) (string, error) {
+			return mgr.GetCompareURL(from, to)
+		},
+	}
+}
+
+// browserDiffingLinks returns a link to the diff we'd show for the ref in
+// diffing mode, if we're in it
+func (gui *Gui) browserDiffingLinks(ref string) []browserLink {
+	diffing := gui.State.Modes.Diffing
+	if !diffing.Active() || diffing.Ref == ref {
+		return nil
+	}
+
+	if diffing.Reverse {
+		return []browserLink{gui.browserCompareLink(ref, diffing.Ref)}
+	}
+	return []browserLink{gui.browserCompareLink(diffing.Ref, ref)}
+}
@@ -13,7 +13,7 @@ func (gui *Gui) getBindings(v *gocui.View) []*Binding {
 		bindingsGlobal, bindingsPanel []*Binding
 	)
 
-	bindings := append(gui.GetCustomCommandKeybindings(), gui.GetInitialKeybindings()...)
+	bindings := append(gui.getApplicableCustomCommandKeybindings(), gui.GetInitialKeybindings()...)
 
 	for _, binding := range bindings {
 		if GetKeyDisplay(binding.Key) != "" && binding.Description != "" {
@@ -58,10 +58,15 @@ func (gui *Gui) handleCreateOptionsMenu() error {
 
 	for i, binding := range bindings {
 		binding := binding // note to self, never close over loop variables
+		keyDisplay := ""
+		// custom commands without a key can only be run from this menu
+		if binding.Key != nil {
+			keyDisplay = GetKeyDisplay(binding.Key)
+		}
 		menuItems[i] = &menuItem{
-			displayStrings: []string{GetKeyDisplay(binding.Key), gui.displayDescription(binding)},
+			displayStrings: []string{keyDisplay, gui.displayDescription(binding)},
 			onPress: func() error {
-				if binding.Key == nil {
+				if binding.Handler == nil {
 					return nil
 				}
 				if err := gui.handleMenuClose(); err != nil {
@@ -1,6 +1,7 @@
 package gui
 
 import (
+	"github.com/jesseduffield/lazygit/pkg/commands/patch"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
@@ -31,17 +32,20 @@ func (gui *Gui) refreshPatchBuildingPanel(selectedLineIdx int) error {
 		return nil
 	}
 
-	to := gui.State.CommitFileTreeViewModel.GetParent()
-	from, reverse := gui.getFromAndReverseArgsForDiff(to)
-	diff, err := gui.Git.WorkingTree.ShowFileDiff(from, to, reverse, node.GetPath(), true)
-	if err != nil {
-		return err
+	var diff string
+	if gui.showingLoadedPatch() {
+		diff = gui.Git.Patch.PatchManager.LoadedFileDiff(node.GetPath())
+	} else {
+		to := gui.State.CommitFileTreeViewModel.GetParent()
+		from, reverse := gui.getFromAndReverseArgsForDiff(to)
+		var err error
+		diff, err = gui.Git.WorkingTree.ShowFileDiff(from, to, reverse, node.GetPath(), true)
+		if err != nil {
+			return err
+		}
 	}
 
 	secondaryDiff := gui.Git.Patch.PatchManager.RenderPatchForFile(node.GetPath(), true, false, true)
-	if err != nil {
-		return err
-	}
 
 	empty, err := gui.refreshLineByLinePanel(diff, secondaryDiff, false, selectedLineIdx)
 	if err != nil {
@@ -116,7 +120,7 @@ func (gui *Gui) handleToggleSelectionForPatch() error {
 func (gui *Gui) handleEscapePatchBuildingPanel() error {
 	gui.escapeLineByLinePanel()
 
-	if gui.Git.Patch.PatchManager.IsEmpty() {
+	if gui.Git.Patch.PatchManager.IsEmpty() && !gui.Git.Patch.PatchManager.LoadedFromFile() {
 		gui.Git.Patch.PatchManager.Reset()
 	}
 
@@ -130,13 +134,14 @@ func (gui *Gui) handleEscapePatchBuildingPanel() error {
 
 func (gui *Gui) secondaryPatchPanelUpdateOpts() *viewUpdateOpts {
 	if gui.Git.Patch.PatchManager.Active() {
-		patch := gui.Git.Patch.PatchManager.RenderAggregatedPatchColored(false)
+		plainPatch := gui.Git.Patch.PatchManager.RenderAggregatedPatchColored(true)
+		renderedPatch := patch.NewPatchParser(gui.Log, plainPatch, gui.patchParserOpts("secondary")).Render(-1, -1, nil)
 
 		return &viewUpdateOpts{
 			title:     "Custom Patch",
 			noWrap:    true,
 			highlight: true,
-			task:      NewRenderStringWithoutScrollTask(patch),
+			task:      NewRenderStringWithoutScrollTask(renderedPatch),
 		}
 	}
 
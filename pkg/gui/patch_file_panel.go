@@ -0,0 +1,173 @@
+package gui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// A custom patch only lives in memory, so we let the user save it to a file or
+// copy it to the clipboard to share it. A patch file can be loaded back in, in
+// which case we show its files in the commit files panel so that the user can
+// pick the parts they want, just as they would with a commit.
+
+func (gui *Gui) showingLoadedPatch() bool {
+	patchManager := gui.Git.Patch.PatchManager
+	return patchManager.LoadedFromFile() && gui.State.Panels.CommitFiles.refName == patchManager.To
+}
+
+var unsafePatchFilenameCharsRegex = regexp.MustCompile(`[^\w.-]+`)
+
+func (gui *Gui) defaultPatchFilename() string {
+	patchManager := gui.Git.Patch.PatchManager
+	if patchManager.LoadedFromFile() {
+		return patchManager.To
+	}
+
+	// To may be a stash ref like stash@{0}
+	name := unsafePatchFilenameCharsRegex.ReplaceAllString(utils.ShortSha(patchManager.To), "-")
+	return strings.Trim(name, "-") + ".patch"
+}
+
+func (gui *Gui) handleSavePatchToFile() error {
+	return gui.prompt(promptOpts{
+		title:          gui.Tr.SavePatchTitle,
+		initialContent: gui.defaultPatchFilename(),
+		handleConfirm: func(path string) error {
+			exists, err := gui.OSCommand.FileExists(path)
+			if err != nil {
+				return gui.surfaceError(err)
+			}
+			if !exists {
+				return gui.savePatchToFile(path)
+			}
+
+			return gui.ask(askOpts{
+				title:  gui.Tr.OverwritePatchFileTitle,
+				prompt: fmt.Sprintf(gui.Tr.OverwritePatchFilePrompt, path),
+				handleConfirm: func() error {
+					return gui.savePatchToFile(path)
+				},
+			})
+		},
+	})
+}
+
+func (gui *Gui) savePatchToFile(path string) error {
+	gui.logAction(gui.Tr.Actions.SavePatchToFile)
+	if err := gui.OSCommand.CreateFileWithContent(path, gui.Git.Patch.PatchManager.RenderPatchFile()); err != nil {
+		return gui.surfaceError(err)
+	}
+
+	gui.raiseToast(fmt.Sprintf(gui.Tr.PatchSavedToFile, path))
+	return nil
+}
+
+func (gui *Gui) handleCopyPatchToClipboard() error {
+	gui.logAction(gui.Tr.Actions.CopyPatchToClipboard)
+	if err := gui.OSCommand.CopyToClipboard(gui.Git.Patch.PatchManager.RenderPatchFile()); err != nil {
+		return gui.surfaceError(err)
+	}
+
+	gui.raiseToast(gui.Tr.PatchCopiedToClipboard)
+	return nil
+}
+
+func (gui *Gui) handleLoadPatchFromFile() error {
+	loadPatch := func() error {
+		return gui.prompt(promptOpts{
+			title:               gui.Tr.LoadPatchTitle,
+			findSuggestionsFunc: gui.getFilePathSuggestionsFunc(),
+			handleConfirm: func(path string) error {
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					return gui.surfaceError(err)
+				}
+
+				gui.logAction(gui.Tr.Actions.LoadPatchFromFile)
+				if !gui.Git.Patch.PatchManager.LoadPatch(path, string(content)) {
+					return gui.createErrorPanel(fmt.Sprintf(gui.Tr.NoDiffsInPatchFile, path))
+				}
+
+				return gui.switchToCommitFilesContext(path, false, gui.State.Contexts.BranchCommits, "commits")
+			},
+		})
+	}
+
+	if gui.Git.Patch.PatchManager.Active() && !gui.Git.Patch.PatchManager.IsEmpty() {
+		return gui.ask(askOpts{
+			title:         gui.Tr.DiscardPatch,
+			prompt:        gui.Tr.DiscardPatchConfirm,
+			handleConfirm: loadPatch,
+		})
+	}
+
+	return loadPatch()
+}
+
+// escapeLoadedPatch leaves the commit files panel once the loaded patch it was
+// showing is gone
+func (gui *Gui) escapeLoadedPatch() error {
+	if gui.currentSideContext().GetKey() != COMMIT_FILES_CONTEXT_KEY {
+		return nil
+	}
+
+	gui.escapeLineByLinePanel()
+
+	parentContext, hasParent := gui.State.Contexts.CommitFiles.GetParentContext()
+	if !hasParent {
+		return nil
+	}
+	return gui.pushContext(parentContext)
+}
+
+func (gui *Gui) handleApplyLoadedPatchToWorkingTree() error {
+	return gui.applyLoadedPatch(gui.Tr.Actions.ApplyPatchToWorkingTree)
+}
+
+func (gui *Gui) handleApplyLoadedPatchToIndex() error {
+	return gui.applyLoadedPatch(gui.Tr.Actions.ApplyPatchToIndex, "cached")
+}
+
+func (gui *Gui) applyLoadedPatch(action string, flags ...string) error {
+	if err := gui.returnFocusFromLineByLinePanelIfNecessary(); err != nil {
+		return err
+	}
+
+	gui.logAction(action)
+	if err := gui.Git.Patch.PatchManager.ApplyPatchesWithFallback(flags...); err != nil {
+		return gui.surfaceError(err)
+	}
+
+	return gui.refreshSidePanels(refreshOptions{mode: ASYNC})
+}
+
+func (gui *Gui) handleApplyLoadedPatchAsNewCommit() error {
+	// we commit whatever's in the index, so it had better only be the patch
+	if len(gui.stagedFiles()) > 0 {
+		return gui.createErrorPanel(gui.Tr.StagedChangesBeforePatchCommit)
+	}
+
+	return gui.prompt(promptOpts{
+		title: gui.Tr.CommitMessageTitle,
+		handleConfirm: func(message string) error {
+			if strings.TrimSpace(message) == "" {
+				return gui.createErrorPanel(gui.Tr.CommitWithoutMessageErr)
+			}
+
+			if err := gui.returnFocusFromLineByLinePanelIfNecessary(); err != nil {
+				return err
+			}
+
+			gui.logAction(gui.Tr.Actions.ApplyPatchAsNewCommit)
+			if err := gui.Git.Patch.PatchManager.ApplyPatchesWithFallback("cached"); err != nil {
+				return gui.surfaceError(err)
+			}
+
+			return gui.withGpgHandling(gui.Git.Commit.CommitCmdObj(message), gui.Tr.CommittingStatus, nil)
+		},
+	})
+}
@@ -7,8 +7,13 @@ import (
 )
 
 func (gui *Gui) handleCreatePatchOptionsMenu() error {
+	loadPatchMenuItem := &menuItem{
+		displayString: gui.Tr.LcLoadPatchFromFile,
+		onPress:       gui.handleLoadPatchFromFile,
+	}
+
 	if !gui.Git.Patch.PatchManager.Active() {
-		return gui.createErrorPanel(gui.Tr.NoPatchError)
+		return gui.createMenu(gui.Tr.PatchOptionsTitle, []*menuItem{loadPatchMenuItem}, createMenuOptions{showCancel: true})
 	}
 
 	menuItems := []*menuItem{
@@ -16,14 +21,34 @@ func (gui *Gui) handleCreatePatchOptionsMenu() error {
 			displayString: "reset patch",
 			onPress:       gui.handleResetPatch,
 		},
-		{
-			displayString: "apply patch",
-			onPress:       func() error { return gui.handleApplyPatch(false) },
-		},
-		{
-			displayString: "apply patch in reverse",
-			onPress:       func() error { return gui.handleApplyPatch(true) },
-		},
+	}
+
+	if gui.Git.Patch.PatchManager.LoadedFromFile() {
+		menuItems = append(menuItems, []*menuItem{
+			{
+				displayString: gui.Tr.LcApplyPatchToWorkingTree,
+				onPress:       gui.handleApplyLoadedPatchToWorkingTree,
+			},
+			{
+				displayString: gui.Tr.LcApplyPatchToIndex,
+				onPress:       gui.handleApplyLoadedPatchToIndex,
+			},
+			{
+				displayString: gui.Tr.LcApplyPatchAsNewCommit,
+				onPress:       gui.handleApplyLoadedPatchAsNewCommit,
+			},
+		}...)
+	} else {
+		menuItems = append(menuItems, []*menuItem{
+			{
+				displayString: "apply patch",
+				onPress:       func() error { return gui.handleApplyPatch(false) },
+			},
+			{
+				displayString: "apply patch in reverse",
+				onPress:       func() error { return gui.handleApplyPatch(true) },
+			},
+		}...)
 	}
 
 	if gui.Git.Patch.PatchManager.CanRebase && gui.Git.Status.WorkingTreeState() == enums.REBASE_MODE_NONE {
@@ -61,6 +86,18 @@ func (gui *Gui) handleCreatePatchOptionsMenu() error {
 		}
 	}
 
+	menuItems = append(menuItems, []*menuItem{
+		{
+			displayString: gui.Tr.LcSavePatchToFile,
+			onPress:       gui.handleSavePatchToFile,
+		},
+		{
+			displayString: gui.Tr.LcCopyPatchToClipboard,
+			onPress:       gui.handleCopyPatchToClipboard,
+		},
+		loadPatchMenuItem,
+	}...)
+
 	return gui.createMenu(gui.Tr.PatchOptionsTitle, menuItems, createMenuOptions{showCancel: true})
 }
 
@@ -186,7 +223,13 @@ func (gui *Gui) handleApplyPatch(reverse bool) error {
 }
 
 func (gui *Gui) handleResetPatch() error {
+	showingLoadedPatch := gui.showingLoadedPatch()
 	gui.Git.Patch.PatchManager.Reset()
+	if showingLoadedPatch {
+		// the files we're showing only exist in the patch, so there's nothing
+		// left to show
+		return gui.escapeLoadedPatch()
+	}
 	if gui.currentContextKeyIgnoringPopups() == MAIN_PATCH_BUILDING_CONTEXT_KEY {
 		if err := gui.pushContext(gui.State.Contexts.CommitFiles); err != nil {
 			return err
@@ -12,19 +12,20 @@ import (
 
 var branchPrefixColorCache = make(map[string]style.TextStyle)
 
-func GetBranchListDisplayStrings(branches []*models.Branch, fullDescription bool, diffName string) [][]string {
+// pullRequests maps the names of branches to their open pull requests
+func GetBranchListDisplayStrings(branches []*models.Branch, fullDescription bool, diffName string, pullRequests map[string]*models.PullRequest) [][]string {
 	lines := make([][]string, len(branches))
 
 	for i := range branches {
 		diffed := branches[i].Name == diffName
-		lines[i] = getBranchDisplayStrings(branches[i], fullDescription, diffed)
+		lines[i] = getBranchDisplayStrings(branches[i], fullDescription, diffed, pullRequests[branches[i].Name])
 	}
 
 	return lines
 }
 
 // getBranchDisplayStrings returns the display string of branch
-func getBranchDisplayStrings(b *models.Branch, fullDescription bool, diffed bool) []string {
+func getBranchDisplayStrings(b *models.Branch, fullDescription bool, diffed bool, pr *models.PullRequest) []string {
 	displayName := b.Name
 	if b.DisplayName != "" {
 		displayName = b.DisplayName
@@ -38,6 +39,9 @@ func getBranchDisplayStrings(b *models.Branch, fullDescription bool, diffed bool
 	if b.IsTrackingRemote() {
 		coloredName = fmt.Sprintf("%s %s", coloredName, ColoredBranchStatus(b))
 	}
+	if pr != nil {
+		coloredName = fmt.Sprintf("%s %s", coloredName, PullRequestStatus(pr))
+	}
 
 	recencyColor := style.FgCyan
 	if b.Recency == "  *" {
@@ -92,6 +96,30 @@ func BranchStatus(branch *models.Branch) string {
 	return fmt.Sprintf("↑%s↓%s", branch.Pushables, branch.Pullables)
 }
 
+// PullRequestStatus shows the pull request's number, coloured by its review
+// state, followed by its CI status
+func PullRequestStatus(pr *models.PullRequest) string {
+	numberStyle := theme.DefaultTextColor
+	switch pr.ReviewState {
+	case models.PR_REVIEW_APPROVED:
+		numberStyle = style.FgGreen
+	case models.PR_REVIEW_CHANGES_REQUESTED:
+		numberStyle = style.FgRed
+	}
+
+	result := numberStyle.Sprint(pr.RefName())
+	switch pr.CIStatus {
+	case models.PR_CI_SUCCESS:
+		result += " " + style.FgGreen.Sprint("✓")
+	case models.PR_CI_FAILURE:
+		result += " " + style.FgRed.Sprint("✗")
+	case models.PR_CI_PENDING:
+		result += " " + style.FgYellow.Sprint("●")
+	}
+
+	return result
+}
+
 func SetCustomBranches(customBranchColors map[string]string) {
 	branchPrefixColorCache = utils.SetCustomColors(customBranchColors)
 }
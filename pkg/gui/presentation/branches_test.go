@@ -0,0 +1,42 @@
+package presentation
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBranchListDisplayStringsWithPullRequests(t *testing.T) {
+	branches := []*models.Branch{
+		{Name: "feature", Recency: "  *", UpstreamRemote: "origin", UpstreamBranch: "feature", Pushables: "0", Pullables: "0"},
+		{Name: "bugfix", Recency: "1d"},
+		{Name: "chore", Recency: "2d"},
+	}
+	pullRequests := map[string]*models.PullRequest{
+		"feature": {Number: 12, ReviewState: models.PR_REVIEW_APPROVED, CIStatus: models.PR_CI_SUCCESS},
+		"bugfix":  {Number: 3},
+	}
+
+	assert.EqualValues(t, [][]string{
+		{"  *", "feature ↑0↓0 #12 ✓"},
+		{"1d", "bugfix #3"},
+		{"2d", "chore"},
+	}, GetBranchListDisplayStrings(branches, false, "", pullRequests))
+}
+
+func TestPullRequestStatus(t *testing.T) {
+	scenarios := []struct {
+		status   models.PullRequestCIStatus
+		expected string
+	}{
+		{models.PR_CI_NONE, "#7"},
+		{models.PR_CI_SUCCESS, "#7 ✓"},
+		{models.PR_CI_FAILURE, "#7 ✗"},
+		{models.PR_CI_PENDING, "#7 ●"},
+	}
+
+	for _, s := range scenarios {
+		assert.Equal(t, s.expected, PullRequestStatus(&models.PullRequest{Number: 7, CIStatus: s.status}))
+	}
+}
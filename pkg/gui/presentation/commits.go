@@ -9,6 +9,7 @@ import (
 	"github.com/jesseduffield/lazygit/pkg/gui/presentation/authors"
 	"github.com/jesseduffield/lazygit/pkg/gui/presentation/graph"
 	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/links"
 	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 	"github.com/kyokomi/emoji/v2"
@@ -38,6 +39,7 @@ func GetCommitListDisplayStrings(
 	length int,
 	showGraph bool,
 	bisectInfo *git_commands.BisectInfo,
+	linkifier *links.Linkifier,
 ) [][]string {
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -99,6 +101,7 @@ func GetCommitListDisplayStrings(
 			fullDescription,
 			bisectStatus,
 			bisectInfo,
+			linkifier,
 		))
 	}
 	return lines
@@ -240,6 +243,7 @@ func displayCommit(
 	fullDescription bool,
 	bisectStatus BisectStatus,
 	bisectInfo *git_commands.BisectInfo,
+	linkifier *links.Linkifier,
 ) []string {
 	shaColor := getShaColor(commit, diffName, cherryPickedCommitShaMap, bisectStatus, bisectInfo)
 	bisectString := getBisectStatusText(bisectStatus, bisectInfo)
@@ -280,7 +284,7 @@ func displayCommit(
 		cols,
 		actionString,
 		authorFunc(commit.Author),
-		graphLine+tagString+theme.DefaultTextColor.Sprint(name),
+		graphLine+tagString+linkifier.Highlight(name, theme.DefaultTextColor),
 	)
 
 	return cols
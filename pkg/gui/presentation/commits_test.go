@@ -217,6 +217,7 @@ func TestGetCommitListDisplayStrings(t *testing.T) {
 					s.length,
 					s.showGraph,
 					s.bisectInfo,
+					nil,
 				)
 
 				renderedResult := utils.RenderDisplayStrings(result)
@@ -160,6 +160,10 @@ func getFileLine(hasUnstagedChanges bool, hasStagedChanges bool, name string, di
 		output += theme.DefaultTextColor.Sprint(" (submodule)")
 	}
 
+	if file != nil && file.ResolvedByRerere {
+		output += theme.DefaultTextColor.Sprint(" (resolved by rerere)")
+	}
+
 	return output
 }
 
@@ -0,0 +1,41 @@
+package presentation
+
+import (
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/gui/presentation/authors"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+)
+
+func GetPullRequestListDisplayStrings(pullRequests []*models.PullRequest, diffName string) [][]string {
+	lines := make([][]string, len(pullRequests))
+
+	for i := range pullRequests {
+		diffed := pullRequests[i].LocalRef != "" && pullRequests[i].LocalRef == diffName
+		lines[i] = getPullRequestDisplayStrings(pullRequests[i], diffed)
+	}
+
+	return lines
+}
+
+func getPullRequestDisplayStrings(pr *models.PullRequest, diffed bool) []string {
+	titleStyle := theme.DefaultTextColor
+	if diffed {
+		titleStyle = theme.DiffTerminalColor
+	}
+
+	return []string{
+		PullRequestStatus(pr),
+		titleStyle.Sprint(pr.Title),
+		authors.AuthorStyle(pr.Author).Sprint(pr.Author),
+		style.FgYellow.Sprint(PullRequestBranches(pr)),
+	}
+}
+
+// PullRequestBranches shows where the pull request is from and to, if we know
+func PullRequestBranches(pr *models.PullRequest) string {
+	if pr.HeadBranch == "" {
+		return ""
+	}
+	return pr.HeadBranch + " → " + pr.BaseBranch
+}
@@ -0,0 +1,21 @@
+package presentation
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPullRequestListDisplayStrings(t *testing.T) {
+	pullRequests := []*models.PullRequest{
+		{Number: 12, Title: "Add a feature", Author: "Jesse", HeadBranch: "feature", BaseBranch: "master", CIStatus: models.PR_CI_FAILURE},
+		// from the refs we fetched, so we don't know the branches
+		{Number: 5, Title: "Fix a bug", Author: "Mark", LocalRef: "refs/pull/5/head"},
+	}
+
+	assert.EqualValues(t, [][]string{
+		{"#12 ✗", "Add a feature", "Jesse", "feature → master"},
+		{"#5", "Fix a bug", "Mark", ""},
+	}, GetPullRequestListDisplayStrings(pullRequests, "refs/pull/5/head"))
+}
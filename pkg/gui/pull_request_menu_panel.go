@@ -73,5 +73,6 @@ func (gui *Gui) createPullRequest(from string, to string) error {
 func (gui *Gui) getHostingServiceMgr() *hosting_service.HostingServiceMgr {
 	remoteUrl := gui.Git.Config.GetRemoteURL()
 	configServices := gui.UserConfig.Services
-	return hosting_service.NewHostingServiceMgr(gui.Log, gui.Tr, remoteUrl, configServices)
+	configURLTemplates := gui.UserConfig.HostingService.URLTemplates
+	return hosting_service.NewHostingServiceMgr(gui.Log, gui.Tr, remoteUrl, configServices, configURLTemplates)
 }
@@ -0,0 +1,170 @@
+package gui
+
+import (
+	"time"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/jesseduffield/lazygit/pkg/commands/hosting_service"
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// If the user has enabled it, we ask the hosting service's API about the
+// repo's open pull requests in the background, so that we can show the pull
+// request for each branch in the branches panel. Anything going wrong here
+// gets logged rather than shown, because the user didn't ask for it just now.
+
+func (gui *Gui) startBackgroundPullRequestRefresh() {
+	if !gui.UserConfig.HostingService.PullRequests {
+		// the user may have just turned it off, in which case we leave the pull
+		// requests to the refs that they fetch
+		gui.OnUIThread(func() error {
+			gui.State.PullRequests = nil
+			gui.State.LoadedPullRequests = false
+			gui.refreshSelectedLine(gui.State.Panels.PullRequests, 0)
+			return gui.rerenderPullRequests()
+		})
+		return
+	}
+
+	go utils.Safe(func() { _ = gui.refreshPullRequests() })
+	gui.goEvery(time.Second*time.Duration(gui.UserConfig.Refresher.PullRequestInterval), gui.refresherStopChan, gui.refreshPullRequests)
+}
+
+func (gui *Gui) getPullRequestClient() (hosting_service.PullRequestClient, error) {
+	hostingServiceMgr := gui.getHostingServiceMgr()
+	webDomain, err := hostingServiceMgr.GetWebDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	hostingServiceConfig := gui.UserConfig.HostingService
+	auth := hosting_service.Auth{Token: hostingServiceConfig.Tokens[webDomain]}
+	if auth.Token == "" {
+		auth = gui.getHostingServiceCredential(webDomain)
+	}
+
+	return hostingServiceMgr.GetPullRequestClient(auth, hostingServiceConfig.APIBaseURLs[webDomain])
+}
+
+// getHostingServiceCredential asks git's credential helper for a username and
+// password for the web domain, the first time we need one. We refresh pull
+// requests every so often, and there's no sense asking again each time,
+// especially for a public repo where the helper has nothing to give us.
+func (gui *Gui) getHostingServiceCredential(webDomain string) hosting_service.Auth {
+	gui.Mutexes.CredentialsMutex.Lock()
+	defer gui.Mutexes.CredentialsMutex.Unlock()
+
+	if auth, ok := gui.hostingServiceCredentials[webDomain]; ok {
+		return auth
+	}
+
+	// public repos don't need a token, so we can carry on without one
+	username, password, err := gui.Git.Remote.Credential(webDomain)
+	if err != nil {
+		gui.Log.Warn(err)
+	}
+
+	auth := hosting_service.Auth{Username: username, Token: password}
+	gui.hostingServiceCredentials[webDomain] = auth
+	return auth
+}
+
+func (gui *Gui) refreshPullRequests() error {
+	// the user may switch repos while we're waiting on the API, in which case
+	// we leave the new repo's state alone
+	state := gui.State
+
+	branches := state.Branches
+	if len(branches) == 0 {
+		// we haven't loaded the branches yet
+		var err error
+		branches, err = gui.Git.Loaders.Branches.Load(nil)
+		if err != nil {
+			gui.Log.Error(err)
+			return err
+		}
+	}
+
+	client, err := gui.getPullRequestClient()
+	if err != nil {
+		gui.Log.Error(err)
+		return err
+	}
+
+	detailedBranches := make([]string, len(branches))
+	for i, branch := range branches {
+		detailedBranches[i] = pullRequestHeadBranch(branch)
+	}
+
+	pullRequests, err := client.GetPullRequests(detailedBranches)
+	if err != nil {
+		gui.Log.Error(err)
+		return err
+	}
+
+	// if the service keeps pull requests in refs, we can check them out and
+	// diff them once we've fetched those
+	if namespace, err := gui.getHostingServiceMgr().GetPullRequestRefNamespace(); err == nil {
+		for _, pr := range pullRequests {
+			pr.LocalRef = git_commands.PullRequestRef(namespace, pr.Number)
+		}
+	}
+
+	gui.OnUIThread(func() error {
+		state.PullRequests = pullRequests
+		gui.refreshSelectedLine(state.Panels.PullRequests, len(pullRequests))
+		if gui.State != state {
+			return nil
+		}
+		return gui.rerenderPullRequests()
+	})
+
+	return nil
+}
+
+// rerenderPullRequests re-renders whichever of the local branches and pull
+// requests is showing in the branches view. Unlike postRefreshUpdate, we don't
+// refocus the panel, because that would reload the main view every time.
+func (gui *Gui) rerenderPullRequests() error {
+	for _, context := range []Context{gui.State.Contexts.Branches, gui.State.Contexts.PullRequests} {
+		view, err := gui.g.View(context.GetViewName())
+		if err != nil || ContextKey(view.Context) != context.GetKey() {
+			continue
+		}
+		if err := context.HandleRender(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pullRequestHeadBranch returns the name that a pull request for the branch
+// would have as its head branch
+func pullRequestHeadBranch(branch *models.Branch) string {
+	if branch.IsTrackingRemote() {
+		return branch.UpstreamBranch
+	}
+	return branch.Name
+}
+
+// pullRequestsByBranch maps the names of local branches to their open pull
+// requests
+func pullRequestsByBranch(branches []*models.Branch, pullRequests []*models.PullRequest) map[string]*models.PullRequest {
+	byHeadBranch := map[string]*models.PullRequest{}
+	for _, pr := range pullRequests {
+		// a fork's branch isn't ours just because it has the same name. If more
+		// than one pull request has our branch as its head, we go with the first.
+		if _, ok := byHeadBranch[pr.HeadBranch]; !ok && !pr.IsFromFork {
+			byHeadBranch[pr.HeadBranch] = pr
+		}
+	}
+
+	result := map[string]*models.PullRequest{}
+	for _, branch := range branches {
+		if pr, ok := byHeadBranch[pullRequestHeadBranch(branch)]; ok {
+			result[branch.Name] = pr
+		}
+	}
+	return result
+}
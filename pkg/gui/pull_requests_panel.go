@@ -0,0 +1,249 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/gui/presentation"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// The pull requests tab lists the repo's open pull requests if we're asking the
+// hosting service's API about them (see pull_requests.go). Otherwise it lists
+// the pull requests whose refs we've fetched from the remote, which may include
+// closed ones, because the refs tell us nothing about that. Fetching every pull
+// request's ref can take a while on a big repo, so we only do it when the user
+// asks us to.
+
+// pullRequestsRemote returns the remote we fetch pull requests from, which is
+// the one whose URL tells us the hosting service
+func (gui *Gui) pullRequestsRemote() string {
+	return gui.Git.Config.GetHostingServiceRemote()
+}
+
+func (gui *Gui) getSelectedPullRequest() *models.PullRequest {
+	selectedLine := gui.State.Panels.PullRequests.SelectedLineIdx
+	// the list is replaced in the background, so the selection may not have
+	// caught up with it yet
+	if selectedLine == -1 || selectedLine >= len(gui.State.PullRequests) {
+		return nil
+	}
+
+	return gui.State.PullRequests[selectedLine]
+}
+
+func (gui *Gui) withSelectedPullRequest(f func(pr *models.PullRequest) error) func() error {
+	return func() error {
+		pr := gui.getSelectedPullRequest()
+		if pr == nil {
+			return nil
+		}
+
+		return f(pr)
+	}
+}
+
+func (gui *Gui) onPullRequestsFocus() error {
+	if gui.State.LoadedPullRequests || gui.UserConfig.HostingService.PullRequests {
+		return nil
+	}
+
+	// without the API, we list whatever pull request refs have been fetched
+	// before, which doesn't need the network
+	gui.State.LoadedPullRequests = true
+	go utils.Safe(func() {
+		// the user didn't ask for this, so if e.g. the hosting service is
+		// unknown we leave the tab empty rather than showing an error
+		if err := gui.loadFetchedPullRequests(); err != nil {
+			gui.Log.Error(err)
+		}
+	})
+	return nil
+}
+
+func (gui *Gui) pullRequestsRenderToMain() error {
+	var task updateTask
+	pr := gui.getSelectedPullRequest()
+	if pr == nil && gui.UserConfig.HostingService.PullRequests {
+		task = NewRenderStringTask(gui.Tr.NoPullRequests)
+	} else if pr == nil {
+		task = NewRenderStringTask(fmt.Sprintf(
+			gui.Tr.NoFetchedPullRequests,
+			gui.getKeyDisplay(gui.UserConfig.Keybinding.Branches.FetchRemote),
+			gui.pullRequestsRemote(),
+		))
+	} else {
+		task = NewRenderStringTask(gui.pullRequestSummary(pr))
+	}
+
+	return gui.refreshMainViews(refreshMainOpts{
+		main: &viewUpdateOpts{
+			title: "Pull Request",
+			task:  task,
+		},
+	})
+}
+
+func (gui *Gui) pullRequestSummary(pr *models.PullRequest) string {
+	lines := []string{pr.Description()}
+	if pr.IsDraft {
+		lines = append(lines, gui.Tr.PullRequestDraft)
+	}
+	lines = append(lines, fmt.Sprintf("%s: %s", gui.Tr.PullRequestAuthor, pr.Author))
+	if branches := presentation.PullRequestBranches(pr); branches != "" {
+		lines = append(lines, branches)
+	}
+	if pr.ReviewState != models.PR_REVIEW_NONE {
+		lines = append(lines, fmt.Sprintf("%s: %s", gui.Tr.PullRequestReview, pr.ReviewState))
+	}
+	if pr.CIStatus != models.PR_CI_NONE {
+		lines = append(lines, fmt.Sprintf("%s: %s", gui.Tr.PullRequestCI, pr.CIStatus))
+	}
+	if pr.URL != "" {
+		lines = append(lines, "", pr.URL)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// fetchPullRequests fetches the refs of the pull requests we know of from the
+// API, or failing that, of all the pull requests on the remote, and lists the
+// latter
+func (gui *Gui) fetchPullRequests(background bool) error {
+	namespace, err := gui.getHostingServiceMgr().GetPullRequestRefNamespace()
+	if err != nil {
+		return err
+	}
+
+	usingAPI := gui.UserConfig.HostingService.PullRequests
+	opts := git_commands.FetchPullRequestsOptions{
+		Background: background,
+		RemoteName: gui.pullRequestsRemote(),
+		Namespace:  namespace,
+	}
+	if usingAPI {
+		for _, pr := range gui.State.PullRequests {
+			opts.Numbers = append(opts.Numbers, pr.Number)
+		}
+		if len(opts.Numbers) == 0 {
+			return nil
+		}
+	}
+
+	gui.Mutexes.FetchMutex.Lock()
+	err = gui.Git.Sync.FetchPullRequests(opts)
+	gui.Mutexes.FetchMutex.Unlock()
+	if background {
+		if err != nil {
+			return err
+		}
+	} else {
+		gui.handleCredentialsPopup(err)
+		if err != nil {
+			return nil
+		}
+	}
+
+	if usingAPI {
+		return nil
+	}
+
+	return gui.loadFetchedPullRequests()
+}
+
+// loadFetchedPullRequests lists the pull requests whose refs we've fetched
+func (gui *Gui) loadFetchedPullRequests() error {
+	namespace, err := gui.getHostingServiceMgr().GetPullRequestRefNamespace()
+	if err != nil {
+		return err
+	}
+
+	pullRequests, err := gui.Git.Loaders.PullRequests.Load(namespace)
+	if err != nil {
+		return err
+	}
+
+	state := gui.State
+	gui.OnUIThread(func() error {
+		state.PullRequests = pullRequests
+		gui.refreshSelectedLine(state.Panels.PullRequests, len(pullRequests))
+		if gui.State != state {
+			return nil
+		}
+		return gui.postRefreshUpdate(gui.State.Contexts.PullRequests)
+	})
+	return nil
+}
+
+func (gui *Gui) handleFetchPullRequests() error {
+	return gui.WithWaitingStatus(gui.Tr.FetchingPullRequestsStatus, func() error {
+		gui.logAction(gui.Tr.Actions.FetchPullRequests)
+		return gui.fetchPullRequests(false)
+	})
+}
+
+// fetchPullRequestHead fetches the head of the pull request and returns the ref
+// we fetched it into. If it returns false, it's already shown an error.
+func (gui *Gui) fetchPullRequestHead(pr *models.PullRequest) (string, bool) {
+	namespace, err := gui.getHostingServiceMgr().GetPullRequestRefNamespace()
+	if err != nil {
+		_ = gui.surfaceError(err)
+		return "", false
+	}
+
+	gui.Mutexes.FetchMutex.Lock()
+	err = gui.Git.Sync.FetchPullRequests(git_commands.FetchPullRequestsOptions{
+		RemoteName: gui.pullRequestsRemote(),
+		Namespace:  namespace,
+		Numbers:    []int{pr.Number},
+	})
+	gui.Mutexes.FetchMutex.Unlock()
+	gui.handleCredentialsPopup(err)
+	if err != nil {
+		return "", false
+	}
+
+	return git_commands.PullRequestRef(namespace, pr.Number), true
+}
+
+func (gui *Gui) handleCheckoutPullRequest(pr *models.PullRequest) error {
+	return gui.WithWaitingStatus(gui.Tr.FetchingPullRequestsStatus, func() error {
+		ref, ok := gui.fetchPullRequestHead(pr)
+		if !ok {
+			return nil
+		}
+
+		gui.logAction(gui.Tr.Actions.CheckoutPullRequest)
+		return gui.handleCheckoutRef(ref, handleCheckoutRefOptions{})
+	})
+}
+
+// handleDiffPullRequest enters diffing mode with the pull request's merge base
+// with its target branch, so that the pull request shows the changes it makes
+func (gui *Gui) handleDiffPullRequest(pr *models.PullRequest) error {
+	return gui.WithWaitingStatus(gui.Tr.FetchingPullRequestsStatus, func() error {
+		ref, ok := gui.fetchPullRequestHead(pr)
+		if !ok {
+			return nil
+		}
+
+		// without the API we don't know the target, so we go with the remote's
+		// default branch
+		remote := gui.pullRequestsRemote()
+		target := remote + "/HEAD"
+		if pr.BaseBranch != "" {
+			target = remote + "/" + pr.BaseBranch
+		}
+
+		mergeBase, err := gui.Git.Branch.MergeBase(target, ref)
+		if err != nil {
+			return err
+		}
+
+		gui.State.Modes.Diffing.Ref = mergeBase
+		gui.State.Modes.Diffing.Reverse = false
+		return gui.refreshSidePanels(refreshOptions{mode: ASYNC})
+	})
+}
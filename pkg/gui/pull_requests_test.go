@@ -0,0 +1,28 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequestsByBranch(t *testing.T) {
+	branches := []*models.Branch{
+		// tracking a remote branch with a different name
+		{Name: "feature", UpstreamRemote: "origin", UpstreamBranch: "jesse/feature"},
+		{Name: "bugfix"},
+		{Name: "chore"},
+		// its pull request is for the remote branch of the same name
+		{Name: "docs", UpstreamRemote: "origin", UpstreamBranch: "other"},
+	}
+	featurePR := &models.PullRequest{Number: 1, HeadBranch: "jesse/feature"}
+	bugfixPR := &models.PullRequest{Number: 2, HeadBranch: "bugfix"}
+	forkPR := &models.PullRequest{Number: 3, HeadBranch: "chore", IsFromFork: true}
+	docsPR := &models.PullRequest{Number: 4, HeadBranch: "docs"}
+
+	assert.Equal(t, map[string]*models.PullRequest{
+		"feature": featurePR,
+		"bugfix":  bugfixPR,
+	}, pullRequestsByBranch(branches, []*models.PullRequest{featurePR, bugfixPR, forkPR, docsPR}))
+}
@@ -34,6 +34,8 @@ func (gui *Gui) handleCreateRebaseOptionsMenu() error {
 		}
 	}
 
+	menuItems = append(menuItems, gui.rerereMenuItems()...)
+
 	var title string
 	if gui.Git.Status.WorkingTreeState() == enums.REBASE_MODE_MERGING {
 		title = gui.Tr.MergeOptionsTitle
@@ -53,6 +55,9 @@ func (gui *Gui) genericMergeCommand(command string) error {
 
 	gui.logAction(fmt.Sprintf("Merge/Rebase: %s", command))
 
+	// whatever rerere resolved belongs to the step we're moving on from
+	gui.State.RerereResolvedPaths = map[string]bool{}
+
 	commandType := ""
 	switch status {
 	case enums.REBASE_MODE_MERGING:
@@ -72,7 +77,16 @@ func (gui *Gui) genericMergeCommand(command string) error {
 			gui.Git.Rebase.GenericMergeOrRebaseActionCmdObj(commandType, command),
 		)
 	}
-	result := gui.Git.Rebase.GenericMergeOrRebaseAction(commandType, command)
+	runCommand := func() error {
+		return gui.Git.Rebase.GenericMergeOrRebaseAction(commandType, command)
+	}
+	var result error
+	if status == enums.REBASE_MODE_REBASING && command != REBASE_OPTION_ABORT {
+		// continuing or skipping moves the rebase on a step, which we can undo
+		result = gui.withRebaseJournal(fmt.Sprintf("Merge/Rebase: %s", command), true, runCommand)
+	} else {
+		result = runCommand()
+	}
 	if err := gui.handleGenericMergeCommandResult(result); err != nil {
 		return err
 	}
@@ -97,6 +111,24 @@ func isMergeConflictErr(errStr string) bool {
 }
 
 func (gui *Gui) handleGenericMergeCommandResult(result error) error {
+	// rerere may have resolved every conflict already, in which case there's
+	// nothing left for the user to do but continue. The files are staged by the
+	// time we refresh, and the refresh is what asks the user to continue, so
+	// that we only ever ask once.
+	resolvedByRerere := false
+	if result != nil && isMergeConflictErr(result.Error()) {
+		var err error
+		resolvedByRerere, err = gui.resolvedAllConflictsWithRerere(result.Error())
+		if err != nil {
+			return gui.surfaceError(err)
+		}
+		if resolvedByRerere {
+			gui.Mutexes.RefreshingFilesMutex.Lock()
+			gui.State.RerereResolvedAllConflicts = true
+			gui.Mutexes.RefreshingFilesMutex.Unlock()
+		}
+	}
+
 	if err := gui.refreshSidePanels(refreshOptions{mode: ASYNC}); err != nil {
 		return err
 	}
@@ -110,6 +142,10 @@ func (gui *Gui) handleGenericMergeCommandResult(result error) error {
 		// assume in this case that we're already done
 		return nil
 	} else if isMergeConflictErr(result.Error()) {
+		if resolvedByRerere {
+			return nil
+		}
+
 		return gui.ask(askOpts{
 			title:               gui.Tr.FoundConflictsTitle,
 			prompt:              gui.Tr.FoundConflicts,
@@ -24,7 +24,7 @@ func (gui *Gui) reflogCommitsRenderToMain() error {
 	} else {
 		cmdObj := gui.Git.Commit.ShowCmdObj(commit.Sha, gui.State.Modes.Filtering.GetPath())
 
-		task = NewRunPtyTask(cmdObj.GetCmd())
+		task = gui.newDiffTask(cmdObj)
 	}
 
 	return gui.refreshMainViews(refreshMainOpts{
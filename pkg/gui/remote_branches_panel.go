@@ -24,8 +24,12 @@ func (gui *Gui) remoteBranchesRenderToMain() error {
 	if remoteBranch == nil {
 		task = NewRenderStringTask("No branches for this remote")
 	} else {
-		cmdObj := gui.Git.Branch.GetGraphCmdObj(remoteBranch.FullName())
-		task = NewRunCommandTask(cmdObj.GetCmd())
+		cmdObj, err := gui.Git.Branch.GetGraphCmdObj(remoteBranch.FullName())
+		if err != nil {
+			task = NewRenderStringTask(err.Error())
+		} else {
+			task = NewRunCommandTask(cmdObj.GetCmd())
+		}
 	}
 
 	return gui.refreshMainViews(refreshMainOpts{
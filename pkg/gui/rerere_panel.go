@@ -0,0 +1,213 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/jesseduffield/lazygit/pkg/commands/loaders"
+	"github.com/jesseduffield/lazygit/pkg/commands/models"
+	"github.com/jesseduffield/lazygit/pkg/gui/mergeconflicts"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// When rerere is enabled, git resolves conflicts it has seen before by replaying
+// the recorded resolution, but it leaves the files unmerged unless
+// rerere.autoUpdate is set. We stage those files for the user and mark them in
+// the files panel until the merge/rebase is over.
+
+// stageRerereResolvedFiles stages the conflicted files which rerere has resolved,
+// returning the paths of every file it resolved. We only take rerere's word for
+// it when git has told us so, either in the output of the command that ran into
+// the conflicts or by having a recorded resolution for the path in MERGE_RR.
+func (gui *Gui) stageRerereResolvedFiles(reportedPaths []string) ([]string, error) {
+	recordedPaths, err := gui.Git.Rerere.MergeRRResolvedPaths()
+	if err != nil {
+		return nil, err
+	}
+	candidatePaths := utils.Uniq(append(reportedPaths, recordedPaths...))
+	if len(candidatePaths) == 0 {
+		return nil, nil
+	}
+
+	resolvedPaths := []string{}
+	pathsToStage := []string{}
+	for _, file := range gui.Git.Loaders.Files.GetStatusFiles(loaders.GetStatusFileOptions{}) {
+		if !utils.IncludesString(candidatePaths, file.Name) {
+			continue
+		}
+
+		if !file.HasMergeConflicts {
+			// git stages the files itself when rerere.autoUpdate is set
+			if utils.IncludesString(reportedPaths, file.Name) {
+				resolvedPaths = append(resolvedPaths, file.Name)
+			}
+			continue
+		}
+
+		// the user may have started editing the file since, so we only stage
+		// it if the markers are actually gone
+		hasConflicts, err := mergeconflicts.FileHasConflictMarkers(file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !hasConflicts {
+			resolvedPaths = append(resolvedPaths, file.Name)
+			pathsToStage = append(pathsToStage, file.Name)
+		}
+	}
+
+	if len(resolvedPaths) == 0 {
+		return nil, nil
+	}
+
+	// we hold the files mutex so that a refresh can't see the files unmerged
+	// after we've marked them, or staged before we've marked them
+	gui.Mutexes.RefreshingFilesMutex.Lock()
+	defer gui.Mutexes.RefreshingFilesMutex.Unlock()
+
+	for _, path := range resolvedPaths {
+		gui.State.RerereResolvedPaths[path] = true
+	}
+
+	if len(pathsToStage) > 0 {
+		gui.logAction(gui.Tr.Actions.StageRerereResolvedFiles)
+		if err := gui.Git.WorkingTree.StageFiles(pathsToStage); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolvedPaths, nil
+}
+
+func (gui *Gui) rerereMenuItems() []*menuItem {
+	enabled := gui.Git.Rerere.Enabled()
+
+	toggleLabel := gui.Tr.LcEnableRerere
+	if enabled {
+		toggleLabel = gui.Tr.LcDisableRerere
+	}
+
+	menuItems := []*menuItem{
+		{
+			displayString: toggleLabel,
+			onPress: func() error {
+				gui.logAction(gui.Tr.Actions.ToggleRerere)
+				if err := gui.Git.Rerere.SetEnabled(!enabled); err != nil {
+					return gui.surfaceError(err)
+				}
+				return nil
+			},
+		},
+		{
+			displayString: gui.Tr.LcListRecordedResolutions,
+			onPress:       gui.handleListRecordedResolutions,
+			opensMenu:     true,
+		},
+	}
+
+	file := gui.getSelectedFile()
+	if enabled && file != nil && (file.HasInlineMergeConflicts || file.ResolvedByRerere) {
+		menuItems = append(menuItems, &menuItem{
+			displayString: fmt.Sprintf(gui.Tr.LcForgetRecordedResolution, file.Name),
+			onPress: func() error {
+				return gui.handleForgetRecordedResolution(file.Name)
+			},
+		})
+	}
+
+	return menuItems
+}
+
+func (gui *Gui) handleForgetRecordedResolution(path string) error {
+	return gui.ask(askOpts{
+		title:  gui.Tr.ForgetRecordedResolution,
+		prompt: fmt.Sprintf(gui.Tr.ForgetRecordedResolutionPrompt, path),
+		handleConfirm: func() error {
+			gui.logAction(gui.Tr.Actions.ForgetRecordedResolution)
+			if err := gui.Git.Rerere.Forget(path); err != nil {
+				return gui.surfaceError(err)
+			}
+			delete(gui.State.RerereResolvedPaths, path)
+
+			return gui.refreshSidePanels(refreshOptions{mode: ASYNC, scope: []RefreshableView{FILES}})
+		},
+	})
+}
+
+func (gui *Gui) handleListRecordedResolutions() error {
+	resolutions, err := gui.Git.Rerere.RecordedResolutions()
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	if len(resolutions) == 0 {
+		return gui.createErrorPanel(gui.Tr.NoRecordedResolutions)
+	}
+
+	menuItems := make([]*menuItem, len(resolutions))
+	for i, resolution := range resolutions {
+		resolution := resolution
+
+		status := style.FgYellow.Sprint(gui.Tr.LcRecordedResolutionUnresolved)
+		if resolution.Resolved {
+			status = style.FgGreen.Sprint(gui.Tr.LcRecordedResolutionResolved)
+		}
+
+		menuItems[i] = &menuItem{
+			displayStrings: []string{
+				style.FgBlue.Sprint(utils.UnixToTimeAgo(resolution.RecordedAt.Unix())),
+				status,
+				resolution.Preview,
+			},
+			onPress: func() error {
+				return gui.handleDeleteRecordedResolution(resolution)
+			},
+		}
+	}
+
+	return gui.createMenu(gui.Tr.RecordedResolutionsTitle, menuItems, createMenuOptions{showCancel: true})
+}
+
+func (gui *Gui) handleDeleteRecordedResolution(resolution *models.RecordedResolution) error {
+	return gui.ask(askOpts{
+		title:  gui.Tr.DeleteRecordedResolution,
+		prompt: gui.Tr.DeleteRecordedResolutionPrompt,
+		handleConfirm: func() error {
+			gui.logAction(gui.Tr.Actions.DeleteRecordedResolution)
+			gui.logCommand(fmt.Sprintf("Deleting rr-cache entry %s", resolution.ID), false)
+			if err := gui.Git.Rerere.DeleteRecordedResolution(resolution.ID); err != nil {
+				return gui.surfaceError(err)
+			}
+			return nil
+		},
+	})
+}
+
+// resolvedAllConflictsWithRerere takes the output of a merge command which ran
+// into conflicts, stages the files rerere resolved and tells us whether that
+// leaves any conflicts for the user to resolve. It stages the files before
+// returning, so a refresh afterwards will find them resolved.
+func (gui *Gui) resolvedAllConflictsWithRerere(output string) (bool, error) {
+	if !gui.Git.Rerere.Enabled() {
+		return false, nil
+	}
+
+	resolvedPaths, err := gui.stageRerereResolvedFiles(git_commands.ParseRerereResolvedPaths(output))
+	if err != nil {
+		return false, err
+	}
+	if len(resolvedPaths) == 0 {
+		return false, nil
+	}
+
+	// the resolved files are staged by now, so anything still unmerged is
+	// left for the user
+	for _, file := range gui.Git.Loaders.Files.GetStatusFiles(loaders.GetStatusFileOptions{}) {
+		if file.HasMergeConflicts {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -1,9 +1,15 @@
 package gui
 
 import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
+	"github.com/jesseduffield/lazygit/pkg/commands/oscommands"
 	"github.com/jesseduffield/lazygit/pkg/commands/patch"
+	"github.com/jesseduffield/lazygit/pkg/gui/lbl"
+	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 func (gui *Gui) refreshStagingPanel(forceSecondaryFocused bool, selectedLineIdx int) error {
@@ -47,6 +53,10 @@ func (gui *Gui) refreshStagingPanel(forceSecondaryFocused bool, selectedLineIdx
 		diff, secondaryDiff = secondaryDiff, diff
 	}
 
+	if !secondaryFocused {
+		diff = patch.SplitHunksAt(diff, gui.State.HunkSplits[file.Name])
+	}
+
 	empty, err := gui.refreshLineByLinePanel(diff, secondaryDiff, secondaryFocused, selectedLineIdx)
 	if err != nil {
 		return err
@@ -94,6 +104,7 @@ func (gui *Gui) handleTogglePanel() error {
 
 func (gui *Gui) handleStagingEscape() error {
 	gui.escapeLineByLinePanel()
+	gui.State.HunkSplits = map[string][]int{}
 
 	return gui.pushContext(gui.State.Contexts.Files)
 }
@@ -147,7 +158,17 @@ func (gui *Gui) applySelection(reverse bool, state *LblPanelState) error {
 		applyFlags = append(applyFlags, "cached")
 	}
 	gui.logAction(gui.Tr.Actions.ApplyPatch)
-	err := gui.Git.WorkingTree.ApplyPatch(patch, applyFlags...)
+	applyPatch := func() error {
+		return gui.Git.WorkingTree.ApplyPatch(patch, applyFlags...)
+	}
+	description := journalDescription(gui.Tr.Actions.ApplyPatch, file.Name)
+	var err error
+	if len(applyFlags) > 0 {
+		err = gui.withIndexJournal(description, applyPatch)
+	} else {
+		// we're discarding lines from the working tree
+		err = gui.withWorkingTreeJournal(description, []string{file.Name}, applyPatch)
+	}
 	if err != nil {
 		return gui.surfaceError(err)
 	}
@@ -164,3 +185,104 @@ func (gui *Gui) applySelection(reverse bool, state *LblPanelState) error {
 	}
 	return nil
 }
+
+// handleEditHunk lets the user edit the selected hunk in their editor, like
+// `git add -e`, and then stages (or, from the staged side, unstages) the
+// result
+func (gui *Gui) handleEditHunk() error {
+	file := gui.getSelectedFile()
+	if file == nil {
+		return nil
+	}
+
+	hunkPatch := ""
+	err := gui.withLBLActiveCheck(func(state *LblPanelState) error {
+		hunk := state.CurrentHunk()
+		// on the staged side we hand over the reverse patch, so that applying
+		// the edited hunk to the index unstages it
+		hunkPatch = patch.ModifiedPatchForRange(gui.Log, file.Name, state.GetDiff(), hunk.FirstLineIdx, hunk.LastLineIdx(), state.SecondaryFocused, false)
+		return nil
+	})
+	if err != nil || hunkPatch == "" {
+		return err
+	}
+
+	instructions := ""
+	for _, line := range strings.Split(gui.Tr.EditHunkInstructions, "\n") {
+		instructions += patch.HunkEditCommentChar + " " + line + "\n"
+	}
+
+	path := filepath.Join(oscommands.GetTempDir(), utils.GetCurrentRepoName(), "EDIT_HUNK.patch")
+	if err := gui.OSCommand.CreateFileWithContent(path, hunkPatch+instructions); err != nil {
+		return gui.surfaceError(err)
+	}
+
+	return gui.editHunkFile(path)
+}
+
+// editHunkFile opens the hunk in the user's editor and applies the result to
+// the index, offering to go back to the editor if it doesn't apply
+func (gui *Gui) editHunkFile(path string) error {
+	cmdStr, err := gui.Git.File.GetEditCmdStr(path, 1)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	gui.logAction(gui.Tr.Actions.EditHunk)
+	if ok, err := gui.runSubprocessWithSuspense(gui.OSCommand.Cmd.NewShell(cmdStr)); !ok {
+		return err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return gui.surfaceError(err)
+	}
+
+	hunkPatch, err := patch.RecountHunks(string(content))
+	if err == nil && hunkPatch != "" {
+		err = gui.withIndexJournal(gui.Tr.Actions.EditHunk, func() error {
+			return gui.Git.WorkingTree.ApplyPatch(hunkPatch, "cached")
+		})
+	}
+	if err != nil {
+		return gui.ask(askOpts{
+			title:  gui.Tr.EditHunkFailedTitle,
+			prompt: fmt.Sprintf(gui.Tr.EditHunkFailedPrompt, strings.TrimSpace(err.Error())),
+			handleConfirm: func() error {
+				return gui.editHunkFile(path)
+			},
+		})
+	}
+
+	if err := gui.refreshSidePanels(refreshOptions{scope: []RefreshableView{FILES}}); err != nil {
+		return err
+	}
+	return gui.handleRefreshStagingPanel(false, -1)
+}
+
+// handleSplitHunk splits the selected hunk at the unchanged lines between its
+// changes, like `git add -p`'s split command. We only remember splits of
+// unstaged hunks: the line numbers of staged hunks shift as things get staged.
+func (gui *Gui) handleSplitHunk() error {
+	file := gui.getSelectedFile()
+	if file == nil {
+		return nil
+	}
+
+	return gui.withLBLActiveCheck(func(state *LblPanelState) error {
+		splitPoints := patch.HunkSplitPoints(state.GetDiff(), state.GetSelectedLineIdx())
+		if len(splitPoints) == 0 {
+			gui.raiseToast(gui.Tr.CannotSplitHunk)
+			return nil
+		}
+
+		if !state.SecondaryFocused {
+			gui.State.HunkSplits[file.Name] = utils.UnionInt(gui.State.HunkSplits[file.Name], splitPoints)
+		}
+
+		diff := patch.SplitHunksAt(state.GetDiff(), splitPoints)
+		state.State = lbl.NewState(diff, -1, state.State, gui.Log, gui.patchParserOpts("main"))
+
+		return gui.refreshAndFocusLblPanel(state)
+	})
+}
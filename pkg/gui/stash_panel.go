@@ -21,7 +21,7 @@ func (gui *Gui) stashRenderToMain() error {
 	if stashEntry == nil {
 		task = NewRenderStringTask(gui.Tr.NoStashEntries)
 	} else {
-		task = NewRunPtyTask(gui.Git.Stash.ShowStashEntryCmdObj(stashEntry.Index).GetCmd())
+		task = gui.newDiffTask(gui.Git.Stash.ShowStashEntryCmdObj(stashEntry.Index))
 	}
 
 	return gui.refreshMainViews(refreshMainOpts{
@@ -113,8 +113,10 @@ func (gui *Gui) handleStashDrop() error {
 		title:  gui.Tr.StashDrop,
 		prompt: gui.Tr.SureDropStashEntry,
 		handleConfirm: func() error {
-			gui.logAction(gui.Tr.Actions.Stash)
-			err := gui.Git.Stash.Drop(stashEntry.Index)
+			gui.logAction(gui.Tr.Actions.DropStash)
+			err := gui.journalDropStash(stashEntry.Index, stashEntry.Name, func() error {
+				return gui.Git.Stash.Drop(stashEntry.Index)
+			})
 			_ = gui.refreshSidePanels(refreshOptions{scope: []RefreshableView{STASH}})
 			if err != nil {
 				return gui.surfaceError(err)
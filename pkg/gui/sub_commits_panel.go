@@ -25,7 +25,7 @@ func (gui *Gui) subCommitsRenderToMain() error {
 	} else {
 		cmdObj := gui.Git.Commit.ShowCmdObj(commit.Sha, gui.State.Modes.Filtering.GetPath())
 
-		task = NewRunPtyTask(cmdObj.GetCmd())
+		task = gui.newDiffTask(cmdObj)
 	}
 
 	return gui.refreshMainViews(refreshMainOpts{
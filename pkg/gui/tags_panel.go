@@ -25,8 +25,12 @@ func (gui *Gui) tagsRenderToMain() error {
 	if tag == nil {
 		task = NewRenderStringTask("No tags")
 	} else {
-		cmdObj := gui.Git.Branch.GetGraphCmdObj(tag.Name)
-		task = NewRunCommandTask(cmdObj.GetCmd())
+		cmdObj, err := gui.Git.Branch.GetGraphCmdObj(tag.Name)
+		if err != nil {
+			task = NewRenderStringTask(err.Error())
+		} else {
+			task = NewRunCommandTask(cmdObj.GetCmd())
+		}
 	}
 
 	return gui.refreshMainViews(refreshMainOpts{
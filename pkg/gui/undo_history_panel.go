@@ -0,0 +1,433 @@
+package gui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/jesseduffield/lazygit/pkg/commands/git_commands"
+	"github.com/jesseduffield/lazygit/pkg/commands/loaders"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// The undo history lists everything that undoing and redoing would step
+// through, so that the user can see what they're in for and go several steps
+// at once.
+
+// undoStep is one step of undoing or redoing: either an entry in the operation
+// journal or a user action from the reflog
+type undoStep struct {
+	journalEntry *JournalEntry
+	reflogAction *reflogAction
+	// when the action happened, or zero if we don't know
+	unixTimestamp int64
+}
+
+// reflogActionsForHistory returns the reflog actions that successive undos (or
+// redos) would go through, in order
+func (gui *Gui) reflogActionsForHistory(undo bool) []*reflogAction {
+	actions := []*reflogAction{}
+	counters := map[*reflogAction]int{}
+	_ = gui.parseReflogForActions(func(counter int, reflogAction reflogAction) (bool, error) {
+		action := reflogAction
+		if undo {
+			if counter > 0 {
+				return false, nil
+			}
+			// we can't undo past the start of the rebase we're in
+			if action.kind == CURRENT_REBASE {
+				return true, nil
+			}
+		} else if counter < 1 {
+			// anything older than a user action that hasn't been undone can't be
+			// redone
+			return true, nil
+		}
+
+		actions = append(actions, &action)
+		counters[&action] = counter
+		return false, nil
+	})
+
+	if !undo {
+		// the action with the lowest counter is the one we undid most recently
+		sort.SliceStable(actions, func(i, j int) bool {
+			return counters[actions[i]] < counters[actions[j]]
+		})
+	}
+
+	return actions
+}
+
+func (gui *Gui) undoSteps() []*undoStep {
+	reflogTimes := gui.reflogTimes()
+	actions := gui.reflogActionsForHistory(true)
+	entries := gui.State.Journal.entries
+
+	steps := []*undoStep{}
+	entryIdx := len(entries) - 1
+	actionIdx := 0
+	for entryIdx >= 0 || actionIdx < len(actions) {
+		if actionIdx == len(actions) || (entryIdx >= 0 && gui.journalEntryIsMoreRecentThan(entries[entryIdx], *actions[actionIdx])) {
+			steps = append(steps, gui.journalUndoStep(entries[entryIdx]))
+			entryIdx--
+		} else {
+			steps = append(steps, reflogUndoStep(actions[actionIdx], reflogTimes))
+			actionIdx++
+		}
+	}
+
+	return steps
+}
+
+func (gui *Gui) redoSteps() []*undoStep {
+	reflogTimes := gui.reflogTimes()
+	actions := gui.reflogActionsForHistory(false)
+	undone := gui.State.Journal.undone
+
+	steps := []*undoStep{}
+	actionIdx := 0
+	for i := len(undone) - 1; i >= 0; i-- {
+		if undone[i].Kind != REFLOG_ACTION {
+			steps = append(steps, gui.journalUndoStep(undone[i]))
+		} else if actionIdx < len(actions) {
+			steps = append(steps, reflogUndoStep(actions[actionIdx], reflogTimes))
+			actionIdx++
+		}
+	}
+
+	// these we undid before this session began
+	for _, action := range actions[actionIdx:] {
+		steps = append(steps, reflogUndoStep(action, reflogTimes))
+	}
+
+	return steps
+}
+
+func (gui *Gui) journalUndoStep(entry *JournalEntry) *undoStep {
+	return &undoStep{journalEntry: entry, unixTimestamp: entry.Time.Unix()}
+}
+
+func reflogUndoStep(action *reflogAction, reflogTimes []int64) *undoStep {
+	step := &undoStep{reflogAction: action}
+	if action.reflogIdx < len(reflogTimes) {
+		step.unixTimestamp = reflogTimes[action.reflogIdx]
+	}
+	return step
+}
+
+func (gui *Gui) reflogTimes() []int64 {
+	times, err := gui.Git.Loaders.ReflogCommits.GetReflogTimes(gui.State.Modes.Filtering.GetPath())
+	if err != nil {
+		gui.Log.Error(err)
+		return nil
+	}
+	return times
+}
+
+var fullShaRegexp = regexp.MustCompile(`^[0-9a-f]{20,40}$`)
+
+// shortRef shortens SHAs but leaves branch names alone
+func shortRef(ref string) string {
+	if fullShaRegexp.MatchString(ref) {
+		return utils.ShortSha(ref)
+	}
+	return ref
+}
+
+func (gui *Gui) undoStepDisplayStrings(step *undoStep) []string {
+	description := ""
+	refs := ""
+	if step.journalEntry != nil {
+		entry := step.journalEntry
+		description = entry.Description
+		switch entry.Kind {
+		case DELETE_BRANCH, DROP_STASH:
+			refs = utils.ShortSha(entry.From)
+		case DISCARD, REBASE_STEP:
+			refs = fmt.Sprintf("%s → %s", utils.ShortSha(entry.From), utils.ShortSha(entry.To))
+		}
+	} else {
+		action := step.reflogAction
+		switch action.kind {
+		case CHECKOUT:
+			description = gui.Tr.ReflogCheckout
+		case COMMIT:
+			description = gui.Tr.ReflogCommit
+		case REBASE:
+			description = gui.Tr.ReflogRebase
+		}
+		refs = fmt.Sprintf("%s → %s", shortRef(action.from), shortRef(action.to))
+	}
+
+	timeAgo := ""
+	if step.unixTimestamp != 0 {
+		timeAgo = utils.UnixToTimeAgo(step.unixTimestamp)
+	}
+
+	return []string{description, style.FgYellow.Sprint(refs), style.FgBlue.Sprint(timeAgo)}
+}
+
+func (gui *Gui) handleCreateUndoHistoryMenu() error {
+	undoSteps := gui.undoSteps()
+	redoSteps := gui.redoSteps()
+	if len(undoSteps) == 0 && len(redoSteps) == 0 {
+		return gui.createErrorPanel(gui.Tr.NoUndoHistory)
+	}
+
+	menuItems := []*menuItem{}
+	// the further back a redo goes, the higher up the list it goes, so that
+	// the list reads from newest to oldest
+	for i := len(redoSteps) - 1; i >= 0; i-- {
+		steps := redoSteps[:i+1]
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: append(
+				[]string{style.FgCyan.Sprintf("%s %d", gui.Tr.RedoStepsLabel, i+1)},
+				gui.undoStepDisplayStrings(redoSteps[i])...,
+			),
+			onPress: func() error {
+				return gui.confirmUndoSteps(steps, false)
+			},
+		})
+	}
+	for i := range undoSteps {
+		steps := undoSteps[:i+1]
+		menuItems = append(menuItems, &menuItem{
+			displayStrings: append(
+				[]string{style.FgMagenta.Sprintf("%s %d", gui.Tr.UndoStepsLabel, i+1)},
+				gui.undoStepDisplayStrings(undoSteps[i])...,
+			),
+			onPress: func() error {
+				return gui.confirmUndoSteps(steps, true)
+			},
+		})
+	}
+
+	return gui.createMenu(gui.Tr.UndoHistoryTitle, menuItems, createMenuOptions{showCancel: true})
+}
+
+// undoTarget returns the commit that best shows where the steps will leave
+// us: where HEAD ends up if any of the steps move it, otherwise the working
+// tree snapshot of the last discard they put back
+func undoTarget(steps []*undoStep, undo bool) string {
+	for i := len(steps) - 1; i >= 0; i-- {
+		if action := steps[i].reflogAction; action != nil {
+			if undo {
+				return action.from
+			}
+			return action.to
+		}
+		if entry := steps[i].journalEntry; entry != nil && entry.Kind == REBASE_STEP && entry.To != "" {
+			if undo {
+				return entry.From
+			}
+			return entry.To
+		}
+	}
+
+	for i := len(steps) - 1; i >= 0; i-- {
+		if entry := steps[i].journalEntry; entry != nil && entry.Kind == DISCARD {
+			if undo {
+				return entry.From
+			}
+			return entry.To
+		}
+	}
+
+	return ""
+}
+
+// confirmUndoSteps shows the diff between HEAD and where the steps will leave
+// us in the main view, and asks the user whether to go ahead
+func (gui *Gui) confirmUndoSteps(steps []*undoStep, undo bool) error {
+	var task updateTask
+	if target := undoTarget(steps, undo); target != "" {
+		cmdObj := gui.OSCommand.Cmd.New(
+			fmt.Sprintf("git diff --submodule --no-ext-diff --color --stat -p HEAD %s", gui.OSCommand.Quote(target)),
+		)
+		task = gui.newDiffTask(cmdObj)
+	} else {
+		task = NewRenderStringTask(gui.Tr.NoUndoPreview)
+	}
+
+	if err := gui.refreshMainViews(refreshMainOpts{
+		main: &viewUpdateOpts{
+			title: gui.Tr.UndoPreviewTitle,
+			task:  task,
+		},
+	}); err != nil {
+		return err
+	}
+
+	prompt := gui.Tr.ConfirmUndoSteps
+	if !undo {
+		prompt = gui.Tr.ConfirmRedoSteps
+	}
+
+	return gui.ask(askOpts{
+		title:  gui.Tr.UndoHistoryTitle,
+		prompt: fmt.Sprintf(prompt, len(steps)),
+		handleConfirm: func() error {
+			return gui.runUndoSteps(steps, undo)
+		},
+	})
+}
+
+// checkUndoSteps refuses to run the steps if any of them would overwrite
+// changes in the working tree or the index. This has to happen before we stash
+// anything, while the working tree still has the changes in it. Each path, and
+// the index, is checked against the first step to touch it, because the steps
+// before that one leave it alone.
+func (gui *Gui) checkUndoSteps(steps []*undoStep, undo bool) error {
+	checked := map[string]bool{}
+	indexChecked := false
+	for _, step := range steps {
+		entry := step.journalEntry
+		if entry == nil {
+			// going back through the reflog resets the index, by which point
+			// we've stashed whatever was in it
+			indexChecked = true
+			continue
+		}
+		if !undo {
+			if err := gui.checkCanRedo(entry); err != nil {
+				return err
+			}
+		}
+
+		expected, paths := entry.expectedWorkingTree(undo)
+		uncheckedPaths := []string{}
+		for _, path := range paths {
+			if !checked[path] && !checked["."] {
+				uncheckedPaths = append(uncheckedPaths, path)
+			}
+		}
+		if err := gui.checkPathsUnchanged(entry, expected, uncheckedPaths); err != nil {
+			return err
+		}
+		for _, path := range paths {
+			checked[path] = true
+		}
+
+		if !indexChecked {
+			if err := gui.checkIndexUnchanged(entry, undo); err != nil {
+				return err
+			}
+		}
+		switch entry.Kind {
+		case STAGE, DISCARD, REBASE_STEP:
+			indexChecked = true
+		}
+	}
+	return nil
+}
+
+// runUndoSteps undoes or redoes each of the steps in turn. Going back through
+// the reflog means hard resets, so we stash any changes for the duration.
+func (gui *Gui) runUndoSteps(steps []*undoStep, undo bool) error {
+	if err := gui.checkUndoSteps(steps, undo); err != nil {
+		return gui.surfaceError(err)
+	}
+
+	status := gui.Tr.UndoingStatus
+	envVars := []string{"GIT_REFLOG_ACTION=[lazygit undo]"}
+	if undo {
+		gui.logAction(gui.Tr.Actions.Undo)
+	} else {
+		status = gui.Tr.RedoingStatus
+		envVars = []string{"GIT_REFLOG_ACTION=[lazygit redo]"}
+		gui.logAction(gui.Tr.Actions.Redo)
+	}
+
+	return gui.WithWaitingStatus(status, func() error {
+		stashed := false
+		var err error
+		for _, step := range steps {
+			if step.reflogAction != nil && !stashed && gui.hasTrackedOrStagedChanges() {
+				if err = gui.Git.Stash.Save(gui.Tr.StashPrefix + undoTarget(steps, undo)); err != nil {
+					break
+				}
+				stashed = true
+			}
+
+			if err = gui.runUndoStep(step, undo, envVars); err != nil {
+				break
+			}
+		}
+
+		// if a step failed we still put the changes back, so that they don't
+		// end up sitting in the stash without the user knowing
+		if stashed {
+			if popErr := gui.Git.Stash.Pop(0); popErr != nil {
+				popErr = fmt.Errorf(gui.Tr.UndoStashPopFailed, popErr)
+				if err == nil {
+					err = popErr
+				} else {
+					err = fmt.Errorf("%v\n\n%v", err, popErr)
+				}
+			}
+		}
+
+		gui.State.Panels.Commits.SelectedLineIdx = 0
+		gui.State.Panels.ReflogCommits.SelectedLineIdx = 0
+		if refreshErr := gui.refreshSidePanels(refreshOptions{mode: BLOCK_UI}); refreshErr != nil {
+			return refreshErr
+		}
+		return err
+	})
+}
+
+func (gui *Gui) runUndoStep(step *undoStep, undo bool, envVars []string) error {
+	if entry := step.journalEntry; entry != nil {
+		if undo {
+			if err := gui.undoJournalEntryUnchecked(entry); err != nil {
+				return err
+			}
+			gui.State.Journal.markUndone(entry)
+			return nil
+		}
+
+		if err := gui.redoJournalEntryUnchecked(entry); err != nil {
+			return err
+		}
+		gui.State.Journal.markRedone(entry)
+		entry.ReflogLength = gui.reflogLength()
+		return nil
+	}
+
+	action := step.reflogAction
+	ref := action.to
+	if undo {
+		ref = action.from
+	}
+
+	var err error
+	switch action.kind {
+	case CHECKOUT:
+		err = gui.Git.Branch.Checkout(ref, git_commands.CheckoutOptions{EnvVars: envVars})
+	case COMMIT, REBASE:
+		err = gui.Git.Commit.ResetToCommit(ref, "hard", envVars)
+	}
+	if err != nil {
+		return err
+	}
+
+	if undo {
+		gui.journalReflogUndo(*action)
+	} else {
+		gui.journalReflogRedo()
+	}
+	return nil
+}
+
+// hasTrackedOrStagedChanges asks git rather than going by the files panel,
+// because undoing earlier steps may have changed the working tree
+func (gui *Gui) hasTrackedOrStagedChanges() bool {
+	for _, file := range gui.Git.Loaders.Files.GetStatusFiles(loaders.GetStatusFileOptions{}) {
+		if file.Tracked || file.HasStagedChanges {
+			return true
+		}
+	}
+	return false
+}
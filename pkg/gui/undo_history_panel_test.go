@@ -0,0 +1,92 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUndoTarget(t *testing.T) {
+	type scenario struct {
+		testName string
+		steps    []*undoStep
+		undo     bool
+		expected string
+	}
+
+	discard := &JournalEntry{Kind: DISCARD, From: "before", To: "after"}
+	branch := &JournalEntry{Kind: DELETE_BRANCH, From: "tip"}
+
+	scenarios := []scenario{
+		{
+			testName: "no steps",
+			steps:    []*undoStep{},
+			undo:     true,
+			expected: "",
+		},
+		{
+			testName: "only journal entries that don't touch the working tree",
+			steps:    []*undoStep{{journalEntry: branch}},
+			undo:     true,
+			expected: "",
+		},
+		{
+			testName: "undoing a discard",
+			steps:    []*undoStep{{journalEntry: branch}, {journalEntry: discard}},
+			undo:     true,
+			expected: "before",
+		},
+		{
+			testName: "redoing a discard",
+			steps:    []*undoStep{{journalEntry: discard}},
+			undo:     false,
+			expected: "after",
+		},
+		{
+			testName: "the last reflog action wins over discards",
+			steps: []*undoStep{
+				{reflogAction: &reflogAction{kind: COMMIT, from: "a", to: "b"}},
+				{journalEntry: discard},
+				{reflogAction: &reflogAction{kind: CHECKOUT, from: "master", to: "feature"}},
+			},
+			undo:     true,
+			expected: "master",
+		},
+		{
+			testName: "undoing a step of a rebase",
+			steps: []*undoStep{
+				{journalEntry: discard},
+				{journalEntry: &JournalEntry{Kind: REBASE_STEP, From: "a", To: "b"}},
+			},
+			undo:     true,
+			expected: "a",
+		},
+		{
+			testName: "redoing a step of a rebase that stopped with conflicts",
+			steps:    []*undoStep{{journalEntry: &JournalEntry{Kind: REBASE_STEP, From: "a"}}},
+			undo:     false,
+			expected: "",
+		},
+		{
+			testName: "redoing reflog actions",
+			steps: []*undoStep{
+				{reflogAction: &reflogAction{kind: COMMIT, from: "a", to: "b"}},
+				{reflogAction: &reflogAction{kind: COMMIT, from: "b", to: "c"}},
+			},
+			undo:     false,
+			expected: "c",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			assert.Equal(t, s.expected, undoTarget(s.steps, s.undo))
+		})
+	}
+}
+
+func TestShortRef(t *testing.T) {
+	assert.Equal(t, "c3c4b66b", shortRef("c3c4b66b64c97ffeecde"))
+	assert.Equal(t, "feature/long-branch-name", shortRef("feature/long-branch-name"))
+}
@@ -28,6 +28,8 @@ type reflogAction struct {
 	kind ReflogActionKind
 	from string
 	to   string
+	// the index in the reflog of the entry the action came from
+	reflogIdx int
 }
 
 // Here we're going through the reflog and maintaining a counter that represents how many
@@ -35,7 +37,7 @@ type reflogAction struct {
 // what the counter is up to and the nature of the action.
 // If we find ourselves mid-rebase, we just return because undo/redo mid rebase
 // requires knowledge of previous TODO file states, which you can't just get from the reflog.
-// Though we might support this later, hence the use of the CURRENT_REBASE action kind.
+// The operation journal takes care of steps within a rebase instead (see withRebaseJournal).
 func (gui *Gui) parseReflogForActions(onUserAction func(counter int, action reflogAction) (bool, error)) error {
 	counter := 0
 	reflogCommits := gui.State.FilteredReflogCommits
@@ -57,15 +59,15 @@ func (gui *Gui) parseReflogForActions(onUserAction func(counter int, action refl
 			} else if ok, _ := utils.FindStringSubmatch(reflogCommit.Name, `^rebase -i \(abort\)|^rebase -i \(finish\)`); ok {
 				rebaseFinishCommitSha = reflogCommit.Sha
 			} else if ok, match := utils.FindStringSubmatch(reflogCommit.Name, `^checkout: moving from ([\S]+) to ([\S]+)`); ok {
-				action = &reflogAction{kind: CHECKOUT, from: match[1], to: match[2]}
+				action = &reflogAction{kind: CHECKOUT, from: match[1], to: match[2], reflogIdx: reflogCommitIdx}
 			} else if ok, _ := utils.FindStringSubmatch(reflogCommit.Name, `^commit|^reset: moving to|^pull`); ok {
-				action = &reflogAction{kind: COMMIT, from: prevCommitSha, to: reflogCommit.Sha}
+				action = &reflogAction{kind: COMMIT, from: prevCommitSha, to: reflogCommit.Sha, reflogIdx: reflogCommitIdx}
 			} else if ok, _ := utils.FindStringSubmatch(reflogCommit.Name, `^rebase -i \(start\)`); ok {
 				// if we're here then we must be currently inside an interactive rebase
-				action = &reflogAction{kind: CURRENT_REBASE, from: prevCommitSha}
+				action = &reflogAction{kind: CURRENT_REBASE, from: prevCommitSha, reflogIdx: reflogCommitIdx}
 			}
 		} else if ok, _ := utils.FindStringSubmatch(reflogCommit.Name, `^rebase -i \(start\)`); ok {
-			action = &reflogAction{kind: REBASE, from: prevCommitSha, to: rebaseFinishCommitSha}
+			action = &reflogAction{kind: REBASE, from: prevCommitSha, to: rebaseFinishCommitSha, reflogIdx: reflogCommitIdx}
 			rebaseFinishCommitSha = ""
 		}
 
@@ -100,12 +102,14 @@ func (gui *Gui) reflogUndo() error {
 		switch action.kind {
 		case COMMIT, REBASE:
 			gui.logAction(gui.Tr.Actions.Undo)
+			gui.journalReflogUndo(action)
 			return true, gui.handleHardResetWithAutoStash(action.from, handleHardResetWithAutoStashOptions{
 				EnvVars:       undoEnvVars,
 				WaitingStatus: undoingStatus,
 			})
 		case CHECKOUT:
 			gui.logAction(gui.Tr.Actions.Undo)
+			gui.journalReflogUndo(action)
 			return true, gui.handleCheckoutRef(action.from, handleCheckoutRefOptions{
 				EnvVars:       undoEnvVars,
 				WaitingStatus: undoingStatus,
@@ -138,12 +142,14 @@ func (gui *Gui) reflogRedo() error {
 		switch action.kind {
 		case COMMIT, REBASE:
 			gui.logAction(gui.Tr.Actions.Redo)
+			gui.journalReflogRedo()
 			return true, gui.handleHardResetWithAutoStash(action.to, handleHardResetWithAutoStashOptions{
 				EnvVars:       redoEnvVars,
 				WaitingStatus: redoingStatus,
 			})
 		case CHECKOUT:
 			gui.logAction(gui.Tr.Actions.Redo)
+			gui.journalReflogRedo()
 			return true, gui.handleCheckoutRef(action.to, handleCheckoutRefOptions{
 				EnvVars:       redoEnvVars,
 				WaitingStatus: redoingStatus,
@@ -32,19 +32,19 @@ const (
 	BISECT_INFO
 )
 
-func getScopeNames(scopes []RefreshableView) []string {
-	scopeNameMap := map[RefreshableView]string{
-		COMMITS:    "commits",
-		BRANCHES:   "branches",
-		FILES:      "files",
-		SUBMODULES: "submodules",
-		STASH:      "stash",
-		REFLOG:     "reflog",
-		TAGS:       "tags",
-		REMOTES:    "remotes",
-		STATUS:     "status",
-	}
+var scopeNameMap = map[RefreshableView]string{
+	COMMITS:    "commits",
+	BRANCHES:   "branches",
+	FILES:      "files",
+	SUBMODULES: "submodules",
+	STASH:      "stash",
+	REFLOG:     "reflog",
+	TAGS:       "tags",
+	REMOTES:    "remotes",
+	STATUS:     "status",
+}
 
+func getScopeNames(scopes []RefreshableView) []string {
 	scopeNames := make([]string, len(scopes))
 	for i, scope := range scopes {
 		scopeNames[i] = scopeNameMap[scope]
@@ -53,6 +53,30 @@ func getScopeNames(scopes []RefreshableView) []string {
 	return scopeNames
 }
 
+// getScopesFromNames is the reverse of getScopeNames, for when the user names
+// the scopes to refresh e.g. in their config
+func getScopesFromNames(names []string) ([]RefreshableView, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	nameScopeMap := map[string]RefreshableView{}
+	for scope, name := range scopeNameMap {
+		nameScopeMap[name] = scope
+	}
+
+	scopes := make([]RefreshableView, len(names))
+	for i, name := range names {
+		scope, ok := nameScopeMap[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown refresh scope '%s'", name)
+		}
+		scopes[i] = scope
+	}
+
+	return scopes, nil
+}
+
 func getModeName(mode RefreshMode) string {
 	switch mode {
 	case SYNC:
@@ -14,6 +14,9 @@ func (gui *Gui) handleCreateResetMenu() error {
 		nukeStr = fmt.Sprintf("%s (%s)", nukeStr, gui.Tr.LcAndResetSubmodules)
 	}
 
+	// these all act on every file, so that's what we'll put back on undo
+	wholeWorkingTree := []string{"."}
+
 	menuItems := []*menuItem{
 		{
 			displayStrings: []string{
@@ -22,7 +25,10 @@ func (gui *Gui) handleCreateResetMenu() error {
 			},
 			onPress: func() error {
 				gui.logAction(gui.Tr.Actions.NukeWorkingTree)
-				if err := gui.Git.WorkingTree.ResetAndClean(); err != nil {
+				err := gui.withWorkingTreeJournal(gui.Tr.Actions.NukeWorkingTree, wholeWorkingTree, func() error {
+					return gui.Git.WorkingTree.ResetAndClean()
+				})
+				if err != nil {
 					return gui.surfaceError(err)
 				}
 
@@ -36,7 +42,10 @@ func (gui *Gui) handleCreateResetMenu() error {
 			},
 			onPress: func() error {
 				gui.logAction(gui.Tr.Actions.DiscardUnstagedFileChanges)
-				if err := gui.Git.WorkingTree.DiscardAnyUnstagedFileChanges(); err != nil {
+				err := gui.withWorkingTreeJournal(gui.Tr.Actions.DiscardUnstagedFileChanges, wholeWorkingTree, func() error {
+					return gui.Git.WorkingTree.DiscardAnyUnstagedFileChanges()
+				})
+				if err != nil {
 					return gui.surfaceError(err)
 				}
 
@@ -50,7 +59,10 @@ func (gui *Gui) handleCreateResetMenu() error {
 			},
 			onPress: func() error {
 				gui.logAction(gui.Tr.Actions.RemoveUntrackedFiles)
-				if err := gui.Git.WorkingTree.RemoveUntrackedFiles(); err != nil {
+				err := gui.withWorkingTreeJournal(gui.Tr.Actions.RemoveUntrackedFiles, wholeWorkingTree, func() error {
+					return gui.Git.WorkingTree.RemoveUntrackedFiles()
+				})
+				if err != nil {
 					return gui.surfaceError(err)
 				}
 
@@ -78,7 +90,10 @@ func (gui *Gui) handleCreateResetMenu() error {
 			},
 			onPress: func() error {
 				gui.logAction(gui.Tr.Actions.MixedReset)
-				if err := gui.Git.WorkingTree.ResetMixed("HEAD"); err != nil {
+				err := gui.withIndexJournal(gui.Tr.Actions.MixedReset, func() error {
+					return gui.Git.WorkingTree.ResetMixed("HEAD")
+				})
+				if err != nil {
 					return gui.surfaceError(err)
 				}
 
@@ -92,7 +107,10 @@ func (gui *Gui) handleCreateResetMenu() error {
 			},
 			onPress: func() error {
 				gui.logAction(gui.Tr.Actions.HardReset)
-				if err := gui.Git.WorkingTree.ResetHard("HEAD"); err != nil {
+				err := gui.withWorkingTreeJournal(gui.Tr.Actions.HardReset, wholeWorkingTree, func() error {
+					return gui.Git.WorkingTree.ResetHard("HEAD")
+				})
+				if err != nil {
 					return gui.surfaceError(err)
 				}
 
@@ -105,6 +105,67 @@ type TranslationSet struct {
 	LcNavigateConflicts                 string
 	LcPickHunk                          string
 	LcPickAllHunks                      string
+	LcSelectLine                        string
+	LcPickLine                          string
+	LcResolveWithPickedLines            string
+	ToggleThreeWayMergeView             string
+	ThreeWayMergeTitle                  string
+	MergeBaseHeader                     string
+	MergeOursHeader                     string
+	MergeTheirsHeader                   string
+	MergeResultTitle                    string
+	LcEditMergeResult                   string
+	LcSaveMergeResult                   string
+	LcNewLine                           string
+	NoLinesPickedTitle                  string
+	NoLinesPickedPrompt                 string
+	LcAutoResolveTrivialConflicts       string
+	LcAutoResolveAllTrivialConflicts    string
+	ResolveConflictTitle                string
+	ConflictDeletedByUs                 string
+	ConflictDeletedByThem               string
+	ConflictBothDeleted                 string
+	ConflictAddedByUs                   string
+	ConflictAddedByThem                 string
+	ConflictBothAdded                   string
+	ConflictBothModified                string
+	LcKeepDeleted                       string
+	KeepDeletedDescription              string
+	LcKeepModified                      string
+	KeepModifiedDescription             string
+	LcKeepFile                          string
+	KeepFileDescription                 string
+	LcDeleteFile                        string
+	DeleteFileDescription               string
+	LcUseOurs                           string
+	UseOursDescription                  string
+	LcUseTheirs                         string
+	UseTheirsDescription                string
+	LcMarkAsResolved                    string
+	MarkAsResolvedDescription           string
+	LcUseOurSubmoduleCommit             string
+	UseOurSubmoduleCommitDescription    string
+	LcUseTheirSubmoduleCommit           string
+	UseTheirSubmoduleCommitDescription  string
+	LcUseCheckedOutSubmoduleCommit      string
+	UseCheckedOutCommitDescription      string
+	ConflictsResolvedByRerere           string
+	LcEnableRerere                      string
+	LcDisableRerere                     string
+	LcForgetRecordedResolution          string
+	LcListRecordedResolutions           string
+	RecordedResolutionsTitle            string
+	NoRecordedResolutions               string
+	LcRecordedResolutionResolved        string
+	LcRecordedResolutionUnresolved      string
+	DeleteRecordedResolution            string
+	DeleteRecordedResolutionPrompt      string
+	ForgetRecordedResolution            string
+	ForgetRecordedResolutionPrompt      string
+	AutoResolveTrivialConflicts         string
+	AutoResolveTrivialConflictsPrompt   string
+	AutoResolveIgnoringWhitespacePrompt string
+	AutoResolvedConflicts               string
 	LcUndo                              string
 	LcUndoReflog                        string
 	LcRedoReflog                        string
@@ -137,6 +198,12 @@ type TranslationSet struct {
 	IntroPopupMessage                   string
 	GitconfigParseErr                   string
 	LcEditFile                          string
+	LcEditHunk                          string
+	LcSplitHunk                         string
+	CannotSplitHunk                     string
+	EditHunkInstructions                string
+	EditHunkFailedTitle                 string
+	EditHunkFailedPrompt                string
 	LcOpenFile                          string
 	LcIgnoreFile                        string
 	LcRefreshFiles                      string
@@ -175,6 +242,7 @@ type TranslationSet struct {
 	LocalBranchesTitle                  string
 	SearchTitle                         string
 	TagsTitle                           string
+	PullRequestsTitle                   string
 	MenuTitle                           string
 	RemotesTitle                        string
 	CredentialsTitle                    string
@@ -279,6 +347,21 @@ type TranslationSet struct {
 	ViewPatchOptions                    string
 	PatchOptionsTitle                   string
 	NoPatchError                        string
+	LcSavePatchToFile                   string
+	LcCopyPatchToClipboard              string
+	LcLoadPatchFromFile                 string
+	LcApplyPatchToWorkingTree           string
+	LcApplyPatchToIndex                 string
+	LcApplyPatchAsNewCommit             string
+	SavePatchTitle                      string
+	OverwritePatchFileTitle             string
+	OverwritePatchFilePrompt            string
+	LoadPatchTitle                      string
+	PatchSavedToFile                    string
+	PatchCopiedToClipboard              string
+	NoDiffsInPatchFile                  string
+	NotAvailableForLoadedPatch          string
+	StagedChangesBeforePatchCommit      string
 	LcEnterFile                         string
 	ExitLineByLineMode                  string
 	EnterUpstream                       string
@@ -335,6 +418,29 @@ type TranslationSet struct {
 	LcPrevTab                           string
 	LcCantUndoWhileRebasing             string
 	LcCantRedoWhileRebasing             string
+	UndidAction                         string
+	RedidAction                         string
+	StashEntryNotFound                  string
+	CantRedoRebaseStep                  string
+	UndoStashPopFailed                  string
+	WorkingTreeChangedSinceAction       string
+	CantTellIfWorkingTreeChanged        string
+	IndexChangedSinceAction             string
+	CantSnapshotWorkingTree             string
+	LcConfirmSelection                  string
+	CustomCommandOutputCopied           string
+	LcViewUndoHistory                   string
+	UndoHistoryTitle                    string
+	NoUndoHistory                       string
+	UndoStepsLabel                      string
+	RedoStepsLabel                      string
+	ReflogCheckout                      string
+	ReflogCommit                        string
+	ReflogRebase                        string
+	UndoPreviewTitle                    string
+	NoUndoPreview                       string
+	ConfirmUndoSteps                    string
+	ConfirmRedoSteps                    string
 	MustStashWarning                    string
 	MustStashTitle                      string
 	ConfirmationTitle                   string
@@ -429,6 +535,9 @@ type TranslationSet struct {
 	ToggleWhitespaceInDiffView          string
 	IgnoringWhitespaceInDiffView        string
 	ShowingWhitespaceInDiffView         string
+	ToggleSplitDiffView                 string
+	ShowingSplitDiffView                string
+	ShowingUnifiedDiffView              string
 	IncreaseContextInDiffView           string
 	DecreaseContextInDiffView           string
 	CreatePullRequestOptions            string
@@ -454,6 +563,29 @@ type TranslationSet struct {
 	LcOpenCommitInBrowser               string
 	LcViewBisectOptions                 string
 	ConfirmRevertCommit                 string
+	UserConfigReloadError               string
+	NoPullRequestRefs                   string
+	NoPullRequests                      string
+	NoFetchedPullRequests               string
+	LcCheckoutPullRequest               string
+	LcDiffPullRequest                   string
+	LcFetchPullRequests                 string
+	FetchingPullRequestsStatus          string
+	PullRequestAuthor                   string
+	PullRequestReview                   string
+	PullRequestCI                       string
+	PullRequestDraft                    string
+	LcOpenInBrowserMenu                 string
+	OpenInBrowserTitle                  string
+	BrowseFile                          string
+	BrowseLine                          string
+	BrowseLines                         string
+	BrowseBranch                        string
+	BrowseTag                           string
+	BrowseCompare                       string
+	LcOpenLinkedIssue                   string
+	LinkedIssuesTitle                   string
+	NoLinkedIssues                      string
 	Actions                             Actions
 	Bisect                              Bisect
 }
@@ -563,6 +695,25 @@ type Actions struct {
 	ResetBisect                       string
 	BisectSkip                        string
 	BisectMark                        string
+	ResolveTrivialConflicts           string
+	ResolveConflict                   string
+	ToggleRerere                      string
+	ForgetRecordedResolution          string
+	DeleteRecordedResolution          string
+	StageRerereResolvedFiles          string
+	SavePatchToFile                   string
+	CopyPatchToClipboard              string
+	LoadPatchFromFile                 string
+	ApplyPatchToWorkingTree           string
+	ApplyPatchToIndex                 string
+	ApplyPatchAsNewCommit             string
+	EditHunk                          string
+	DropStash                         string
+	UpdateRebaseTodo                  string
+	CheckoutPullRequest               string
+	FetchPullRequests                 string
+	OpenInBrowser                     string
+	OpenLinkedIssue                   string
 }
 
 const englishIntroPopupMessage = `
@@ -678,9 +829,70 @@ func EnglishTranslationSet() TranslationSet {
 		LcNavigateConflicts:                 "navigate conflicts",
 		LcPickHunk:                          "pick hunk",
 		LcPickAllHunks:                      "pick all hunks",
+		LcSelectLine:                        "select line",
+		LcPickLine:                          "pick line",
+		LcResolveWithPickedLines:            "resolve conflict with picked lines",
+		ToggleThreeWayMergeView:             "toggle three-way merge view",
+		ThreeWayMergeTitle:                  "Base / Ours / Theirs",
+		MergeBaseHeader:                     "Base",
+		MergeOursHeader:                     "Ours",
+		MergeTheirsHeader:                   "Theirs",
+		MergeResultTitle:                    "Merge result",
+		LcEditMergeResult:                   "edit result",
+		LcSaveMergeResult:                   "save result",
+		LcNewLine:                           "new line",
+		NoLinesPickedTitle:                  "No lines picked",
+		NoLinesPickedPrompt:                 "You haven't picked any lines, so resolving the conflict will remove both sides of it. Continue?",
+		LcAutoResolveTrivialConflicts:       "auto-resolve trivial conflicts",
+		LcAutoResolveAllTrivialConflicts:    "auto-resolve trivial conflicts in all conflicted files",
+		ResolveConflictTitle:                "Resolve conflict: %s",
+		ConflictDeletedByUs:                 "deleted by us, modified by them",
+		ConflictDeletedByThem:               "modified by us, deleted by them",
+		ConflictBothDeleted:                 "deleted by both",
+		ConflictAddedByUs:                   "added by us",
+		ConflictAddedByThem:                 "added by them",
+		ConflictBothAdded:                   "added by both",
+		ConflictBothModified:                "modified by both",
+		LcKeepDeleted:                       "keep deleted",
+		KeepDeletedDescription:              "Remove the file, accepting the deletion",
+		LcKeepModified:                      "keep modified",
+		KeepModifiedDescription:             "Keep the modified version that's in the working tree",
+		LcKeepFile:                          "keep file",
+		KeepFileDescription:                 "Keep the file as it is in the working tree",
+		LcDeleteFile:                        "delete file",
+		DeleteFileDescription:               "Remove the file",
+		LcUseOurs:                           "use ours",
+		UseOursDescription:                  "Replace the file with our version (git checkout --ours)",
+		LcUseTheirs:                         "use theirs",
+		UseTheirsDescription:                "Replace the file with their version (git checkout --theirs)",
+		LcMarkAsResolved:                    "mark as resolved",
+		MarkAsResolvedDescription:           "Stage the file as it is in the working tree",
+		LcUseOurSubmoduleCommit:             "use our commit %s",
+		UseOurSubmoduleCommitDescription:    "Point the submodule at the commit from our side",
+		LcUseTheirSubmoduleCommit:           "use their commit %s",
+		UseTheirSubmoduleCommitDescription:  "Point the submodule at the commit from their side",
+		LcUseCheckedOutSubmoduleCommit:      "use checked out commit",
+		UseCheckedOutCommitDescription:      "Point the submodule at the commit currently checked out in it",
+		ConflictsResolvedByRerere:           "all merge conflicts resolved, some of them by reusing recorded resolutions (rerere). Continue?",
+		LcEnableRerere:                      "enable reuse of recorded resolutions (rerere)",
+		LcDisableRerere:                     "disable reuse of recorded resolutions (rerere)",
+		LcForgetRecordedResolution:          "forget recorded resolution for '%s'",
+		LcListRecordedResolutions:           "list recorded resolutions",
+		RecordedResolutionsTitle:            "Recorded resolutions",
+		NoRecordedResolutions:               "There are no recorded resolutions",
+		LcRecordedResolutionResolved:        "resolved",
+		LcRecordedResolutionUnresolved:      "conflict only",
+		DeleteRecordedResolution:            "Delete recorded resolution",
+		DeleteRecordedResolutionPrompt:      "Are you sure you want to delete this recorded resolution? Its conflict will no longer be resolved automatically.",
+		ForgetRecordedResolution:            "Forget recorded resolution",
+		ForgetRecordedResolutionPrompt:      "Are you sure you want to forget the recorded resolution for '%s'? The conflict markers will be brought back so that you can resolve it again.",
+		AutoResolveTrivialConflicts:         "Auto-resolve trivial conflicts",
+		AutoResolveTrivialConflictsPrompt:   "Resolve every conflict in the conflicted files where both sides made the same change or only one side changed anything?",
+		AutoResolveIgnoringWhitespacePrompt: "Resolve every conflict in the conflicted files where both sides made the same change, only one side changed anything, or the sides differ only in whitespace?",
+		AutoResolvedConflicts:               "Resolved %d trivial conflicts, %d remaining",
 		LcUndo:                              "undo",
-		LcUndoReflog:                        "undo (via reflog) (experimental)",
-		LcRedoReflog:                        "redo (via reflog) (experimental)",
+		LcUndoReflog:                        "undo (experimental)",
+		LcRedoReflog:                        "redo (experimental)",
 		LcPop:                               "pop",
 		LcDrop:                              "drop",
 		LcApply:                             "apply",
@@ -710,6 +922,12 @@ func EnglishTranslationSet() TranslationSet {
 		IntroPopupMessage:                   englishIntroPopupMessage,
 		GitconfigParseErr:                   `Gogit failed to parse your gitconfig file due to the presence of unquoted '\' characters. Removing these should fix the issue.`,
 		LcEditFile:                          `edit file`,
+		LcEditHunk:                          `edit hunk`,
+		LcSplitHunk:                         `split hunk at unchanged lines`,
+		CannotSplitHunk:                     "This hunk can't be split: there are no unchanged lines between its changes",
+		EditHunkInstructions:                "---\nTo remove '-' lines, make them ' ' lines (context).\nTo remove '+' lines, delete them.\nYou can add or change '+' lines freely.\nLines starting with # will be removed.\nIf you remove every change, nothing will be applied.",
+		EditHunkFailedTitle:                 "Edited hunk doesn't apply",
+		EditHunkFailedPrompt:                "Your edited hunk doesn't apply:\n\n%s\n\nEdit it again?",
 		LcOpenFile:                          `open file`,
 		LcIgnoreFile:                        `add to .gitignore`,
 		LcRefreshFiles:                      `refresh files`,
@@ -748,6 +966,7 @@ func EnglishTranslationSet() TranslationSet {
 		LocalBranchesTitle:                  "Branches Tab",
 		SearchTitle:                         "Search",
 		TagsTitle:                           "Tags Tab",
+		PullRequestsTitle:                   "Pull Requests Tab",
 		MenuTitle:                           "Menu",
 		RemotesTitle:                        "Remotes Tab",
 		CredentialsTitle:                    "Credentials",
@@ -853,6 +1072,21 @@ func EnglishTranslationSet() TranslationSet {
 		ViewPatchOptions:                    "view custom patch options",
 		PatchOptionsTitle:                   "Patch Options",
 		NoPatchError:                        "No patch created yet. To start building a patch, use 'space' on a commit file or enter to add specific lines",
+		LcSavePatchToFile:                   "save patch to file",
+		LcCopyPatchToClipboard:              "copy patch to clipboard",
+		LcLoadPatchFromFile:                 "load patch from file",
+		LcApplyPatchToWorkingTree:           "apply patch to working tree",
+		LcApplyPatchToIndex:                 "apply patch to index",
+		LcApplyPatchAsNewCommit:             "apply patch as new commit",
+		SavePatchTitle:                      "Save patch to:",
+		OverwritePatchFileTitle:             "Overwrite file",
+		OverwritePatchFilePrompt:            "'%s' already exists. Overwrite it?",
+		LoadPatchTitle:                      "Load patch from:",
+		PatchSavedToFile:                    "Patch saved to %s",
+		PatchCopiedToClipboard:              "Patch copied to clipboard",
+		NoDiffsInPatchFile:                  "No file diffs found in '%s'",
+		NotAvailableForLoadedPatch:          "This isn't possible for a patch loaded from a file",
+		StagedChangesBeforePatchCommit:      "You have staged changes. Commit or unstage them before applying the patch as a new commit",
 		LcEnterFile:                         "enter file to add selected lines to the patch (or toggle directory collapsed)",
 		ExitLineByLineMode:                  `exit line-by-line mode`,
 		EnterUpstream:                       `Enter upstream as '<remote> <branchname>'`,
@@ -907,8 +1141,31 @@ func EnglishTranslationSet() TranslationSet {
 		LcResetCherryPick:                   "reset cherry-picked (copied) commits selection",
 		LcNextTab:                           "next tab",
 		LcPrevTab:                           "previous tab",
-		LcCantUndoWhileRebasing:             "Can't undo while rebasing",
+		LcCantUndoWhileRebasing:             "Can't undo past this point while rebasing. To go back to before the rebase, abort it",
 		LcCantRedoWhileRebasing:             "Can't redo while rebasing",
+		UndidAction:                         "Undid: %s",
+		RedidAction:                         "Redid: %s",
+		StashEntryNotFound:                  "Could not find stash entry %s",
+		CantRedoRebaseStep:                  "Can't redo this step of the rebase because it stopped with conflicts. Continue the rebase again instead.",
+		UndoStashPopFailed:                  "Couldn't put back the changes that were stashed for the undo, so they are still in the top stash entry: %v",
+		WorkingTreeChangedSinceAction:       "Can't go back over '%s' because these files have changed since, and the changes would be lost. Commit, stash or discard them first:\n%s",
+		CantTellIfWorkingTreeChanged:        "Can't go back over this action because lazygit couldn't snapshot the working tree at the time, so it can't tell whether doing so would lose your changes",
+		IndexChangedSinceAction:             "Can't go back over '%s' because files have been staged or unstaged since, and that would be lost. Undo that first, or commit or stash your changes",
+		CantSnapshotWorkingTree:             "Couldn't snapshot the working tree, so '%s' can't be undone:\n%s",
+		LcConfirmSelection:                  "confirm selection",
+		CustomCommandOutputCopied:           "Command output copied to clipboard",
+		LcViewUndoHistory:                   "view undo history",
+		UndoHistoryTitle:                    "Undo history",
+		NoUndoHistory:                       "There is nothing to undo or redo",
+		UndoStepsLabel:                      "undo",
+		RedoStepsLabel:                      "redo",
+		ReflogCheckout:                      "Checkout",
+		ReflogCommit:                        "Commit",
+		ReflogRebase:                        "Rebase",
+		UndoPreviewTitle:                    "Undo preview",
+		NoUndoPreview:                       "These actions don't move HEAD or change the working tree, so there's no diff to show",
+		ConfirmUndoSteps:                    "Undo %d action(s)? The main view shows the difference between HEAD and where this will leave you. Any changes in your working tree will be stashed and reapplied afterwards.",
+		ConfirmRedoSteps:                    "Redo %d action(s)? The main view shows the difference between HEAD and where this will leave you. Any changes in your working tree will be stashed and reapplied afterwards.",
 		MustStashWarning:                    "Pulling a patch out into the index requires stashing and unstashing your changes. If something goes wrong, you'll be able to access your files from the stash. Continue?",
 		MustStashTitle:                      "Must stash",
 		ConfirmationTitle:                   "Confirmation Panel",
@@ -1003,6 +1260,9 @@ func EnglishTranslationSet() TranslationSet {
 		ToggleWhitespaceInDiffView:          "Toggle whether or not whitespace changes are shown in the diff view",
 		IgnoringWhitespaceInDiffView:        "Whitespace will be ignored in the diff view",
 		ShowingWhitespaceInDiffView:         "Whitespace will be shown in the diff view",
+		ToggleSplitDiffView:                 "Toggle between unified and side-by-side diffs",
+		ShowingSplitDiffView:                "Diffs will be shown side by side",
+		ShowingUnifiedDiffView:              "Diffs will be shown unified",
 		IncreaseContextInDiffView:           "Increase the size of the context shown around changes in the diff view",
 		DecreaseContextInDiffView:           "Decrease the size of the context shown around changes in the diff view",
 		CreatePullRequest:                   "Create pull request",
@@ -1028,6 +1288,29 @@ func EnglishTranslationSet() TranslationSet {
 		LcOpenCommitInBrowser:               "open commit in browser",
 		LcViewBisectOptions:                 "view bisect options",
 		ConfirmRevertCommit:                 "Are you sure you want to revert {{.selectedCommit}}?",
+		UserConfigReloadError:               "Could not reload your config, so the previous config is still in use:\n\n%s",
+		NoPullRequestRefs:                   "This git service doesn't keep pull requests in refs that we can fetch",
+		NoPullRequests:                      "No pull requests",
+		NoFetchedPullRequests:               "No pull requests fetched. Press '%s' to fetch them from %s",
+		LcCheckoutPullRequest:               "check out pull request",
+		LcDiffPullRequest:                   "diff pull request against its target branch",
+		LcFetchPullRequests:                 "fetch pull requests",
+		FetchingPullRequestsStatus:          "fetching pull requests",
+		PullRequestAuthor:                   "Author",
+		PullRequestReview:                   "Review",
+		PullRequestCI:                       "CI",
+		PullRequestDraft:                    "Draft",
+		LcOpenInBrowserMenu:                 "open in browser",
+		OpenInBrowserTitle:                  "Open in browser",
+		BrowseFile:                          "file at %s",
+		BrowseLine:                          "line %d at %s",
+		BrowseLines:                         "lines %d-%d at %s",
+		BrowseBranch:                        "branch %s",
+		BrowseTag:                           "tag %s",
+		BrowseCompare:                       "compare %s...%s",
+		LcOpenLinkedIssue:                   "open linked issue",
+		LinkedIssuesTitle:                   "Linked issues",
+		NoLinkedIssues:                      "This commit's message doesn't link to anything",
 		Actions: Actions{
 			// TODO: combine this with the original keybinding descriptions (those are all in lowercase atm)
 			CheckoutCommit:                    "Checkout commit",
@@ -1119,6 +1402,25 @@ func EnglishTranslationSet() TranslationSet {
 			ResetBisect:                       "Reset bisect",
 			BisectSkip:                        "Bisect skip",
 			BisectMark:                        "Bisect mark",
+			ResolveTrivialConflicts:           "Resolve trivial merge conflicts",
+			ResolveConflict:                   "Resolve conflict",
+			ToggleRerere:                      "Toggle rerere",
+			ForgetRecordedResolution:          "Forget recorded resolution",
+			DeleteRecordedResolution:          "Delete recorded resolution",
+			StageRerereResolvedFiles:          "Stage files resolved by rerere",
+			SavePatchToFile:                   "Save patch to file",
+			CopyPatchToClipboard:              "Copy patch to clipboard",
+			LoadPatchFromFile:                 "Load patch from file",
+			ApplyPatchToWorkingTree:           "Apply patch to working tree",
+			ApplyPatchToIndex:                 "Apply patch to index",
+			ApplyPatchAsNewCommit:             "Apply patch as new commit",
+			EditHunk:                          "Edit hunk",
+			DropStash:                         "Drop stash",
+			UpdateRebaseTodo:                  "Update rebase TODO",
+			CheckoutPullRequest:               "Checkout pull request",
+			FetchPullRequests:                 "Fetch pull requests",
+			OpenInBrowser:                     "Open in browser",
+			OpenLinkedIssue:                   "Open linked issue",
 		},
 		Bisect: Bisect{
 			Mark:                        "mark %s as %s",
@@ -0,0 +1,140 @@
+package links
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// This package finds the links in text like commit messages: URLs, and
+// references to issues which the user has told us how to link to, e.g. PROJ-123
+// for a Jira project.
+
+// LinkStyle goes on top of the style of the text around a link
+var LinkStyle = style.FgBlue.SetUnderline()
+
+// trailing punctuation is more likely to belong to the sentence than the URL
+var urlRegexp = regexp.MustCompile("https?://[^\\s<>\"'`]*[^\\s<>\"'`.,;:!?)\\]}]")
+
+// Link is a link found in some text, with the byte range it takes up there
+type Link struct {
+	Text  string
+	URL   string
+	Start int
+	End   int
+}
+
+type issueTracker struct {
+	regex       *regexp.Regexp
+	urlTemplate string
+}
+
+type Linkifier struct {
+	issueTrackers []issueTracker
+	repoURL       string
+}
+
+// NewLinkifier returns a linkifier for URLs and for references to the given
+// issue trackers' issues. If we don't know the repo's URL, we leave out any
+// issue trackers that need it.
+func NewLinkifier(issueTrackerConfigs []config.IssueTrackerConfig, repoURL string) (*Linkifier, error) {
+	issueTrackers := []issueTracker{}
+	for _, issueTrackerConfig := range issueTrackerConfigs {
+		regex, err := regexp.Compile(issueTrackerConfig.Pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if repoURL == "" && strings.Contains(issueTrackerConfig.URL, "{{.RepoURL}}") {
+			continue
+		}
+
+		issueTrackers = append(issueTrackers, issueTracker{regex: regex, urlTemplate: issueTrackerConfig.URL})
+	}
+
+	return &Linkifier{issueTrackers: issueTrackers, repoURL: repoURL}, nil
+}
+
+// Find returns the links in the text in the order they appear. Where links
+// overlap, e.g. because a URL contains something that looks like an issue
+// reference, the one that starts first wins, and of those, URLs win.
+func (self *Linkifier) Find(text string) []Link {
+	if self == nil {
+		return nil
+	}
+
+	found := []Link{}
+	for _, match := range urlRegexp.FindAllStringIndex(text, -1) {
+		url := text[match[0]:match[1]]
+		found = append(found, Link{Text: url, URL: url, Start: match[0], End: match[1]})
+	}
+
+	for _, issueTracker := range self.issueTrackers {
+		for _, match := range issueTracker.regex.FindAllStringSubmatchIndex(text, -1) {
+			args := map[string]string{
+				"Match":   text[match[0]:match[1]],
+				"RepoURL": self.repoURL,
+			}
+			for i, name := range issueTracker.regex.SubexpNames() {
+				if name != "" && match[2*i] >= 0 {
+					args[name] = text[match[2*i]:match[2*i+1]]
+				}
+			}
+
+			found = append(found, Link{
+				Text:  args["Match"],
+				URL:   utils.ResolvePlaceholderString(issueTracker.urlTemplate, args),
+				Start: match[0],
+				End:   match[1],
+			})
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Start < found[j].Start
+	})
+
+	result := []Link{}
+	end := 0
+	for _, link := range found {
+		if link.Start >= end && link.End > link.Start {
+			result = append(result, link)
+			end = link.End
+		}
+	}
+
+	return result
+}
+
+// Highlight returns the text in the given style, with its links highlighted
+func (self *Linkifier) Highlight(text string, textStyle style.TextStyle) string {
+	return Render(text, self.Find(text), textStyle)
+}
+
+// Render returns the text in the given style, with the given links, which
+// we've found in it, highlighted
+func Render(text string, found []Link, textStyle style.TextStyle) string {
+	if len(found) == 0 {
+		return textStyle.Sprint(text)
+	}
+
+	linkStyle := textStyle.MergeStyle(LinkStyle)
+	result := ""
+	offset := 0
+	for _, link := range found {
+		if link.Start > offset {
+			result += textStyle.Sprint(text[offset:link.Start])
+		}
+		result += linkStyle.Sprint(text[link.Start:link.End])
+		offset = link.End
+	}
+	if offset < len(text) {
+		result += textStyle.Sprint(text[offset:])
+	}
+
+	return result
+}
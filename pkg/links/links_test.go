@@ -0,0 +1,94 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/stretchr/testify/assert"
+)
+
+var testIssueTrackers = []config.IssueTrackerConfig{
+	{Pattern: `PROJ-\d+`, URL: "https://jira.work.com/browse/{{.Match}}"},
+	{Pattern: `#(?P<number>\d+)`, URL: "{{.RepoURL}}/issues/{{.number}}"},
+}
+
+func TestFind(t *testing.T) {
+	scenarios := []struct {
+		testName string
+		text     string
+		repoURL  string
+		expected []Link
+	}{
+		{
+			testName: "no links",
+			text:     "Fix the build",
+			repoURL:  "https://github.com/peter/calculator",
+			expected: []Link{},
+		},
+		{
+			testName: "issue references",
+			text:     "Fix PROJ-123 (see #456)",
+			repoURL:  "https://github.com/peter/calculator",
+			expected: []Link{
+				{Text: "PROJ-123", URL: "https://jira.work.com/browse/PROJ-123", Start: 4, End: 12},
+				{Text: "#456", URL: "https://github.com/peter/calculator/issues/456", Start: 18, End: 22},
+			},
+		},
+		{
+			testName: "URLs leave out trailing punctuation",
+			text:     "See https://example.com/docs?page=2, and (http://example.com/faq).",
+			expected: []Link{
+				{Text: "https://example.com/docs?page=2", URL: "https://example.com/docs?page=2", Start: 4, End: 35},
+				{Text: "http://example.com/faq", URL: "http://example.com/faq", Start: 42, End: 64},
+			},
+		},
+		{
+			testName: "a reference within a URL is part of the URL",
+			text:     "https://example.com/notes#123",
+			repoURL:  "https://github.com/peter/calculator",
+			expected: []Link{
+				{Text: "https://example.com/notes#123", URL: "https://example.com/notes#123", Start: 0, End: 29},
+			},
+		},
+		{
+			testName: "issue trackers that need the repo's URL are left out without it",
+			text:     "PROJ-1 #2",
+			expected: []Link{
+				{Text: "PROJ-1", URL: "https://jira.work.com/browse/PROJ-1", Start: 0, End: 6},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			linkifier, err := NewLinkifier(testIssueTrackers, s.repoURL)
+			assert.NoError(t, err)
+			assert.Equal(t, s.expected, linkifier.Find(s.text))
+		})
+	}
+}
+
+func TestNewLinkifierRejectsInvalidPatterns(t *testing.T) {
+	_, err := NewLinkifier([]config.IssueTrackerConfig{{Pattern: `PROJ-(\d+`, URL: "{{.Match}}"}}, "")
+	assert.Error(t, err)
+}
+
+func TestNilLinkifierFindsNothing(t *testing.T) {
+	var linkifier *Linkifier
+	assert.Empty(t, linkifier.Find("https://example.com"))
+}
+
+func TestHighlight(t *testing.T) {
+	linkifier, err := NewLinkifier(testIssueTrackers, "")
+	assert.NoError(t, err)
+
+	linkStyle := style.FgRed.MergeStyle(LinkStyle)
+	assert.Equal(
+		t,
+		style.FgRed.Sprint("Fix ")+linkStyle.Sprint("PROJ-1")+style.FgRed.Sprint(" and ")+linkStyle.Sprint("PROJ-2"),
+		linkifier.Highlight("Fix PROJ-1 and PROJ-2", style.FgRed),
+	)
+	assert.Equal(t, style.FgRed.Sprint("Fix the build"), linkifier.Highlight("Fix the build", style.FgRed))
+}
@@ -0,0 +1,167 @@
+package syntax
+
+var cStyleComments = [][2]string{{"/*", "*/"}}
+
+var languages = []*Language{
+	{
+		Name:             "go",
+		Extensions:       []string{".go"},
+		LineComments:     []string{"//"},
+		BlockComments:    cStyleComments,
+		Strings:          []string{`"`, "'", "`"},
+		MultiLineStrings: []string{"`"},
+		Keywords: []string{
+			"break", "case", "chan", "const", "continue", "default", "defer", "else",
+			"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+			"map", "package", "range", "return", "select", "struct", "switch", "type",
+			"var", "nil", "true", "false",
+		},
+	},
+	{
+		Name:             "javascript",
+		Extensions:       []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx"},
+		LineComments:     []string{"//"},
+		BlockComments:    cStyleComments,
+		Strings:          []string{`"`, "'", "`"},
+		MultiLineStrings: []string{"`"},
+		Keywords: []string{
+			"async", "await", "break", "case", "catch", "class", "const", "continue",
+			"default", "delete", "do", "else", "enum", "export", "extends", "false",
+			"finally", "for", "from", "function", "if", "implements", "import", "in",
+			"instanceof", "interface", "let", "new", "null", "of", "private",
+			"protected", "public", "readonly", "return", "static", "super", "switch",
+			"this", "throw", "true", "try", "type", "typeof", "undefined", "var",
+			"void", "while", "yield",
+		},
+	},
+	{
+		Name:             "python",
+		Extensions:       []string{".py"},
+		LineComments:     []string{"#"},
+		Strings:          []string{`"`, "'", `"""`, "'''"},
+		MultiLineStrings: []string{`"""`, "'''"},
+		Keywords: []string{
+			"and", "as", "assert", "async", "await", "break", "class", "continue",
+			"def", "del", "elif", "else", "except", "False", "finally", "for", "from",
+			"global", "if", "import", "in", "is", "lambda", "None", "nonlocal", "not",
+			"or", "pass", "raise", "return", "True", "try", "while", "with", "yield",
+		},
+	},
+	{
+		Name:          "ruby",
+		Extensions:    []string{".rb", ".rake", ".gemspec"},
+		FileNames:     []string{"Gemfile", "Rakefile"},
+		LineComments:  []string{"#"},
+		Strings:       []string{`"`, "'"},
+		BlockComments: [][2]string{{"=begin", "=end"}},
+		Keywords: []string{
+			"alias", "and", "begin", "break", "case", "class", "def", "do",
+			"else", "elsif", "end", "ensure", "false", "for", "if", "in", "module",
+			"next", "nil", "not", "or", "redo", "rescue", "retry", "return", "self",
+			"super", "then", "true", "undef", "unless", "until", "when", "while", "yield",
+		},
+	},
+	{
+		Name:          "rust",
+		Extensions:    []string{".rs"},
+		LineComments:  []string{"//"},
+		BlockComments: cStyleComments,
+		Strings:       []string{`"`},
+		Keywords: []string{
+			"as", "async", "await", "break", "const", "continue", "crate", "dyn",
+			"else", "enum", "extern", "false", "fn", "for", "if", "impl", "in", "let",
+			"loop", "match", "mod", "move", "mut", "pub", "ref", "return", "self",
+			"Self", "static", "struct", "super", "trait", "true", "type", "unsafe",
+			"use", "where", "while",
+		},
+	},
+	{
+		Name:          "c",
+		Extensions:    []string{".c", ".h", ".cc", ".cpp", ".cxx", ".hpp", ".m", ".mm"},
+		LineComments:  []string{"//"},
+		BlockComments: cStyleComments,
+		Strings:       []string{`"`, "'"},
+		Keywords: []string{
+			"auto", "break", "case", "char", "class", "const", "continue", "default",
+			"delete", "do", "double", "else", "enum", "extern", "false", "float", "for",
+			"goto", "if", "include", "define", "inline", "int", "long", "namespace",
+			"new", "nullptr", "private", "protected", "public", "register", "return",
+			"short", "signed", "sizeof", "static", "struct", "switch", "template",
+			"this", "true", "typedef", "union", "unsigned", "using", "virtual", "void",
+			"volatile", "while",
+		},
+	},
+	{
+		Name:          "java",
+		Extensions:    []string{".java", ".kt", ".kts", ".scala", ".cs", ".swift", ".dart"},
+		LineComments:  []string{"//"},
+		BlockComments: cStyleComments,
+		Strings:       []string{`"`, "'"},
+		Keywords: []string{
+			"abstract", "break", "case", "catch", "class", "const", "continue",
+			"data", "default", "do", "else", "enum", "extends", "false", "final",
+			"finally", "for", "fun", "func", "if", "implements", "import", "in",
+			"interface", "let", "namespace", "new", "null", "object", "override",
+			"package", "private", "protected", "public", "return", "static", "super",
+			"switch", "this", "throw", "throws", "true", "try", "using", "val", "var",
+			"void", "when", "while",
+		},
+	},
+	{
+		Name:         "shell",
+		Extensions:   []string{".sh", ".bash", ".zsh", ".fish"},
+		FileNames:    []string{".bashrc", ".zshrc", ".profile"},
+		LineComments: []string{"#"},
+		Strings:      []string{`"`, "'"},
+		Keywords: []string{
+			"case", "do", "done", "elif", "else", "esac", "export", "fi", "for",
+			"function", "if", "in", "local", "return", "then", "until", "while",
+		},
+	},
+	{
+		Name:         "yaml",
+		Extensions:   []string{".yml", ".yaml", ".toml"},
+		LineComments: []string{"#"},
+		Strings:      []string{`"`, "'"},
+		Keywords:     []string{"true", "false", "null", "yes", "no"},
+	},
+	{
+		Name:       "json",
+		Extensions: []string{".json"},
+		Strings:    []string{`"`},
+		Keywords:   []string{"true", "false", "null"},
+	},
+	{
+		Name:         "makefile",
+		Extensions:   []string{".mk"},
+		FileNames:    []string{"Makefile", "makefile", "GNUmakefile", "Dockerfile"},
+		LineComments: []string{"#"},
+		Strings:      []string{`"`, "'"},
+		Keywords: []string{
+			"ifeq", "ifneq", "ifdef", "ifndef", "else", "endif", "include", "define",
+			"endef", "export", "FROM", "RUN", "CMD", "COPY", "ADD", "ENV", "ARG",
+			"WORKDIR", "EXPOSE", "ENTRYPOINT", "VOLUME", "USER", "LABEL",
+		},
+	},
+	{
+		Name:          "lua",
+		Extensions:    []string{".lua"},
+		LineComments:  []string{"--"},
+		BlockComments: [][2]string{{"--[[", "]]"}},
+		Strings:       []string{`"`, "'"},
+		Keywords: []string{
+			"and", "break", "do", "else", "elseif", "end", "false", "for", "function",
+			"goto", "if", "in", "local", "nil", "not", "or", "repeat", "return", "then",
+			"true", "until", "while",
+		},
+	},
+}
+
+func init() {
+	for _, language := range languages {
+		language.keywordSet = map[string]bool{}
+		for _, keyword := range language.Keywords {
+			language.keywordSet[keyword] = true
+		}
+	}
+}
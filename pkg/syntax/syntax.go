@@ -0,0 +1,201 @@
+package syntax
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/gui/style"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// This package does just enough lexing to colour keywords, strings, comments
+// and numbers. It works one line at a time because that's how we get to see
+// the lines of a diff, but it carries state over from one line to the next so
+// that comments and strings which span several lines are still coloured.
+
+type TokenKind int
+
+const (
+	KEYWORD TokenKind = iota
+	STRING
+	COMMENT
+	NUMBER
+)
+
+// Token is a range of bytes within a line
+type Token struct {
+	Kind  TokenKind
+	Start int
+	End   int
+}
+
+// State tells us whether a line starts in the middle of a comment or string,
+// and if so, what will end it
+type State struct {
+	closer string
+	kind   TokenKind
+}
+
+type Language struct {
+	Name string
+
+	Extensions []string
+	// for files like 'Makefile' that have no extension
+	FileNames []string
+
+	LineComments  []string
+	BlockComments [][2]string
+	// string delimiters, where the opening and closing delimiters are the same
+	Strings []string
+	// the subset of Strings that may span several lines
+	MultiLineStrings []string
+	Keywords         []string
+
+	keywordSet map[string]bool
+}
+
+// Style returns the colour we use for the given kind of token
+func (kind TokenKind) Style() style.TextStyle {
+	switch kind {
+	case KEYWORD:
+		return style.FgMagenta
+	case STRING:
+		return style.FgYellow
+	case COMMENT:
+		return style.FgBlackLighter
+	default:
+		return style.FgCyan
+	}
+}
+
+// LanguageForPath returns the language of the file at the given path, or nil if
+// we don't know how to highlight it
+func LanguageForPath(path string) *Language {
+	base := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(base))
+
+	for _, language := range languages {
+		for _, fileName := range language.FileNames {
+			if base == fileName {
+				return language
+			}
+		}
+		if ext == "" {
+			continue
+		}
+		for _, extension := range language.Extensions {
+			if ext == extension {
+				return language
+			}
+		}
+	}
+
+	return nil
+}
+
+// HighlightLine returns the tokens of the line, given the state that the
+// previous line left us in, along with the state that this line leaves us in
+func (self *Language) HighlightLine(line string, state State) ([]Token, State) {
+	tokens := []Token{}
+	position := 0
+
+	if state.closer != "" {
+		end, closed := findCloser(line, 0, state.closer, state.kind == STRING)
+		tokens = append(tokens, Token{Kind: state.kind, Start: 0, End: end})
+		if !closed {
+			return tokens, state
+		}
+		position = end
+		state = State{}
+	}
+
+	for position < len(line) {
+		if delimiters, ok := matchingBlockComment(line[position:], self.BlockComments); ok {
+			end, closed := findCloser(line, position+len(delimiters[0]), delimiters[1], false)
+			tokens = append(tokens, Token{Kind: COMMENT, Start: position, End: end})
+			if !closed {
+				return tokens, State{closer: delimiters[1], kind: COMMENT}
+			}
+			position = end
+			continue
+		}
+
+		if matchingPrefix(line[position:], self.LineComments) != "" {
+			tokens = append(tokens, Token{Kind: COMMENT, Start: position, End: len(line)})
+			break
+		}
+
+		if delimiter := matchingPrefix(line[position:], self.Strings); delimiter != "" {
+			end, closed := findCloser(line, position+len(delimiter), delimiter, true)
+			tokens = append(tokens, Token{Kind: STRING, Start: position, End: end})
+			if !closed && utils.IncludesString(self.MultiLineStrings, delimiter) {
+				return tokens, State{closer: delimiter, kind: STRING}
+			}
+			position = end
+			continue
+		}
+
+		char := line[position]
+		if isIdentifierChar(char) {
+			end := position + 1
+			for end < len(line) && isIdentifierChar(line[end]) {
+				end++
+			}
+			word := line[position:end]
+			if isDigit(word[0]) {
+				tokens = append(tokens, Token{Kind: NUMBER, Start: position, End: end})
+			} else if self.keywordSet[word] {
+				tokens = append(tokens, Token{Kind: KEYWORD, Start: position, End: end})
+			}
+			position = end
+			continue
+		}
+
+		position++
+	}
+
+	return tokens, State{}
+}
+
+// findCloser returns the index just past the closing delimiter, or the end of
+// the line if the delimiter doesn't appear, along with whether we found it
+func findCloser(line string, start int, closer string, allowEscapes bool) (int, bool) {
+	for i := start; i < len(line); i++ {
+		if allowEscapes && line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(line[i:], closer) {
+			return i + len(closer), true
+		}
+	}
+	return len(line), false
+}
+
+// matchingPrefix returns the longest of the candidates that str starts with
+func matchingPrefix(str string, candidates []string) string {
+	result := ""
+	for _, candidate := range candidates {
+		if len(candidate) > len(result) && strings.HasPrefix(str, candidate) {
+			result = candidate
+		}
+	}
+	return result
+}
+
+func matchingBlockComment(str string, blockComments [][2]string) ([2]string, bool) {
+	for _, delimiters := range blockComments {
+		if strings.HasPrefix(str, delimiters[0]) {
+			return delimiters, true
+		}
+	}
+	return [2]string{}, false
+}
+
+func isIdentifierChar(char byte) bool {
+	return char == '_' || isDigit(char) || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || char >= 0x80
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
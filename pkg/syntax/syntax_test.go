@@ -0,0 +1,102 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageForPath(t *testing.T) {
+	assert.Equal(t, "go", LanguageForPath("pkg/gui/gui.go").Name)
+	assert.Equal(t, "javascript", LanguageForPath("src/App.TSX").Name)
+	assert.Equal(t, "makefile", LanguageForPath("some/dir/Makefile").Name)
+	assert.Nil(t, LanguageForPath("README"))
+	assert.Nil(t, LanguageForPath("notes.unknown"))
+}
+
+func TestHighlightLine(t *testing.T) {
+	type tokenText struct {
+		kind TokenKind
+		text string
+	}
+
+	scenarios := []struct {
+		testName      string
+		path          string
+		lines         []string
+		expected      [][]tokenText
+		expectedState State
+	}{
+		{
+			testName: "keywords, strings, numbers and comments",
+			path:     "main.go",
+			lines:    []string{`func main() { x := "a \" b" + 42 // done`},
+			expected: [][]tokenText{{
+				{KEYWORD, "func"},
+				{STRING, `"a \" b"`},
+				{NUMBER, "42"},
+				{COMMENT, "// done"},
+			}},
+		},
+		{
+			testName: "identifiers containing keywords",
+			path:     "main.go",
+			lines:    []string{"format := iffy"},
+			expected: [][]tokenText{{}},
+		},
+		{
+			testName: "block comment spanning lines",
+			path:     "main.go",
+			lines:    []string{"a /* start", "middle", "end */ return"},
+			expected: [][]tokenText{
+				{{COMMENT, "/* start"}},
+				{{COMMENT, "middle"}},
+				{{COMMENT, "end */"}, {KEYWORD, "return"}},
+			},
+		},
+		{
+			testName: "multi-line string",
+			path:     "script.py",
+			lines:    []string{`x = """first`, `last""" if y`},
+			expected: [][]tokenText{
+				{{STRING, `"""first`}},
+				{{STRING, `last"""`}, {KEYWORD, "if"}},
+			},
+		},
+		{
+			testName:      "unterminated single-line string",
+			path:          "main.go",
+			lines:         []string{`"oops`},
+			expected:      [][]tokenText{{{STRING, `"oops`}}},
+			expectedState: State{},
+		},
+		{
+			testName: "lua block comment takes precedence over line comment",
+			path:     "init.lua",
+			lines:    []string{"--[[ a", "b ]] end"},
+			expected: [][]tokenText{
+				{{COMMENT, "--[[ a"}},
+				{{COMMENT, "b ]]"}, {KEYWORD, "end"}},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			language := LanguageForPath(s.path)
+			state := State{}
+			for i, line := range s.lines {
+				var tokens []Token
+				tokens, state = language.HighlightLine(line, state)
+
+				actual := []tokenText{}
+				for _, token := range tokens {
+					actual = append(actual, tokenText{token.Kind, line[token.Start:token.End]})
+				}
+				assert.EqualValues(t, s.expected[i], actual)
+			}
+			assert.EqualValues(t, s.expectedState, state)
+		})
+	}
+}
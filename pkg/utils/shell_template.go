@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// A shell template is a template for a command that we hand to the shell. Like
+// html/template does for HTML, we rewrite the template so that the output of
+// each action is escaped to suit where it appears: quoted if it appears on its
+// own, or escaped if it appears within quotes in the template, like
+// `git commit -m "{{.Message}}"`. That way a branch name with a `$(...)` in it
+// can't run anything. To output a value as-is, e.g. because it's a list of
+// flags, pass it through `raw`.
+//
+// Within quotes we escape values the way a POSIX shell expects.
+
+type shellContext int
+
+const (
+	shellUnquoted shellContext = iota
+	shellSingleQuoted
+	shellDoubleQuoted
+)
+
+// the names of the functions which we add to the end of each action's pipeline
+var shellEscaperNames = map[shellContext]string{
+	shellUnquoted:     "_shell_escape_unquoted",
+	shellSingleQuoted: "_shell_escape_single_quoted",
+	shellDoubleQuoted: "_shell_escape_double_quoted",
+}
+
+// RawShellValue is a value which a shell template outputs as-is, e.g. an editor
+// command which comes with its own arguments
+type RawShellValue string
+
+// StringList is a list of values, like the options ticked in a multi-select
+// prompt. A shell template quotes each of its values on its own, whereas
+// elsewhere it comes out with spaces between the values.
+type StringList []string
+
+func (self StringList) String() string {
+	return strings.Join(self, " ")
+}
+
+func shellTemplateFuncs(quote func(string) string) template.FuncMap {
+	return template.FuncMap{
+		"raw": func(value interface{}) RawShellValue {
+			return RawShellValue(fmt.Sprint(value))
+		},
+		// the result is already quoted, so we mustn't quote it again
+		"quote": func(value interface{}) RawShellValue {
+			return RawShellValue(quote(fmt.Sprint(value)))
+		},
+		shellEscaperNames[shellUnquoted]: func(value interface{}) string {
+			switch value := value.(type) {
+			case RawShellValue:
+				return string(value)
+			case StringList:
+				return shellEscapeList(value, quote)
+			case []string:
+				return shellEscapeList(value, quote)
+			case int, int64, bool:
+				return fmt.Sprint(value)
+			default:
+				return quote(fmt.Sprint(value))
+			}
+		},
+		shellEscaperNames[shellSingleQuoted]: func(value interface{}) string {
+			if raw, ok := value.(RawShellValue); ok {
+				return string(raw)
+			}
+			return strings.Replace(shellValueString(value), `'`, `'\''`, -1)
+		},
+		shellEscaperNames[shellDoubleQuoted]: func(value interface{}) string {
+			if raw, ok := value.(RawShellValue); ok {
+				return string(raw)
+			}
+			return strings.NewReplacer(
+				`\`, `\\`,
+				`"`, `\"`,
+				`$`, `\$`,
+				"`", "\\`",
+			).Replace(shellValueString(value))
+		},
+	}
+}
+
+func shellEscapeList(values []string, quote func(string) string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quote(value)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func shellValueString(value interface{}) string {
+	if elems, ok := value.([]string); ok {
+		return strings.Join(elems, " ")
+	}
+	return fmt.Sprint(value)
+}
+
+// ParseShellTemplate parses a shell template, which lets us find syntax errors
+// before we need to run the command
+func ParseShellTemplate(templateStr string, funcs template.FuncMap) (*template.Template, error) {
+	tmpl, err := template.New("template").
+		Funcs(TemplateFuncs).
+		Funcs(funcs).
+		Funcs(shellTemplateFuncs(nil)).
+		Parse(templateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// an empty template has no tree
+	if tmpl.Tree == nil {
+		return tmpl, nil
+	}
+
+	if _, err := escapeShellList(tmpl.Tree.Root, shellUnquoted); err != nil {
+		return nil, fmt.Errorf("template: %s: %v", templateStr, err)
+	}
+
+	return tmpl, nil
+}
+
+// ResolveShellTemplate resolves a shell template, quoting values with the given
+// function where they don't appear within quotes
+func ResolveShellTemplate(templateStr string, object interface{}, funcs template.FuncMap, quote func(string) string) (string, error) {
+	tmpl, err := ParseShellTemplate(templateStr, funcs)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(shellTemplateFuncs(quote)).Execute(&buf, object); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// escapeShellList adds the right escaper to each of the actions in the list,
+// given the context that the list starts in, and returns the context that it
+// ends in
+func escapeShellList(list *parse.ListNode, context shellContext) (shellContext, error) {
+	if list == nil {
+		return context, nil
+	}
+
+	var err error
+	for _, node := range list.Nodes {
+		switch node := node.(type) {
+		case *parse.TextNode:
+			context = shellContextAfter(context, node.Text)
+		case *parse.ActionNode:
+			// actions which declare variables don't output anything
+			if len(node.Pipe.Decl) > 0 {
+				continue
+			}
+			node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+				NodeType: parse.NodeCommand,
+				Pos:      node.Pos,
+				Args:     []parse.Node{parse.NewIdentifier(shellEscaperNames[context]).SetTree(nil).SetPos(node.Pos)},
+			})
+		case *parse.IfNode:
+			context, err = escapeShellBranch(&node.BranchNode, context, false)
+		case *parse.RangeNode:
+			context, err = escapeShellBranch(&node.BranchNode, context, true)
+		case *parse.WithNode:
+			context, err = escapeShellBranch(&node.BranchNode, context, false)
+		}
+		if err != nil {
+			return context, err
+		}
+	}
+
+	return context, nil
+}
+
+func escapeShellBranch(branch *parse.BranchNode, context shellContext, loop bool) (shellContext, error) {
+	listContext, err := escapeShellList(branch.List, context)
+	if err != nil {
+		return context, err
+	}
+
+	elseContext, err := escapeShellList(branch.ElseList, context)
+	if err != nil {
+		return context, err
+	}
+
+	// we can't know which branch will run or how many times a loop will, so we
+	// need them all to leave us where they found us
+	if listContext != elseContext || (loop && listContext != context) {
+		return context, errors.New("quotes must be closed within the branch or loop that opens them")
+	}
+
+	return listContext, nil
+}
+
+// shellContextAfter returns the context at the end of the given text, given the
+// context at its start
+func shellContextAfter(context shellContext, text []byte) shellContext {
+	for i := 0; i < len(text); i++ {
+		switch context {
+		case shellUnquoted:
+			switch text[i] {
+			case '\\':
+				i++
+			case '\'':
+				context = shellSingleQuoted
+			case '"':
+				context = shellDoubleQuoted
+			}
+		case shellSingleQuoted:
+			if text[i] == '\'' {
+				context = shellUnquoted
+			}
+		case shellDoubleQuoted:
+			switch text[i] {
+			case '\\':
+				i++
+			case '"':
+				context = shellUnquoted
+			}
+		}
+	}
+
+	return context
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testQuote(str string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`").Replace(str) + `"`
+}
+
+func TestResolveShellTemplate(t *testing.T) {
+	object := map[string]interface{}{
+		"Branch":  "feature/$(rm -rf ~)",
+		"Message": `it's "done"`,
+		"Files":   []string{"a.go", "my file.go"},
+		"Remotes": StringList{"origin", "my fork"},
+		"Flags":   "--force --verbose",
+		"Line":    12,
+		"Empty":   "",
+	}
+
+	scenarios := []struct {
+		testName    string
+		template    string
+		funcs       template.FuncMap
+		expected    string
+		expectedErr string
+	}{
+		{
+			testName: "unquoted",
+			template: "git checkout {{.Branch}}",
+			expected: `git checkout "feature/\$(rm -rf ~)"`,
+		},
+		{
+			testName: "within double quotes",
+			template: `git commit -m "{{.Message}}"`,
+			expected: `git commit -m "it's \"done\""`,
+		},
+		{
+			testName: "within single quotes",
+			template: `git commit -m '{{.Message}}'`,
+			expected: `git commit -m 'it'\''s "done"'`,
+		},
+		{
+			testName: "escaped quote doesn't open quotes",
+			template: `echo \" {{.Message}}`,
+			expected: `echo \" "it's \"done\""`,
+		},
+		{
+			testName: "list",
+			template: "git add -- {{.Files}}",
+			expected: `git add -- "a.go" "my file.go"`,
+		},
+		{
+			testName: "string list",
+			template: "git fetch --multiple {{.Remotes}}",
+			expected: `git fetch --multiple "origin" "my fork"`,
+		},
+		{
+			testName: "number",
+			template: "vim +{{.Line}}",
+			expected: "vim +12",
+		},
+		{
+			testName: "raw",
+			template: "git push {{raw .Flags}}",
+			expected: "git push --force --verbose",
+		},
+		{
+			testName: "quote isn't quoted again",
+			template: "git checkout {{quote .Branch}}",
+			expected: `git checkout "feature/\$(rm -rf ~)"`,
+		},
+		{
+			testName: "helpers",
+			template: `{{.Branch | trimPrefix "feature/" | slugify}} {{join ", " .Files}} {{.Empty | default "none"}}`,
+			expected: `"rm-rf" "a.go, my file.go" "none"`,
+		},
+		{
+			testName: "extra funcs",
+			template: "{{editor}} {{filename}}",
+			funcs: template.FuncMap{
+				"editor":   func() RawShellValue { return "code --wait" },
+				"filename": func() string { return "my file.go" },
+			},
+			expected: `code --wait "my file.go"`,
+		},
+		{
+			testName: "conditional and loop",
+			template: `git log{{if .Line}} -n {{.Line}}{{end}} -- {{range .Files}}"{{.}}" {{end}}`,
+			expected: `git log -n 12 -- "a.go" "my file.go" `,
+		},
+		{
+			testName: "variable declaration",
+			template: `{{$b := .Branch}}echo {{$b}}`,
+			expected: `echo "feature/\$(rm -rf ~)"`,
+		},
+		{
+			testName:    "quote left open in a branch",
+			template:    `echo {{if .Line}}"{{end}}{{.Branch}}`,
+			expectedErr: "quotes must be closed",
+		},
+		{
+			testName:    "syntax error",
+			template:    "echo {{.Branch",
+			expectedErr: "unclosed action",
+		},
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.testName, func(t *testing.T) {
+			result, err := ResolveShellTemplate(s.template, object, s.funcs, testQuote)
+			if s.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), s.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, s.expected, result)
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "fix-the-login-page", Slugify("Fix the login page!"))
+	assert.Equal(t, "feature-abc-123", Slugify("--feature/ABC 123--"))
+	assert.Equal(t, "", Slugify("!!!"))
+}
@@ -2,12 +2,63 @@ package utils
 
 import (
 	"bytes"
+	"reflect"
+	"regexp"
 	"strings"
 	"text/template"
 )
 
+// TemplateFuncs are helpers available to the templates in the user config, on
+// top of text/template's own. Their arguments are ordered so that the value
+// being worked on comes last, for the sake of pipelines like
+// `{{.Name | trimPrefix "feature/"}}`.
+var TemplateFuncs = template.FuncMap{
+	"join": func(separator string, elems []string) string {
+		return strings.Join(elems, separator)
+	},
+	"default": func(defaultValue interface{}, value interface{}) interface{} {
+		if isEmptyTemplateValue(value) {
+			return defaultValue
+		}
+		return value
+	},
+	"trimPrefix": func(prefix string, str string) string {
+		return strings.TrimPrefix(str, prefix)
+	},
+	"slugify": Slugify,
+}
+
+func isEmptyTemplateValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+var nonSlugCharsRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify turns e.g. "Fix the login page!" into "fix-the-login-page"
+func Slugify(str string) string {
+	return strings.Trim(nonSlugCharsRegexp.ReplaceAllString(strings.ToLower(str), "-"), "-")
+}
+
 func ResolveTemplate(templateStr string, object interface{}) (string, error) {
-	tmpl, err := template.New("template").Parse(templateStr)
+	return ResolveTemplateWithFuncs(templateStr, object, nil)
+}
+
+// ResolveTemplateWithFuncs is ResolveTemplate with extra functions available to
+// the template
+func ResolveTemplateWithFuncs(templateStr string, object interface{}, funcs template.FuncMap) (string, error) {
+	tmpl, err := ParseTemplate(templateStr, funcs)
 	if err != nil {
 		return "", err
 	}
@@ -20,6 +71,12 @@ func ResolveTemplate(templateStr string, object interface{}) (string, error) {
 	return buf.String(), nil
 }
 
+// ParseTemplate parses a template with TemplateFuncs and the given functions
+// available to it
+func ParseTemplate(templateStr string, funcs template.FuncMap) (*template.Template, error) {
+	return template.New("template").Funcs(TemplateFuncs).Funcs(funcs).Parse(templateStr)
+}
+
 // ResolvePlaceholderString populates a template with values
 func ResolvePlaceholderString(str string, arguments map[string]string) string {
 	for key, value := range arguments {
@@ -10,13 +10,23 @@ import (
 
 func main() {
 	if len(os.Args) < 2 {
-		log.Fatal("Please provide a command: one of 'generate', 'check'")
+		log.Fatal("Please provide a command: one of 'generate [config file]', 'check'")
 	}
 
 	command := os.Args[1]
 
 	switch command {
 	case "generate":
+		if len(os.Args) > 2 {
+			// generating cheatsheets for a user's config, which don't belong in our docs
+			dir, err := os.Getwd()
+			if err != nil {
+				log.Fatal(err)
+			}
+			cheatsheet.GenerateForConfig(dir, os.Args[2])
+			fmt.Printf("\nGenerated cheatsheets in %s\n", dir)
+			return
+		}
 		cheatsheet.Generate()
 		fmt.Printf("\nGenerated cheatsheets in %s\n", cheatsheet.GetDir())
 	case "check":